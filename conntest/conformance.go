@@ -0,0 +1,72 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conntest
+
+import (
+	"context"
+	"testing"
+
+	"periph.io/x/conn/v3"
+)
+
+// TestConn verifies that c follows the contracts documented in package conn.
+//
+// It is meant to be called from the unit tests of an implementation of
+// conn.Conn, including on real hardware:
+//   - String() is not empty;
+//   - Duplex() is a valid value;
+//   - Tx() accepts a nil read buffer in half duplex and equal buffer lengths
+//     in full duplex. c must be connected to a device where a transaction of
+//     zero bytes, or one zero byte in full duplex, is harmless;
+//   - Tx() rejects buffers of different lengths in full duplex;
+//   - Tx() rejects buffers larger than conn.Limits.MaxTxSize(), if
+//     implemented;
+//   - conn.ConnContext.TxContext(), if implemented, returns exactly ctx.Err()
+//     when ctx is already done.
+//
+// It reports failures with t.Errorf().
+func TestConn(t testing.TB, c conn.Conn) {
+	t.Helper()
+	if c.String() == "" {
+		t.Errorf("String() is empty")
+	}
+	d := c.Duplex()
+	switch d {
+	case conn.DuplexUnknown, conn.Half:
+		if err := c.Tx(nil, nil); err != nil {
+			t.Errorf("Tx(nil, nil) with %s: %v", d, err)
+		}
+	case conn.Full:
+		if err := c.Tx([]byte{0}, make([]byte, 1)); err != nil {
+			t.Errorf("Tx() with equal buffers in full duplex: %v", err)
+		}
+		if c.Tx([]byte{0}, make([]byte, 2)) == nil {
+			t.Errorf("Tx() with different buffer lengths in full duplex must fail")
+		}
+	default:
+		t.Errorf("Duplex() returned invalid %s", d)
+	}
+	if l, ok := c.(conn.Limits); ok {
+		if m := l.MaxTxSize(); m < 0 {
+			t.Errorf("MaxTxSize() returned %d", m)
+		} else if m > 0 {
+			w := make([]byte, m+1)
+			var r []byte
+			if d == conn.Full {
+				r = make([]byte, m+1)
+			}
+			if c.Tx(w, r) == nil {
+				t.Errorf("Tx() of %d bytes above MaxTxSize() %d must fail", m+1, m)
+			}
+		}
+	}
+	if cc, ok := c.(conn.ConnContext); ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := cc.TxContext(ctx, nil, nil); err != context.Canceled {
+			t.Errorf("TxContext() with a canceled context returned %v; expected context.Canceled", err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conntest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"periph.io/x/conn/v3"
+)
+
+func TestTestConn(t *testing.T) {
+	TestConn(t, &RegisterDevice{})
+	TestConn(t, &Playback{Ops: []IO{{W: []byte{0}, R: []byte{0}}}, D: conn.Full, DontPanic: true})
+}
+
+func TestTestConn_fail(t *testing.T) {
+	data := []struct {
+		c    conn.Conn
+		want string
+	}{
+		{&Discard{D: conn.Full}, "different buffer lengths"},
+		{&Discard{D: 5}, "invalid Duplex(5)"},
+		{&nonConformant{name: ""}, "String() is empty"},
+		{&nonConformant{name: "x", max: 4}, "above MaxTxSize() 4"},
+		{&nonConformant{name: "x", max: -1}, "MaxTxSize() returned -1"},
+		{&nonConformant{name: "x", err: conn.ErrTimeout}, "Tx(nil, nil) with Half: conn: timeout"},
+	}
+	for i, line := range data {
+		f := &fakeTB{}
+		TestConn(f, line.c)
+		if !strings.Contains(f.String(), line.want) {
+			t.Errorf("#%d: %q doesn't contain %q", i, f.String(), line.want)
+		}
+	}
+	f := &fakeTB{}
+	TestConn(f, &Playback{D: conn.Full, DontPanic: true})
+	if !strings.Contains(f.String(), "equal buffers in full duplex") {
+		t.Fatal(f.String())
+	}
+}
+
+//
+
+// fakeTB records the errors reported by the conformance tests.
+type fakeTB struct {
+	testing.TB
+	errs []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errs = append(f.errs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) String() string {
+	return strings.Join(f.errs, "\n")
+}
+
+// nonConformant is a half duplex conn.Conn that ignores its limits and
+// context.
+type nonConformant struct {
+	name string
+	max  int
+	err  error
+}
+
+func (n *nonConformant) String() string {
+	return n.name
+}
+
+func (n *nonConformant) Tx(w, r []byte) error {
+	return n.err
+}
+
+func (n *nonConformant) TxContext(ctx context.Context, w, r []byte) error {
+	return n.err
+}
+
+func (n *nonConformant) Duplex() conn.Duplex {
+	return conn.Half
+}
+
+func (n *nonConformant) MaxTxSize() int {
+	return n.max
+}
@@ -7,6 +7,7 @@ package conntest
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"sync"
@@ -44,20 +45,49 @@ func (r *RecordRaw) Tx(w, read []byte) error {
 	return err
 }
 
+// TxContext implements conn.ConnContext.
+func (r *RecordRaw) TxContext(ctx context.Context, w, read []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.Tx(w, read)
+}
+
 // Duplex implements conn.Conn.
 func (r *RecordRaw) Duplex() conn.Duplex {
 	return conn.Half
 }
 
 // IO registers the I/O that happened on either a real or fake connection.
+//
+// The fields after Err are only used by Playback to relax the matching of
+// the transactions; Record never sets them.
 type IO struct {
 	W []byte
 	R []byte
+	// Err is returned by Playback once W and R are processed. Use one of the
+	// well known conn errors like conn.ErrAddrNACK to simulate a failure.
+	Err error
+
+	// WMask is the mask applied to both W and the bytes written before they
+	// are compared. A 0x00 byte means "don't care". Bytes past the end of
+	// WMask are compared exactly. The length of the write must still match.
+	WMask []byte
+	// Min and Max are the number of consecutive transactions the op can
+	// match. When both are 0, it must match exactly once. Otherwise it
+	// matches at least Min and at most Max times; a negative Max means no
+	// limit and a Max of 0 means Max equals Min. For example Min: 0, Max: -1
+	// means "any number of times".
+	Min, Max int
+	// Group is set to the same non-zero value on consecutive ops that can
+	// match in any order.
+	Group int
 }
 
 // Record implements conn.Conn that records everything written to it.
 //
 // This can then be used to feed to Playback to do "replay" based unit tests.
+// Golden() keeps the recorded Ops in a file, see SaveGolden for the formats.
 type Record struct {
 	sync.Mutex
 	Conn conn.Conn // Conn can be nil if only writes are being recorded.
@@ -70,6 +100,16 @@ func (r *Record) String() string {
 
 // Tx implements conn.Conn.
 func (r *Record) Tx(w, read []byte) error {
+	return r.TxContext(context.Background(), w, read)
+}
+
+// TxContext implements conn.ConnContext.
+//
+// ctx is forwarded to Conn via conn.WithContext().
+func (r *Record) TxContext(ctx context.Context, w, read []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	io := IO{}
 	if len(w) != 0 {
 		io.W = make([]byte, len(w))
@@ -82,7 +122,7 @@ func (r *Record) Tx(w, read []byte) error {
 			return Errorf("conntest: read unsupported when no bus is connected")
 		}
 	} else {
-		if err := r.Conn.Tx(w, read); err != nil {
+		if err := conn.WithContext(r.Conn).TxContext(ctx, w, read); err != nil {
 			return err
 		}
 	}
@@ -107,6 +147,20 @@ func (r *Record) Duplex() conn.Duplex {
 // While "replay" type of unit tests are of limited value, they still present
 // an easy way to do basic code coverage.
 //
+// Set IO.Err to simulate a failing transaction.
+//
+// By default each transaction must match the next op exactly. Set IO.WMask
+// to ignore bits of the write, IO.Min and IO.Max to let an op match a
+// variable number of times and IO.Group to let ops match in any order. An op
+// that matched Min times gives way to the following ops as soon as they
+// match, so a status register polled until ready can be played back as a
+// "busy" op with Min set to the number of times the driver shall see it busy
+// and Max: -1, followed by a "ready" op with the same W and a different R.
+// Since R is not compared, a "busy" op with Min: 0 would never match.
+//
+// Count is the index of the first op that can still match. The matches are
+// reset when Ops or Count are modified between transactions.
+//
 // Set DontPanic to true to return an error instead of panicking, which is the
 // default.
 type Playback struct {
@@ -115,6 +169,12 @@ type Playback struct {
 	D         conn.Duplex
 	Count     int
 	DontPanic bool
+
+	// matched is the number of matches per op, valid while Ops starts at first
+	// and Count is still count.
+	matched []int
+	first   *IO
+	count   int
 }
 
 func (p *Playback) String() string {
@@ -125,8 +185,11 @@ func (p *Playback) String() string {
 func (p *Playback) Close() error {
 	p.Lock()
 	defer p.Unlock()
-	if len(p.Ops) != p.Count {
-		return errorf(p.DontPanic, "conntest: expected playback to be empty: I/O count %d; expected %d", p.Count, len(p.Ops))
+	p.sync()
+	for i := p.Count; i < len(p.Ops); i++ {
+		if p.numMatched(i) < p.Ops[i].min() {
+			return errorf(p.DontPanic, "conntest: expected playback to be empty: I/O count %d; expected %d", p.Count, len(p.Ops))
+		}
 	}
 	return nil
 }
@@ -135,18 +198,48 @@ func (p *Playback) Close() error {
 func (p *Playback) Tx(w, r []byte) error {
 	p.Lock()
 	defer p.Unlock()
-	if len(p.Ops) <= p.Count {
-		return errorf(p.DontPanic, "conntest: unexpected Tx() (count #%d) expecting []conntest.IO{W:%#v, R:%#v}", p.Count, w, r)
+	p.sync()
+	defer func() {
+		p.count = p.Count
+	}()
+	i := p.match(p.Count, w, r)
+	if i < 0 {
+		if len(p.Ops) <= p.Count {
+			return errorf(p.DontPanic, "conntest: unexpected Tx() (count #%d) expecting []conntest.IO{W:%#v, R:%#v}", p.Count, w, r)
+		}
+		if !p.Ops[p.Count].matchW(w) {
+			return errorf(p.DontPanic, "conntest: unexpected write (count #%d) %#v != %#v", p.Count, w, p.Ops[p.Count].W)
+		}
+		return errorf(p.DontPanic, "conntest: unexpected read buffer length (count #%d) %d != %d", p.Count, len(r), len(p.Ops[p.Count].R))
 	}
-	if !bytes.Equal(p.Ops[p.Count].W, w) {
-		return errorf(p.DontPanic, "conntest: unexpected write (count #%d) %#v != %#v", p.Count, w, p.Ops[p.Count].W)
+	copy(r, p.Ops[i].R)
+	p.matched[i]++
+	// Skip the ops before i and the segment if it cannot match anymore.
+	p.Count = i
+	for p.Count > 0 && p.Ops[i].Group != 0 && p.Ops[p.Count-1].Group == p.Ops[i].Group {
+		p.Count--
 	}
-	if len(p.Ops[p.Count].R) != len(r) {
-		return errorf(p.DontPanic, "conntest: unexpected read buffer length (count #%d) %d != %d", p.Count, len(r), len(p.Ops[p.Count].R))
+	end := p.segmentEnd(p.Count)
+	full := true
+	for j := p.Count; j < end; j++ {
+		if m := p.Ops[j].max(); m < 0 || p.numMatched(j) < m {
+			full = false
+		}
 	}
-	copy(r, p.Ops[p.Count].R)
-	p.Count++
-	return nil
+	if full {
+		p.Count = end
+	}
+	return p.Ops[i].Err
+}
+
+// TxContext implements conn.ConnContext.
+//
+// If ctx is done, ctx.Err() is returned and the current op is not consumed.
+func (p *Playback) TxContext(ctx context.Context, w, r []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.Tx(w, r)
 }
 
 // Duplex implements conn.Conn.
@@ -174,6 +267,14 @@ func (d *Discard) Tx(w, r []byte) error {
 	return nil
 }
 
+// TxContext implements conn.ConnContext.
+func (d *Discard) TxContext(ctx context.Context, w, r []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.Tx(w, r)
+}
+
 // Duplex implements conn.Conn.
 func (d *Discard) Duplex() conn.Duplex {
 	return d.D
@@ -196,6 +297,106 @@ type testErr struct {
 	error
 }
 
+// match returns the index of the op matching the transaction, starting at
+// the segment at c, or -1.
+//
+// A segment is a single op or a group. Once all the ops of a segment matched
+// their minimum, the following segments are tried first.
+func (p *Playback) match(c int, w, r []byte) int {
+	if c >= len(p.Ops) {
+		return -1
+	}
+	end := p.segmentEnd(c)
+	done := true
+	for i := c; i < end; i++ {
+		if p.numMatched(i) < p.Ops[i].min() {
+			done = false
+		}
+	}
+	if done {
+		if i := p.match(end, w, r); i >= 0 {
+			return i
+		}
+	}
+	for i := c; i < end; i++ {
+		if m := p.Ops[i].max(); (m < 0 || p.numMatched(i) < m) && p.Ops[i].matchW(w) && len(p.Ops[i].R) == len(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// segmentEnd returns the index following the segment starting at c.
+func (p *Playback) segmentEnd(c int) int {
+	end := c + 1
+	for g := p.Ops[c].Group; g != 0 && end < len(p.Ops) && p.Ops[end].Group == g; end++ {
+	}
+	return end
+}
+
+func (p *Playback) numMatched(i int) int {
+	return p.matched[i]
+}
+
+// sync resets matched if Ops was replaced or Count was modified since the
+// last transaction, and resizes it if ops were added or removed.
+func (p *Playback) sync() {
+	var first *IO
+	if len(p.Ops) != 0 {
+		first = &p.Ops[0]
+	}
+	if first != p.first || p.Count != p.count {
+		p.matched = nil
+		p.first = first
+		p.count = p.Count
+	}
+	if len(p.matched) < len(p.Ops) {
+		p.matched = append(p.matched, make([]int, len(p.Ops)-len(p.matched))...)
+	}
+	p.matched = p.matched[:len(p.Ops)]
+}
+
+func (io *IO) min() int {
+	if io.Min == 0 && io.Max == 0 {
+		return 1
+	}
+	return io.Min
+}
+
+func (io *IO) max() int {
+	if io.Max == 0 {
+		if io.Min == 0 {
+			return 1
+		}
+		return io.Min
+	}
+	return io.Max
+}
+
+// matchW returns true if w matches W with WMask applied.
+func (io *IO) matchW(w []byte) bool {
+	if io.WMask == nil {
+		return bytes.Equal(io.W, w)
+	}
+	if len(io.W) != len(w) {
+		return false
+	}
+	for i := range w {
+		m := byte(0xff)
+		if i < len(io.WMask) {
+			m = io.WMask[i]
+		}
+		if w[i]&m != io.W[i]&m {
+			return false
+		}
+	}
+	return true
+}
+
 var _ conn.Conn = &RecordRaw{}
 var _ conn.Conn = &Record{}
 var _ conn.Conn = &Playback{}
+var _ conn.ConnContext = &RecordRaw{}
+var _ conn.ConnContext = &Record{}
+var _ conn.ConnContext = &Playback{}
+var _ conn.ConnContext = &Discard{}
@@ -6,6 +6,8 @@ package conntest
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"testing"
 
 	"periph.io/x/conn/v3"
@@ -203,6 +205,36 @@ func TestRecord_Playback(t *testing.T) {
 	}
 }
 
+func TestRecord_Playback_TxContext(t *testing.T) {
+	p := &Playback{Ops: []IO{{W: []byte{10}, R: []byte{12}}}, DontPanic: true}
+	r := Record{Conn: p}
+	ctx, cancel := context.WithCancel(context.Background())
+	v := [1]byte{}
+	if err := r.TxContext(ctx, []byte{10}, v[:]); err != nil {
+		t.Fatal(err)
+	}
+	if v[0] != 12 || len(r.Ops) != 1 {
+		t.Fatal(v, r.Ops)
+	}
+	cancel()
+	if err := r.TxContext(ctx, []byte{10}, v[:]); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := p.TxContext(ctx, []byte{10}, v[:]); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if len(r.Ops) != 1 || p.Count != 1 {
+		t.Fatal(r.Ops, p.Count)
+	}
+	rr := RecordRaw{W: &bytes.Buffer{}}
+	if err := rr.TxContext(ctx, []byte{10}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := rr.TxContext(context.Background(), []byte{10}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestDiscard(t *testing.T) {
 	d := Discard{D: conn.Half}
 	if s := d.String(); s != "discard" {
@@ -217,4 +249,194 @@ func TestDiscard(t *testing.T) {
 	if err := d.Tx([]byte{0}, []byte{0}); err != nil {
 		t.Fatal(err)
 	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := d.TxContext(ctx, []byte{0}, []byte{0}); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := d.TxContext(ctx, []byte{0}, []byte{0}); err != context.Canceled {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayback_Err(t *testing.T) {
+	p := Playback{Ops: []IO{{W: []byte{1}, R: []byte{2}, Err: conn.ErrAddrNACK}}, DontPanic: true}
+	r := make([]byte, 1)
+	if err := p.Tx([]byte{1}, r); !errors.Is(err, conn.ErrAddrNACK) {
+		t.Fatal(err)
+	}
+	if r[0] != 2 {
+		t.Fatal(r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayback_WMask(t *testing.T) {
+	p := Playback{Ops: []IO{{W: []byte{0x80, 0x12, 3}, WMask: []byte{0xf0, 0}}}, DontPanic: true}
+	if p.Tx([]byte{0x80, 0x34}, nil) == nil {
+		t.Fatal("length mismatch")
+	}
+	if p.Tx([]byte{0x81, 0x34, 4}, nil) == nil {
+		t.Fatal("bytes past WMask are compared exactly")
+	}
+	if p.Tx([]byte{0x90, 0x34, 3}, nil) == nil {
+		t.Fatal("masked bits are compared")
+	}
+	if err := p.Tx([]byte{0x8f, 0x34, 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayback_poll(t *testing.T) {
+	ops := []IO{
+		{W: []byte{1}},
+		{W: []byte{0xf}, R: []byte{0x80}, Min: 2, Max: -1},
+		{W: []byte{0xf}, R: []byte{0}},
+		{W: []byte{2}, R: []byte{42}},
+	}
+	p := Playback{Ops: ops, DontPanic: true}
+	if err := p.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	r := make([]byte, 1)
+	polls := 0
+	for ; polls < 10; polls++ {
+		if err := p.Tx([]byte{0xf}, r); err != nil {
+			t.Fatal(err)
+		}
+		if r[0] == 0 {
+			break
+		}
+	}
+	if polls != 2 {
+		t.Fatal(polls)
+	}
+	if p.Count != 3 {
+		t.Fatal(p.Count)
+	}
+	if err := p.Tx([]byte{2}, r); err != nil || r[0] != 42 {
+		t.Fatal(err, r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayback_Min_Max(t *testing.T) {
+	p := Playback{Ops: []IO{{W: []byte{1}, Min: 1, Max: 2}, {W: []byte{2}, Min: 0, Max: 1}}, DontPanic: true}
+	if p.Close() == nil {
+		t.Fatal("Min not reached")
+	}
+	for i := 0; i < 2; i++ {
+		if err := p.Tx([]byte{1}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.Tx([]byte{1}, nil) == nil {
+		t.Fatal("Max reached")
+	}
+	// The optional op doesn't need to be consumed.
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Tx([]byte{2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if p.Tx([]byte{2}, nil) == nil {
+		t.Fatal("Ops consumed")
+	}
+}
+
+func TestPlayback_Min(t *testing.T) {
+	// Max defaults to Min.
+	p := Playback{Ops: []IO{{W: []byte{1}, Min: 2}}, DontPanic: true}
+	for i := 0; i < 2; i++ {
+		if err := p.Tx([]byte{1}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.Tx([]byte{1}, nil) == nil {
+		t.Fatal("Max reached")
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayback_rewind(t *testing.T) {
+	ops := []IO{{W: []byte{1}, Min: 1, Max: 2}, {W: []byte{2}}}
+	p := Playback{Ops: ops, DontPanic: true}
+	for _, w := range []byte{1, 1, 2} {
+		if err := p.Tx([]byte{w}, nil); err != nil {
+			t.Fatal(w, err)
+		}
+	}
+	p.Count = 0
+	if p.Close() == nil {
+		t.Fatal("matches not reset")
+	}
+	for _, w := range []byte{1, 1, 2} {
+		if err := p.Tx([]byte{w}, nil); err != nil {
+			t.Fatal(w, err)
+		}
+	}
+	// Replacing Ops also resets the matches.
+	p = Playback{Ops: ops, DontPanic: true}
+	if err := p.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	p.Ops = []IO{{W: []byte{1}}}
+	if err := p.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayback_Group(t *testing.T) {
+	ops := []IO{
+		{W: []byte{1}},
+		{W: []byte{2}, Group: 1},
+		{W: []byte{3}, Group: 1},
+		{W: []byte{4}, Group: 1, Min: 0, Max: -1},
+		{W: []byte{5}},
+	}
+	p := Playback{Ops: ops, DontPanic: true}
+	for _, w := range []byte{1, 3, 4, 4, 2} {
+		if err := p.Tx([]byte{w}, nil); err != nil {
+			t.Fatal(w, err)
+		}
+		if p.Count != 1 {
+			t.Fatal(w, p.Count)
+		}
+	}
+	if p.Tx([]byte{2}, nil) == nil {
+		t.Fatal("already matched")
+	}
+	if err := p.Tx([]byte{5}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	p = Playback{Ops: ops, DontPanic: true}
+	if err := p.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if p.Tx([]byte{5}, nil) == nil {
+		t.Fatal("group not done")
+	}
+	if err := p.Tx([]byte{2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if p.Close() == nil {
+		t.Fatal("group not done")
+	}
 }
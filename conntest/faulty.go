@@ -0,0 +1,182 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conntest
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"periph.io/x/conn/v3"
+)
+
+// Faults injects faults in transactions, to exercise the error handling of
+// device drivers.
+//
+// It is embedded in Faulty and in the fakes of the bus packages, like
+// i2ctest.Faulty. The random faults are drawn from a RNG seeded with Seed, so
+// a test is reproducible as long as the transactions are the same.
+//
+// The zero value injects no fault.
+type Faults struct {
+	// FailAt lists the transactions that fail with Err, counting from 1.
+	FailAt []int
+	// ErrorRate is the probability, between 0 and 1, that a transaction fails
+	// with Err.
+	ErrorRate float64
+	// Err is returned by the failing transactions. It defaults to
+	// conn.ErrTimeout.
+	Err error
+	// BitFlipRate is the probability, between 0 and 1, that each bit read is
+	// flipped.
+	BitFlipRate float64
+	// TruncateRate is the probability, between 0 and 1, that a read stops
+	// early. The bytes past a random point are then set to 0xFF, like an
+	// idle bus pulled up, and no error is returned.
+	TruncateRate float64
+	// Latency is added before each transaction.
+	Latency time.Duration
+	// LockupAt is the transaction at which the bus locks up, counting from 1.
+	// This transaction and all the following ones fail with conn.ErrBusBusy
+	// until Recover() is called. 0 disables lockups.
+	LockupAt int
+	// Seed seeds the RNG.
+	Seed uint64
+	// Clock is used for Latency. If nil, real clock will be used.
+	Clock clockwork.Clock
+
+	mu     sync.Mutex
+	count  int
+	rng    *rand.Rand
+	locked bool
+}
+
+// Count returns the number of transactions seen so far, including the failed
+// ones.
+func (f *Faults) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// Recover ends a bus lockup.
+func (f *Faults) Recover() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.locked = false
+}
+
+// Do runs the transaction tx with the faults injected.
+//
+// r are the read buffers of the transaction. A failing transaction doesn't
+// call tx. If ctx is done while waiting for Latency, ctx.Err() is returned.
+func (f *Faults) Do(ctx context.Context, tx func() error, r ...[]byte) error {
+	f.mu.Lock()
+	f.count++
+	if f.rng == nil {
+		f.rng = rand.New(rand.NewPCG(f.Seed, f.Seed))
+	}
+	if f.LockupAt != 0 && f.count == f.LockupAt {
+		f.locked = true
+	}
+	var err error
+	if f.locked {
+		err = conn.ErrBusBusy
+	} else if slices.Contains(f.FailAt, f.count) || (f.ErrorRate > 0 && f.rng.Float64() < f.ErrorRate) {
+		err = f.Err
+		if err == nil {
+			err = conn.ErrTimeout
+		}
+	}
+	// Draw the corruption of the read data before releasing the lock.
+	var cuts []int
+	var flips [][]int
+	if err == nil {
+		for _, b := range r {
+			cut := len(b)
+			if f.TruncateRate > 0 && len(b) != 0 && f.rng.Float64() < f.TruncateRate {
+				cut = f.rng.IntN(len(b))
+			}
+			cuts = append(cuts, cut)
+			var bits []int
+			if f.BitFlipRate > 0 {
+				for i := 0; i < 8*len(b); i++ {
+					if f.rng.Float64() < f.BitFlipRate {
+						bits = append(bits, i)
+					}
+				}
+			}
+			flips = append(flips, bits)
+		}
+	}
+	clock := f.Clock
+	d := f.Latency
+	f.mu.Unlock()
+
+	if d > 0 {
+		if clock == nil {
+			clock = clockwork.NewRealClock()
+		}
+		select {
+		case <-clock.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx(); err != nil {
+		return err
+	}
+	for i, b := range r {
+		for j := cuts[i]; j < len(b); j++ {
+			b[j] = 0xFF
+		}
+		for _, bit := range flips[i] {
+			b[bit/8] ^= 1 << uint(bit%8)
+		}
+	}
+	return nil
+}
+
+// Faulty implements conn.Conn and injects faults in the transactions on Conn.
+//
+// Use it to verify that a driver detects corrupted data and recovers from
+// failures. See Faults for the supported faults.
+type Faulty struct {
+	Conn conn.Conn
+	Faults
+}
+
+func (f *Faulty) String() string {
+	return "faulty(" + f.Conn.String() + ")"
+}
+
+// Tx implements conn.Conn.
+func (f *Faulty) Tx(w, r []byte) error {
+	return f.TxContext(context.Background(), w, r)
+}
+
+// TxContext implements conn.ConnContext.
+//
+// ctx is forwarded to Conn via conn.WithContext().
+func (f *Faulty) TxContext(ctx context.Context, w, r []byte) error {
+	return f.Do(ctx, func() error {
+		return conn.WithContext(f.Conn).TxContext(ctx, w, r)
+	}, r)
+}
+
+// Duplex implements conn.Conn.
+func (f *Faulty) Duplex() conn.Duplex {
+	return f.Conn.Duplex()
+}
+
+var _ conn.Conn = &Faulty{}
+var _ conn.ConnContext = &Faulty{}
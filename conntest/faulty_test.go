@@ -0,0 +1,150 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conntest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"periph.io/x/conn/v3"
+)
+
+func TestFaulty_none(t *testing.T) {
+	f := &Faulty{Conn: &Playback{Ops: []IO{{W: []byte{1}, R: []byte{2, 3}}}, D: conn.Half}}
+	if s := f.String(); s != "faulty(playback)" {
+		t.Fatal(s)
+	}
+	if d := f.Duplex(); d != conn.Half {
+		t.Fatal(d)
+	}
+	r := make([]byte, 2)
+	if err := f.Tx([]byte{1}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{2, 3}) {
+		t.Fatal(r)
+	}
+	if c := f.Count(); c != 1 {
+		t.Fatal(c)
+	}
+}
+
+func TestFaulty_FailAt(t *testing.T) {
+	f := &Faulty{Conn: &Discard{}, Faults: Faults{FailAt: []int{2, 4}, Err: conn.ErrAddrNACK}}
+	var got []error
+	for i := 0; i < 4; i++ {
+		got = append(got, f.Tx(nil, nil))
+	}
+	if got[0] != nil || got[1] != conn.ErrAddrNACK || got[2] != nil || got[3] != conn.ErrAddrNACK {
+		t.Fatal(got)
+	}
+	f = &Faulty{Conn: &Discard{}, Faults: Faults{FailAt: []int{1}}}
+	if err := f.Tx(nil, nil); err != conn.ErrTimeout {
+		t.Fatal(err)
+	}
+}
+
+func TestFaulty_ErrorRate(t *testing.T) {
+	run := func() []bool {
+		f := &Faulty{Conn: &Discard{}, Faults: Faults{ErrorRate: 0.5, Seed: 42}}
+		var out []bool
+		for i := 0; i < 100; i++ {
+			out = append(out, f.Tx(nil, nil) != nil)
+		}
+		return out
+	}
+	a := run()
+	n := 0
+	for _, failed := range a {
+		if failed {
+			n++
+		}
+	}
+	if n < 25 || n > 75 {
+		t.Fatal(n)
+	}
+	// The same seed yields the same faults.
+	b := run()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestFaulty_BitFlipRate(t *testing.T) {
+	f := &Faulty{Conn: &Discard{}, Faults: Faults{BitFlipRate: 1}}
+	r := make([]byte, 2)
+	if err := f.Tx(nil, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{0xFF, 0xFF}) {
+		t.Fatal(r)
+	}
+}
+
+func TestFaulty_TruncateRate(t *testing.T) {
+	f := &Faulty{Conn: &Discard{}, Faults: Faults{TruncateRate: 1, Seed: 1}}
+	r := make([]byte, 16)
+	if err := f.Tx(nil, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[len(r)-1] != 0xFF {
+		t.Fatal(r)
+	}
+	for i := bytes.IndexByte(r, 0xFF); i < len(r); i++ {
+		if r[i] != 0xFF {
+			t.Fatal(r)
+		}
+	}
+}
+
+func TestFaulty_Lockup(t *testing.T) {
+	f := &Faulty{Conn: &Discard{}, Faults: Faults{LockupAt: 2}}
+	if err := f.Tx(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := f.Tx(nil, nil); err != conn.ErrBusBusy {
+			t.Fatal(err)
+		}
+	}
+	f.Recover()
+	if err := f.Tx(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFaulty_Latency(t *testing.T) {
+	clk := clockwork.NewFakeClock()
+	f := &Faulty{Conn: &Discard{}, Faults: Faults{Latency: time.Second, Clock: clk}}
+	done := make(chan error)
+	go func() {
+		done <- f.Tx(nil, nil)
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := f.TxContext(ctx, nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}
+
+func TestFaulty_TxError(t *testing.T) {
+	f := &Faulty{Conn: &Playback{DontPanic: true}, Faults: Faults{BitFlipRate: 1}}
+	if err := f.Tx(nil, make([]byte, 1)); !IsErr(err) {
+		t.Fatal(err)
+	}
+}
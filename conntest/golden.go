@@ -0,0 +1,386 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conntest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"periph.io/x/conn/v3"
+)
+
+// UpdateGolden makes Golden() record against the real connection and
+// overwrite the golden file instead of playing it back.
+//
+// It defaults to true when the environment variable PERIPH_UPDATE_GOLDEN is
+// set to a non-empty value.
+var UpdateGolden = os.Getenv("PERIPH_UPDATE_GOLDEN") != ""
+
+// Golden returns a Playback of the ops saved in the golden file at path.
+//
+// The Playback doesn't panic; the test fails at cleanup if not all the ops
+// were consumed.
+//
+// When UpdateGolden is true, it returns a Record over c instead and saves the
+// recorded ops to path at cleanup, unless the test failed. c is only used in
+// this mode, so it can be nil when only playing back.
+func Golden(t testing.TB, path string, c conn.Conn) conn.Conn {
+	t.Helper()
+	return GoldenFile(t, path, func() (conn.Conn, *[]IO) {
+		r := &Record{Conn: c}
+		return r, &r.Ops
+	}, func(ops []IO) (conn.Conn, io.Closer) {
+		p := &Playback{Ops: ops, DontPanic: true}
+		return p, p
+	})
+}
+
+// GoldenFile implements Golden() for any type of op, so each bus can have
+// its own.
+//
+// When UpdateGolden is true, it returns the recorder created by record and
+// saves the ops it recorded to path at cleanup, unless the test failed.
+// Otherwise it returns the playback created by play with the ops loaded from
+// path, and fails the test at cleanup if closing the playback fails.
+func GoldenFile[T encoding.TextMarshaler, P interface {
+	*T
+	encoding.TextUnmarshaler
+}, C any](t testing.TB, path string, record func() (C, *[]T), play func(ops []T) (C, io.Closer)) C {
+	t.Helper()
+	if UpdateGolden {
+		c, ops := record()
+		t.Cleanup(func() {
+			if !t.Failed() {
+				if err := SaveGolden(path, *ops); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+		return c
+	}
+	ops, err := LoadGolden[T, P](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, closer := play(ops)
+	t.Cleanup(func() {
+		if err := closer.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return c
+}
+
+// SaveGolden saves ops to the golden file at path, creating the directory if
+// needed.
+//
+// The file is written in JSON if path ends with ".json", in the text format
+// otherwise.
+//
+// The text format has one op per line. Each op is a space separated list of
+// key=value fields, like "w=0a0b r=0000 err=timeout". Buffers are written in
+// hexadecimal. Values containing spaces are quoted. Empty lines and
+// everything from a '#' in place of a field to the end of the line are
+// ignored, so the file can be annotated.
+//
+// The JSON format is an array with one object per op, holding the same fields
+// as the text format.
+func SaveGolden[T encoding.TextMarshaler](path string, ops []T) error {
+	var b bytes.Buffer
+	isJSON := filepath.Ext(path) == ".json"
+	if isJSON {
+		b.WriteString("[\n")
+	}
+	for i := range ops {
+		line, err := ops[i].MarshalText()
+		if err != nil {
+			return err
+		}
+		if !isJSON {
+			b.Write(line)
+			b.WriteByte('\n')
+			continue
+		}
+		f, err := splitGolden(line)
+		if err != nil {
+			return err
+		}
+		b.WriteString("  {")
+		for j := range f {
+			if j != 0 {
+				b.WriteByte(',')
+			}
+			k, _ := json.Marshal(f[j][0])
+			v, _ := json.Marshal(f[j][1])
+			b.Write(k)
+			b.WriteByte(':')
+			b.Write(v)
+		}
+		b.WriteByte('}')
+		if i != len(ops)-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	if isJSON {
+		b.WriteString("]\n")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// LoadGolden loads the ops from the golden file at path, as written by
+// SaveGolden.
+func LoadGolden[T any, P interface {
+	*T
+	encoding.TextUnmarshaler
+}](path string) ([]T, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lines [][]byte
+	var where []string
+	if filepath.Ext(path) == ".json" {
+		var objs []map[string]string
+		if err := json.Unmarshal(raw, &objs); err != nil {
+			return nil, fmt.Errorf("conntest: %s: %w", path, err)
+		}
+		for i, o := range objs {
+			var kv []string
+			for k, v := range o {
+				kv = append(kv, k, v)
+			}
+			lines = append(lines, FormatGoldenLine(kv...))
+			where = append(where, fmt.Sprintf("%s: op #%d", path, i))
+		}
+	} else {
+		s := bufio.NewScanner(bytes.NewReader(raw))
+		s.Buffer(nil, len(raw)+1)
+		for n := 1; s.Scan(); n++ {
+			f, err := splitGolden(s.Bytes())
+			if err != nil {
+				return nil, fmt.Errorf("conntest: %s:%d: %w", path, n, err)
+			}
+			if len(f) != 0 {
+				lines = append(lines, append([]byte(nil), s.Bytes()...))
+				where = append(where, fmt.Sprintf("%s:%d", path, n))
+			}
+		}
+	}
+	ops := make([]T, len(lines))
+	for i, l := range lines {
+		if err := P(&ops[i]).UnmarshalText(l); err != nil {
+			return nil, fmt.Errorf("conntest: %s: %w", where[i], err)
+		}
+	}
+	return ops, nil
+}
+
+// FormatGoldenLine formats one op of the golden text format from key, value
+// pairs.
+//
+// Pairs with an empty value are omitted, except the first one, so an op is
+// never an empty line.
+func FormatGoldenLine(kv ...string) []byte {
+	var b []byte
+	for i := 0; i+1 < len(kv); i += 2 {
+		if i != 0 && kv[i+1] == "" {
+			continue
+		}
+		if len(b) != 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, kv[i]...)
+		b = append(b, '=')
+		if strings.ContainsAny(kv[i+1], " \t\"#=") {
+			b = strconv.AppendQuote(b, kv[i+1])
+		} else {
+			b = append(b, kv[i+1]...)
+		}
+	}
+	return b
+}
+
+// ParseGoldenLine parses one op of the golden text format into its fields.
+//
+// Each key must be present at most once.
+func ParseGoldenLine(line []byte) (map[string]string, error) {
+	f, err := splitGolden(line)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, len(f))
+	for _, kv := range f {
+		if _, ok := m[kv[0]]; ok {
+			return nil, fmt.Errorf("duplicate field %q", kv[0])
+		}
+		m[kv[0]] = kv[1]
+	}
+	return m, nil
+}
+
+// ParseGoldenHex decodes a buffer of the golden formats, written with
+// hex.EncodeToString(). It returns nil for an empty buffer.
+func ParseGoldenHex(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return hex.DecodeString(s)
+}
+
+// GoldenErrorName returns the name of err in the golden formats.
+//
+// The well known conn errors like conn.ErrTimeout are written by name, like
+// "timeout". Other errors are written as their message. It returns "" for
+// nil.
+func GoldenErrorName(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, e := range goldenErrors {
+		if errors.Is(err, e.err) {
+			return e.name
+		}
+	}
+	return err.Error()
+}
+
+// GoldenError returns the error named name by GoldenErrorName.
+//
+// It returns the well known conn error if name is one, a new error with name
+// as the message otherwise. It returns nil for "".
+func GoldenError(name string) error {
+	if name == "" {
+		return nil
+	}
+	for _, e := range goldenErrors {
+		if e.name == name {
+			return e.err
+		}
+	}
+	return errors.New(name)
+}
+
+// MarshalText implements encoding.TextMarshaler for the golden text format.
+//
+// The fields are "w", "r", "err", "mask", "min", "max" and "group". The
+// last four are omitted when zero.
+func (io IO) MarshalText() ([]byte, error) {
+	return FormatGoldenLine("w", hex.EncodeToString(io.W), "r", hex.EncodeToString(io.R), "err", GoldenErrorName(io.Err),
+		"mask", hex.EncodeToString(io.WMask), "min", formatGoldenInt(io.Min), "max", formatGoldenInt(io.Max), "group", formatGoldenInt(io.Group)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the golden text
+// format.
+func (io *IO) UnmarshalText(b []byte) error {
+	f, err := ParseGoldenLine(b)
+	if err != nil {
+		return err
+	}
+	var n IO
+	for k, v := range f {
+		switch k {
+		case "w":
+			n.W, err = ParseGoldenHex(v)
+		case "r":
+			n.R, err = ParseGoldenHex(v)
+		case "err":
+			n.Err = GoldenError(v)
+		case "mask":
+			n.WMask, err = ParseGoldenHex(v)
+		case "min":
+			n.Min, err = strconv.Atoi(v)
+		case "max":
+			n.Max, err = strconv.Atoi(v)
+		case "group":
+			n.Group, err = strconv.Atoi(v)
+		default:
+			err = fmt.Errorf("unknown field %q", k)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	*io = n
+	return nil
+}
+
+//
+
+var goldenErrors = [...]struct {
+	name string
+	err  error
+}{
+	{"addr_nack", conn.ErrAddrNACK},
+	{"data_nack", conn.ErrDataNACK},
+	{"arbitration_lost", conn.ErrArbitrationLost},
+	{"timeout", conn.ErrTimeout},
+	{"bus_busy", conn.ErrBusBusy},
+	{"crc", conn.ErrCRC},
+	{"parity", conn.ErrParity},
+}
+
+// formatGoldenInt formats i; it returns "" for 0 so the field is omitted.
+func formatGoldenInt(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
+
+// splitGolden splits a line into its key, value pairs in order.
+func splitGolden(line []byte) ([][2]string, error) {
+	var out [][2]string
+	s := string(line)
+	for {
+		s = strings.TrimLeft(s, " \t\r")
+		if s == "" || s[0] == '#' {
+			return out, nil
+		}
+		i := strings.IndexAny(s, "= \t#")
+		if i <= 0 || s[i] != '=' {
+			return nil, fmt.Errorf("expected key=value at %q", s)
+		}
+		key := s[:i]
+		s = s[i+1:]
+		var value string
+		if s != "" && s[0] == '"' {
+			q, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value for %q", key)
+			}
+			value, _ = strconv.Unquote(q)
+			s = s[len(q):]
+			if s != "" && !strings.ContainsRune(" \t\r", rune(s[0])) {
+				return nil, fmt.Errorf("expected space after value for %q", key)
+			}
+		} else {
+			i = strings.IndexAny(s, " \t\r")
+			if i < 0 {
+				i = len(s)
+			}
+			value = s[:i]
+			s = s[i:]
+		}
+		out = append(out, [2]string{key, value})
+	}
+}
+
+var _ encoding.TextMarshaler = IO{}
+var _ encoding.TextUnmarshaler = &IO{}
@@ -0,0 +1,206 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conntest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"periph.io/x/conn/v3"
+)
+
+func TestIO_MarshalText(t *testing.T) {
+	data := []struct {
+		io   IO
+		want string
+	}{
+		{IO{}, "w="},
+		{IO{W: []byte{0xa, 0xb}, R: []byte{0, 1}}, "w=0a0b r=0001"},
+		{IO{R: []byte{1}}, "w= r=01"},
+		{IO{W: []byte{1}, Err: conn.ErrTimeout}, "w=01 err=timeout"},
+		{IO{W: []byte{1}, Err: errors.New("bad thing")}, `w=01 err="bad thing"`},
+		{IO{W: []byte{1, 2}, WMask: []byte{0xf0}, Min: 1, Max: -1, Group: 2}, "w=0102 mask=f0 min=1 max=-1 group=2"},
+	}
+	for i, line := range data {
+		b, err := line.io.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != line.want {
+			t.Fatalf("#%d: %q != %q", i, b, line.want)
+		}
+		var got IO
+		if err := got.UnmarshalText(b); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if !equalIO(got, line.io) {
+			t.Fatalf("#%d: %#v != %#v", i, got, line.io)
+		}
+	}
+}
+
+func TestIO_UnmarshalText_err(t *testing.T) {
+	data := []string{
+		"w",
+		"w=0",
+		"r=zz",
+		"x=01",
+		"w=01 w=02",
+		`err="abc`,
+		`err="abc"x`,
+		"=01",
+		"mask=0",
+		"min=a",
+		"max=a",
+		"group=a",
+	}
+	for _, line := range data {
+		var io IO
+		if err := io.UnmarshalText([]byte(line)); err == nil {
+			t.Fatalf("%q: expected error", line)
+		}
+	}
+}
+
+func TestSaveGolden_text(t *testing.T) {
+	ops := []IO{
+		{W: []byte{1}, R: []byte{2, 3}},
+		{},
+		{W: []byte{4}, Err: conn.ErrCRC},
+	}
+	path := filepath.Join(t.TempDir(), "sub", "ops.txt")
+	if err := SaveGolden(path, ops); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(raw); s != "w=01 r=0203\nw=\nw=04 err=crc\n" {
+		t.Fatal(s)
+	}
+	// Annotate the file.
+	raw = append([]byte("# Reading the sensor.\n\n"), raw...)
+	raw = append(raw, "w=05 # Trailing comment.\n"...)
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadGolden[IO](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops = append(ops, IO{W: []byte{5}})
+	checkOps(t, got, ops)
+}
+
+func TestSaveGolden_json(t *testing.T) {
+	ops := []IO{
+		{W: []byte{1}, R: []byte{2, 3}},
+		{W: []byte{4}, Err: errors.New("oh no")},
+	}
+	path := filepath.Join(t.TempDir(), "ops.json")
+	if err := SaveGolden(path, ops); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[\n  {\"w\":\"01\",\"r\":\"0203\"},\n  {\"w\":\"04\",\"err\":\"oh no\"}\n]\n"
+	if s := string(raw); s != want {
+		t.Fatal(s)
+	}
+	got, err := LoadGolden[IO](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOps(t, got, ops)
+}
+
+func TestLoadGolden_err(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadGolden[IO](filepath.Join(dir, "missing.txt")); err == nil {
+		t.Fatal("expected error")
+	}
+	data := map[string]string{
+		"bad.txt":   "# ok\nw=01\nw=0\n",
+		"bad.json":  "[{\"w\":1}]",
+		"bad2.json": "[{\"x\":\"01\"}]",
+	}
+	for name, content := range data {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadGolden[IO](path); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+	if _, err := LoadGolden[IO](filepath.Join(dir, "bad.txt")); !strings.Contains(err.Error(), "bad.txt:3:") {
+		t.Fatal(err)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.txt")
+	defer func(u bool) { UpdateGolden = u }(UpdateGolden)
+
+	UpdateGolden = true
+	t.Run("update", func(t *testing.T) {
+		c := Golden(t, path, &Playback{Ops: []IO{{W: []byte{1}, R: []byte{2}}}})
+		r := make([]byte, 1)
+		if err := c.Tx([]byte{1}, r); err != nil {
+			t.Fatal(err)
+		}
+	})
+	UpdateGolden = false
+	t.Run("playback", func(t *testing.T) {
+		c := Golden(t, path, nil)
+		r := make([]byte, 1)
+		if err := c.Tx([]byte{1}, r); err != nil {
+			t.Fatal(err)
+		}
+		if r[0] != 2 {
+			t.Fatal(r)
+		}
+	})
+}
+
+func TestGoldenError(t *testing.T) {
+	for _, e := range goldenErrors {
+		if err := GoldenError(GoldenErrorName(&conn.Error{Conn: "x", Err: e.err})); err != e.err {
+			t.Fatal(err)
+		}
+	}
+	if err := GoldenError(""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//
+
+func equalIO(a, b IO) bool {
+	if GoldenErrorName(a.Err) != GoldenErrorName(b.Err) {
+		return false
+	}
+	a.Err, b.Err = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+func checkOps(t *testing.T, got, want []IO) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%d ops != %d", len(got), len(want))
+	}
+	for i := range want {
+		if !equalIO(got[i], want[i]) {
+			t.Fatalf("#%d: %#v != %#v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conntest
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"periph.io/x/conn/v3"
+)
+
+// Register is a 8 bit register of a RegisterDevice.
+type Register struct {
+	// Value is the current value of the register.
+	Value byte
+	// ReadOnly ignores writes.
+	ReadOnly bool
+	// WriteOneToClear clears the bits written as 1 and leaves the others
+	// untouched, like an interrupt status register.
+	WriteOneToClear bool
+	// Volatile, if set, returns the value on each read instead of Value, like a
+	// register updated by the hardware.
+	Volatile func(d *RegisterDevice) byte
+	// OnWrite, if set, is called after each write with the value written,
+	// before it is applied to Value with WriteOneToClear.
+	//
+	// d is locked; the hook can access d.Regs directly but must not call the
+	// methods of d.
+	OnWrite func(d *RegisterDevice, v byte)
+}
+
+// RegisterDevice implements conn.Conn and emulates a peripheral exposing
+// memory mapped registers, as accessed by mmr.Dev8 and mmr.Dev16.
+//
+// Each transaction starts with the register address. The following bytes
+// written, then the bytes read, access the registers starting at this
+// address. The address is kept across transactions, so a transaction without
+// address bytes continues where the previous one stopped.
+//
+// The registers absent from Regs behave like plain memory, initially 0.
+//
+// Use i2ctest.DeviceBus to put it on an I²C bus.
+type RegisterDevice struct {
+	sync.Mutex
+	// AddrWidth is the size of the register address in bytes, 1 or 2. It
+	// defaults to 1.
+	AddrWidth int
+	// Order is the byte order of 16 bit addresses. It defaults to
+	// binary.BigEndian.
+	Order binary.ByteOrder
+	// NoAutoIncrement makes all the bytes of a transaction access the same
+	// register instead of the following ones.
+	NoAutoIncrement bool
+	// Regs are the registers, by address.
+	Regs map[uint16]*Register
+
+	ptr uint16
+}
+
+// Get returns the value of the register at addr, without running its
+// Volatile function.
+func (d *RegisterDevice) Get(addr uint16) byte {
+	d.Lock()
+	defer d.Unlock()
+	if r := d.Regs[addr]; r != nil {
+		return r.Value
+	}
+	return 0
+}
+
+// Set sets the value of the registers starting at addr, regardless of their
+// flags and without running their hooks.
+func (d *RegisterDevice) Set(addr uint16, v ...byte) {
+	d.Lock()
+	defer d.Unlock()
+	for i := range v {
+		d.reg(addr + uint16(i)).Value = v[i]
+	}
+}
+
+func (d *RegisterDevice) String() string {
+	return "registerdevice"
+}
+
+// Tx implements conn.Conn.
+//
+// It fails if w is shorter than AddrWidth but not empty.
+func (d *RegisterDevice) Tx(w, r []byte) error {
+	d.Lock()
+	defer d.Unlock()
+	width := d.AddrWidth
+	if width == 0 {
+		width = 1
+	}
+	if len(w) != 0 {
+		switch {
+		case width != 1 && width != 2:
+			return Errorf("conntest: invalid AddrWidth %d", d.AddrWidth)
+		case len(w) < width:
+			return Errorf("conntest: write of %d bytes is shorter than the register address", len(w))
+		case width == 1:
+			d.ptr = uint16(w[0])
+		case d.Order == nil:
+			d.ptr = binary.BigEndian.Uint16(w)
+		default:
+			d.ptr = d.Order.Uint16(w)
+		}
+		for _, v := range w[width:] {
+			reg := d.reg(d.ptr)
+			if !reg.ReadOnly {
+				if reg.OnWrite != nil {
+					reg.OnWrite(d, v)
+				}
+				if reg.WriteOneToClear {
+					reg.Value &^= v
+				} else {
+					reg.Value = v
+				}
+			}
+			d.next(width)
+		}
+	}
+	for i := range r {
+		if reg := d.Regs[d.ptr]; reg == nil {
+			r[i] = 0
+		} else if reg.Volatile != nil {
+			r[i] = reg.Volatile(d)
+		} else {
+			r[i] = reg.Value
+		}
+		d.next(width)
+	}
+	return nil
+}
+
+// TxContext implements conn.ConnContext.
+func (d *RegisterDevice) TxContext(ctx context.Context, w, r []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.Tx(w, r)
+}
+
+// Duplex implements conn.Conn.
+func (d *RegisterDevice) Duplex() conn.Duplex {
+	return conn.Half
+}
+
+//
+
+// reg returns the register at addr, creating it if needed.
+func (d *RegisterDevice) reg(addr uint16) *Register {
+	r := d.Regs[addr]
+	if r == nil {
+		if d.Regs == nil {
+			d.Regs = map[uint16]*Register{}
+		}
+		r = &Register{}
+		d.Regs[addr] = r
+	}
+	return r
+}
+
+// next moves to the next register, wrapping around the address space.
+func (d *RegisterDevice) next(width int) {
+	if d.NoAutoIncrement {
+		return
+	}
+	d.ptr++
+	if width == 1 {
+		d.ptr &= 0xFF
+	}
+}
+
+var _ conn.Conn = &RegisterDevice{}
+var _ conn.ConnContext = &RegisterDevice{}
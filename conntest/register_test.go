@@ -0,0 +1,146 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conntest
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/mmr"
+)
+
+func TestRegisterDevice_Dev8(t *testing.T) {
+	d := &RegisterDevice{}
+	if s := d.String(); s != "registerdevice" {
+		t.Fatal(s)
+	}
+	if x := d.Duplex(); x != conn.Half {
+		t.Fatal(x)
+	}
+	d.Set(0xFF, 0x12, 0x34)
+	m := mmr.Dev8{Conn: d, Order: binary.BigEndian}
+	// Auto-increment wraps around the 8 bit address space.
+	if v, err := m.ReadUint16(0xFF); err != nil || v != 0x1200 {
+		t.Fatalf("%#x, %v", v, err)
+	}
+	if err := m.WriteUint32(0x10, 0x01020304); err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Get(0x13); v != 4 {
+		t.Fatal(v)
+	}
+	// Without address, the transaction continues after the previous one.
+	r := make([]byte, 1)
+	if err := d.Tx([]byte{0x11}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Tx(nil, r); err != nil || r[0] != 2 {
+		t.Fatal(r, err)
+	}
+	if err := d.Tx(nil, r); err != nil || r[0] != 3 {
+		t.Fatal(r, err)
+	}
+	if v := d.Get(0x80); v != 0 {
+		t.Fatal(v)
+	}
+}
+
+func TestRegisterDevice_Dev16(t *testing.T) {
+	d := &RegisterDevice{AddrWidth: 2, Order: binary.LittleEndian}
+	m := mmr.Dev16{Conn: d, Order: binary.LittleEndian}
+	if err := m.WriteUint16(0x1234, 0xABCD); err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Get(0x1234); v != 0xCD {
+		t.Fatalf("%#x", v)
+	}
+	if v := d.Get(0x1235); v != 0xAB {
+		t.Fatalf("%#x", v)
+	}
+	d = &RegisterDevice{AddrWidth: 2}
+	if err := d.Tx([]byte{0x12, 0x34, 1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Get(0x1234); v != 1 {
+		t.Fatal(v)
+	}
+	if d.Tx([]byte{1}, nil) == nil {
+		t.Fatal("short address")
+	}
+	if (&RegisterDevice{AddrWidth: 3}).Tx([]byte{1, 2, 3}, nil) == nil {
+		t.Fatal("invalid AddrWidth")
+	}
+}
+
+func TestRegisterDevice_flags(t *testing.T) {
+	reads := 0
+	var written []byte
+	d := &RegisterDevice{
+		Regs: map[uint16]*Register{
+			0: {Value: 0x60, ReadOnly: true},
+			1: {Value: 0x0F, WriteOneToClear: true},
+			2: {Volatile: func(d *RegisterDevice) byte {
+				reads++
+				return byte(reads)
+			}},
+			3: {OnWrite: func(d *RegisterDevice, v byte) {
+				written = append(written, v)
+				// Writing the reset command clears the interrupts.
+				if v == 0xB6 {
+					d.Regs[1].Value = 0
+				}
+			}},
+		},
+	}
+	if err := d.Tx([]byte{0, 0xFF, 0x05}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Get(0); v != 0x60 {
+		t.Fatalf("%#x", v)
+	}
+	if v := d.Get(1); v != 0x0A {
+		t.Fatalf("%#x", v)
+	}
+	r := make([]byte, 2)
+	for i := byte(1); i < 3; i++ {
+		if err := d.Tx([]byte{2}, r[:1]); err != nil || r[0] != i {
+			t.Fatal(r, err)
+		}
+	}
+	if err := d.TxContext(context.Background(), []byte{3, 0xB6}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 1 || written[0] != 0xB6 || d.Get(1) != 0 {
+		t.Fatal(written, d.Get(1))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.TxContext(ctx, []byte{3, 0}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterDevice_NoAutoIncrement(t *testing.T) {
+	n := byte(0)
+	d := &RegisterDevice{
+		NoAutoIncrement: true,
+		Regs:            map[uint16]*Register{0x10: {Volatile: func(*RegisterDevice) byte { n++; return n }}},
+	}
+	r := make([]byte, 3)
+	if err := d.Tx([]byte{0x10}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 1 || r[1] != 2 || r[2] != 3 {
+		t.Fatal(r)
+	}
+	if err := d.Tx([]byte{0x20, 1, 2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Get(0x20); v != 2 {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,434 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Priority is the scheduling priority of a queued transaction.
+//
+// Transactions with a higher priority run first. Transactions with the same
+// priority run in submission order. Any value is valid.
+type Priority int
+
+// Predefined priorities.
+const (
+	PriorityLow    Priority = -10
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 10
+)
+
+// Request is a transaction to queue.
+type Request struct {
+	// W and R are the buffers of the transaction. W is copied by Submit(); the
+	// caller must not touch R until the Future is done.
+	W, R []byte
+	// Priority defaults to PriorityNormal.
+	Priority Priority
+	// Coalesce lets this request share a single transaction with the queued
+	// requests that are identical and that also set Coalesce. Only use it for
+	// reads without side effects.
+	//
+	// The shared transaction runs at the highest priority of the requests.
+	Coalesce bool
+}
+
+// Future is the pending result of a queued transaction.
+type Future struct {
+	done chan struct{}
+	err  error
+	r    []byte
+	j    *job
+	stop func() bool
+}
+
+// Done returns a channel that is closed once the transaction completed,
+// failed or was canceled.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for the transaction and returns its error.
+func (f *Future) Wait() error {
+	<-f.done
+	return f.err
+}
+
+// Err returns the error of the transaction once Done() is closed, nil
+// before.
+func (f *Future) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// AsyncConn is a conn.Conn that queues transactions on a single worker
+// goroutine.
+//
+// Use Submit() to queue a transaction without waiting for it. Tx() and
+// TxContext() queue a transaction at PriorityNormal and wait for it.
+type AsyncConn struct {
+	c conn.Conn
+	q *queue
+}
+
+// NewAsyncConn returns an AsyncConn over c and starts its worker.
+//
+// Call Close() to stop the worker.
+func NewAsyncConn(c conn.Conn) *AsyncConn {
+	return &AsyncConn{c: c, q: newQueue()}
+}
+
+// Submit queues req.
+//
+// If ctx is done before the transaction starts, it is removed from the queue
+// and the Future fails with ctx.Err(). A transaction that started always
+// completes.
+func (a *AsyncConn) Submit(ctx context.Context, req Request) *Future {
+	key := ""
+	if req.Coalesce {
+		key = string(req.W) + "/" + strconv.Itoa(len(req.R))
+	}
+	w := append([]byte(nil), req.W...)
+	return a.q.submit(ctx, req.Priority, key, req.R, func(r []byte) error {
+		return a.c.Tx(w, r)
+	})
+}
+
+func (a *AsyncConn) String() string {
+	return a.c.String()
+}
+
+// Tx implements conn.Conn.
+func (a *AsyncConn) Tx(w, r []byte) error {
+	return a.Submit(context.Background(), Request{W: w, R: r}).Wait()
+}
+
+// TxContext implements conn.ConnContext.
+func (a *AsyncConn) TxContext(ctx context.Context, w, r []byte) error {
+	return txQueued(ctx, w, r, func(ctx context.Context, w, r []byte) *Future {
+		return a.Submit(ctx, Request{W: w, R: r})
+	})
+}
+
+// Duplex implements conn.Conn.
+func (a *AsyncConn) Duplex() conn.Duplex {
+	return a.c.Duplex()
+}
+
+// MaxTxSize implements conn.Limits.
+func (a *AsyncConn) MaxTxSize() int {
+	return maxTxSize(a.c)
+}
+
+// Close stops the worker after the current transaction, if any. The queued
+// transactions fail.
+//
+// It doesn't close the underlying connection.
+func (a *AsyncConn) Close() error {
+	a.q.close()
+	return nil
+}
+
+// AsyncI2C is an i2c.Bus that queues transactions on a single worker
+// goroutine.
+//
+// It has the same semantics as AsyncConn. SetSpeed() is queued too.
+type AsyncI2C struct {
+	b i2c.Bus
+	q *queue
+}
+
+// NewAsyncI2C returns an AsyncI2C over b and starts its worker.
+//
+// Call Close() to stop the worker.
+func NewAsyncI2C(b i2c.Bus) *AsyncI2C {
+	return &AsyncI2C{b: b, q: newQueue()}
+}
+
+// Submit queues req for the device at addr.
+//
+// Only requests to the same address are coalesced.
+func (a *AsyncI2C) Submit(ctx context.Context, addr uint16, req Request) *Future {
+	key := ""
+	if req.Coalesce {
+		key = strconv.Itoa(int(addr)) + "/" + string(req.W) + "/" + strconv.Itoa(len(req.R))
+	}
+	w := append([]byte(nil), req.W...)
+	return a.q.submit(ctx, req.Priority, key, req.R, func(r []byte) error {
+		return a.b.Tx(addr, w, r)
+	})
+}
+
+func (a *AsyncI2C) String() string {
+	return a.b.String()
+}
+
+// Tx implements i2c.Bus.
+func (a *AsyncI2C) Tx(addr uint16, w, r []byte) error {
+	return a.Submit(context.Background(), addr, Request{W: w, R: r}).Wait()
+}
+
+// TxContext implements i2c.BusContext.
+func (a *AsyncI2C) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	return txQueued(ctx, w, r, func(ctx context.Context, w, r []byte) *Future {
+		return a.Submit(ctx, addr, Request{W: w, R: r})
+	})
+}
+
+// SetSpeed implements i2c.Bus.
+func (a *AsyncI2C) SetSpeed(f physic.Frequency) error {
+	return a.q.submit(context.Background(), PriorityNormal, "", nil, func([]byte) error {
+		return a.b.SetSpeed(f)
+	}).Wait()
+}
+
+// SCL implements i2c.Pins.
+func (a *AsyncI2C) SCL() gpio.PinIO {
+	return i2cSCL(a.b)
+}
+
+// SDA implements i2c.Pins.
+func (a *AsyncI2C) SDA() gpio.PinIO {
+	return i2cSDA(a.b)
+}
+
+// Close stops the worker after the current transaction, if any. The queued
+// transactions fail.
+//
+// It doesn't close the underlying bus.
+func (a *AsyncI2C) Close() error {
+	a.q.close()
+	return nil
+}
+
+//
+
+var errQueueClosed = errors.New("connutil: queue closed")
+
+// txQueued submits a transaction on a private read buffer and waits for it,
+// so it can return as soon as ctx is done even if the transaction started.
+func txQueued(ctx context.Context, w, r []byte, submit func(ctx context.Context, w, r []byte) *Future) error {
+	var rb []byte
+	if r != nil {
+		rb = make([]byte, len(r))
+	}
+	f := submit(ctx, w, rb)
+	select {
+	case <-f.done:
+		copy(r, rb)
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// job is a queued transaction, shared by one or more coalesced futures.
+type job struct {
+	prio    Priority
+	seq     uint64
+	index   int
+	key     string
+	run     func(r []byte) error
+	futures []*Future
+	running bool
+}
+
+// jobHeap implements heap.Interface.
+type jobHeap []*job
+
+func (h jobHeap) Len() int {
+	return len(h)
+}
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].prio != h[j].prio {
+		return h[i].prio > h[j].prio
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h jobHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *jobHeap) Push(x any) {
+	j := x.(*job)
+	j.index = len(*h)
+	*h = append(*h, j)
+}
+
+func (h *jobHeap) Pop() any {
+	old := *h
+	j := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return j
+}
+
+// queue runs jobs by priority on a single worker goroutine.
+type queue struct {
+	mu      sync.Mutex
+	cond    sync.Cond
+	jobs    jobHeap
+	pending map[string]*job
+	seq     uint64
+	closed  bool
+	done    chan struct{}
+}
+
+func newQueue() *queue {
+	q := &queue{pending: map[string]*job{}, done: make(chan struct{})}
+	q.cond.L = &q.mu
+	go q.work()
+	return q
+}
+
+// submit queues run to be called with r. key is empty if the job cannot be
+// coalesced.
+func (q *queue) submit(ctx context.Context, prio Priority, key string, r []byte, run func(r []byte) error) *Future {
+	f := &Future{done: make(chan struct{}), r: r}
+	if err := ctx.Err(); err != nil {
+		f.complete(err)
+		return f
+	}
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		f.complete(errQueueClosed)
+		return f
+	}
+	if j := q.pending[key]; key != "" && j != nil {
+		j.futures = append(j.futures, f)
+		if prio > j.prio {
+			j.prio = prio
+			heap.Fix(&q.jobs, j.index)
+		}
+		f.j = j
+	} else {
+		j := &job{prio: prio, seq: q.seq, key: key, run: run, futures: []*Future{f}}
+		q.seq++
+		heap.Push(&q.jobs, j)
+		if key != "" {
+			q.pending[key] = j
+		}
+		f.j = j
+		q.cond.Signal()
+	}
+	if ctx.Done() != nil {
+		f.stop = context.AfterFunc(ctx, func() {
+			q.cancel(f, ctx.Err())
+		})
+	}
+	q.mu.Unlock()
+	return f
+}
+
+// cancel removes f from the queue if its transaction didn't start yet.
+func (q *queue) cancel(f *Future, err error) {
+	q.mu.Lock()
+	j := f.j
+	if j == nil || j.running {
+		q.mu.Unlock()
+		return
+	}
+	for i := range j.futures {
+		if j.futures[i] == f {
+			j.futures = append(j.futures[:i], j.futures[i+1:]...)
+			break
+		}
+	}
+	if len(j.futures) == 0 {
+		heap.Remove(&q.jobs, j.index)
+		if j.key != "" {
+			delete(q.pending, j.key)
+		}
+	}
+	f.j = nil
+	q.mu.Unlock()
+	f.complete(err)
+}
+
+func (q *queue) close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		<-q.done
+		return
+	}
+	q.closed = true
+	jobs := q.jobs
+	q.jobs = nil
+	q.pending = map[string]*job{}
+	for _, j := range jobs {
+		j.running = true
+	}
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	for _, j := range jobs {
+		for _, f := range j.futures {
+			f.complete(errQueueClosed)
+		}
+	}
+	<-q.done
+}
+
+func (q *queue) work() {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		for len(q.jobs) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		j := heap.Pop(&q.jobs).(*job)
+		if j.key != "" {
+			delete(q.pending, j.key)
+		}
+		j.running = true
+		q.mu.Unlock()
+
+		first := j.futures[0]
+		err := j.run(first.r)
+		for _, f := range j.futures[1:] {
+			copy(f.r, first.r)
+		}
+		for _, f := range j.futures {
+			f.complete(err)
+		}
+	}
+}
+
+// complete resolves the future. It must be called exactly once.
+func (f *Future) complete(err error) {
+	f.err = err
+	if f.stop != nil {
+		f.stop()
+	}
+	close(f.done)
+}
+
+var _ conn.ConnContext = &AsyncConn{}
+var _ conn.Limits = &AsyncConn{}
+var _ i2c.BusContext = &AsyncI2C{}
+var _ i2c.Pins = &AsyncI2C{}
@@ -0,0 +1,274 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestAsyncConn_Priority(t *testing.T) {
+	g := newGateConn()
+	a := NewAsyncConn(g)
+	defer a.Close()
+	// Block the worker on a first transaction.
+	first := a.Submit(context.Background(), Request{W: []byte{0}})
+	<-g.started
+	var futures []*Future
+	for i, p := range []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityNormal, 42} {
+		futures = append(futures, a.Submit(context.Background(), Request{W: []byte{byte(i + 1)}, Priority: p}))
+	}
+	if err := futures[0].Err(); err != nil {
+		t.Fatal(err)
+	}
+	close(g.unblock)
+	for _, f := range append(futures, first) {
+		if err := f.Wait(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(g.order(), []byte{0, 5, 3, 2, 4, 1}) {
+		t.Fatal(g.order())
+	}
+}
+
+func TestAsyncConn_Coalesce(t *testing.T) {
+	g := newGateConn()
+	a := NewAsyncConn(g)
+	defer a.Close()
+	first := a.Submit(context.Background(), Request{W: []byte{0}})
+	<-g.started
+	r1 := make([]byte, 2)
+	r2 := make([]byte, 2)
+	r3 := make([]byte, 2)
+	f1 := a.Submit(context.Background(), Request{W: []byte{1}, R: r1, Coalesce: true, Priority: PriorityLow})
+	f2 := a.Submit(context.Background(), Request{W: []byte{2}, R: make([]byte, 2)})
+	f3 := a.Submit(context.Background(), Request{W: []byte{1}, R: r2, Coalesce: true, Priority: PriorityHigh})
+	// Not coalesced: different read length, not opted in.
+	f4 := a.Submit(context.Background(), Request{W: []byte{1}, R: make([]byte, 1), Coalesce: true})
+	f5 := a.Submit(context.Background(), Request{W: []byte{1}, R: r3})
+	close(g.unblock)
+	for _, f := range []*Future{first, f1, f2, f3, f4, f5} {
+		if err := f.Wait(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The coalesced request was bumped to PriorityHigh.
+	if !reflect.DeepEqual(g.order(), []byte{0, 1, 2, 1, 1}) {
+		t.Fatal(g.order())
+	}
+	if r1[0] != 1 || r2[0] != 1 || r3[0] != 1 {
+		t.Fatal(r1, r2, r3)
+	}
+}
+
+func TestAsyncConn_Submit_reuse(t *testing.T) {
+	g := newGateConn()
+	a := NewAsyncConn(g)
+	defer a.Close()
+	first := a.Submit(context.Background(), Request{W: []byte{0}})
+	<-g.started
+	w := []byte{1}
+	f := a.Submit(context.Background(), Request{W: w})
+	// W is copied, so it can be reused right away.
+	w[0] = 2
+	close(g.unblock)
+	for _, f := range []*Future{first, f} {
+		if err := f.Wait(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(g.order(), []byte{0, 1}) {
+		t.Fatal(g.order())
+	}
+}
+
+func TestAsyncConn_Cancel(t *testing.T) {
+	g := newGateConn()
+	a := NewAsyncConn(g)
+	defer a.Close()
+	first := a.Submit(context.Background(), Request{W: []byte{0}})
+	<-g.started
+	ctx, cancel := context.WithCancel(context.Background())
+	f1 := a.Submit(ctx, Request{W: []byte{1}, Coalesce: true})
+	f2 := a.Submit(ctx, Request{W: []byte{1}, Coalesce: true})
+	f3 := a.Submit(context.Background(), Request{W: []byte{2}})
+	cancel()
+	if err := f1.Wait(); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := f2.Wait(); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := a.Submit(ctx, Request{W: []byte{3}}).Wait(); err != context.Canceled {
+		t.Fatal(err)
+	}
+	// TxContext returns immediately.
+	if err := a.TxContext(ctx, []byte{4}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	close(g.unblock)
+	if err := first.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f3.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g.order(), []byte{0, 2}) {
+		t.Fatal(g.order())
+	}
+}
+
+func TestAsyncConn_TxContext_running(t *testing.T) {
+	g := newGateConn()
+	a := NewAsyncConn(g)
+	defer a.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := make([]byte, 1)
+	done := make(chan error)
+	go func() {
+		done <- a.TxContext(ctx, []byte{1}, r)
+	}()
+	<-g.started
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatal(err)
+	}
+	close(g.unblock)
+	// The buffer is untouched even though the transaction completes.
+	if err := a.Tx([]byte{2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 0 {
+		t.Fatal(r)
+	}
+	if err := a.TxContext(context.Background(), []byte{3}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 3 {
+		t.Fatal(r)
+	}
+}
+
+func TestAsyncConn_Close(t *testing.T) {
+	g := newGateConn()
+	a := NewAsyncConn(g)
+	if s := a.String(); s != "gateConn" {
+		t.Fatal(s)
+	}
+	if d := a.Duplex(); d != conn.Half {
+		t.Fatal(d)
+	}
+	if m := a.MaxTxSize(); m != 0 {
+		t.Fatal(m)
+	}
+	first := a.Submit(context.Background(), Request{W: []byte{0}})
+	<-g.started
+	f := a.Submit(context.Background(), Request{W: []byte{1}})
+	done := make(chan struct{})
+	go func() {
+		_ = a.Close()
+		close(done)
+	}()
+	if err := f.Wait(); err != errQueueClosed {
+		t.Fatal(err)
+	}
+	close(g.unblock)
+	<-done
+	if err := first.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Tx([]byte{2}, nil); err != errQueueClosed {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAsyncI2C(t *testing.T) {
+	b := &orderBus{}
+	a := NewAsyncI2C(b)
+	defer a.Close()
+	if s := a.String(); s != "orderBus" {
+		t.Fatal(s)
+	}
+	if a.SCL() != gpio.INVALID || a.SDA() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	if err := a.SetSpeed(physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Tx(0x40, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.TxContext(context.Background(), 0x41, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	// Coalescing is per address.
+	f1 := a.Submit(context.Background(), 0x42, Request{Coalesce: true})
+	f2 := a.Submit(context.Background(), 0x43, Request{Coalesce: true})
+	if err := f1.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f2.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(b.addrs, []uint16{0x40, 0x41, 0x42, 0x43}) {
+		t.Fatal(b.addrs)
+	}
+}
+
+//
+
+// gateConn blocks its first transaction until unblock is closed, and records
+// the first byte written of each transaction. It echoes it in r.
+type gateConn struct {
+	started chan struct{}
+	unblock chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	w       []byte
+}
+
+func newGateConn() *gateConn {
+	return &gateConn{started: make(chan struct{}), unblock: make(chan struct{})}
+}
+
+func (g *gateConn) String() string {
+	return "gateConn"
+}
+
+func (g *gateConn) Tx(w, r []byte) error {
+	g.once.Do(func() {
+		close(g.started)
+		<-g.unblock
+	})
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(w) != 0 {
+		g.w = append(g.w, w[0])
+		if len(r) != 0 {
+			r[0] = w[0]
+		}
+	}
+	return nil
+}
+
+func (g *gateConn) Duplex() conn.Duplex {
+	return conn.Half
+}
+
+func (g *gateConn) order() []byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]byte(nil), g.w...)
+}
@@ -0,0 +1,379 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Link types written in the pcap header by the capture wrappers.
+//
+// The USER link types are reserved for private use; they are displayed as raw
+// data by Wireshark unless a dissector is configured for them.
+const (
+	// LinkTypeI2CLinux is LINKTYPE_I2C_LINUX. Each I²C message is a packet
+	// with the 5 bytes pseudo header decoded by Wireshark.
+	LinkTypeI2CLinux = 209
+	// LinkTypeSPI is LINKTYPE_USER0. Each transaction or packet is a record
+	// as described in CaptureSPI.
+	LinkTypeSPI = 147
+	// LinkTypeConn is LINKTYPE_USER1. It is used for generic connections,
+	// like UART.
+	LinkTypeConn = 148
+	// LinkTypeOneWire is LINKTYPE_USER2.
+	LinkTypeOneWire = 149
+)
+
+// Flags of the records written for the USER link types.
+const (
+	// CaptureKeepCS is set when the CS line was kept asserted after a SPI
+	// packet.
+	CaptureKeepCS = 0x01
+	// CaptureStrongPullup is set when a 1-wire transaction requested a strong
+	// pull up.
+	CaptureStrongPullup = 0x02
+	// CaptureFailed is set when the transaction returned an error.
+	CaptureFailed = 0x80
+)
+
+// CaptureI2C returns an i2c.Bus that writes the transactions on b to w in the
+// pcap format with LinkTypeI2CLinux, so the capture can be opened with
+// Wireshark.
+//
+// The pcap header is written immediately. Each transaction is written as a
+// write message followed by a read message, omitting the empty ones. The
+// read message has no data when the transaction fails. Addresses above 0x7F
+// are written as 10 bit addresses, with the I2C_M_TEN flag set.
+//
+// Errors writing to w are ignored so capturing never affects the bus; only
+// the first one is returned by the returned object's Close() method. It
+// doesn't close w nor b.
+//
+// The returned object also implements i2c.BusContext, i2c.Pins and
+// io.Closer.
+func CaptureI2C(b i2c.Bus, w io.Writer) (i2c.Bus, error) {
+	p, err := newPcapWriter(w, LinkTypeI2CLinux)
+	if err != nil {
+		return nil, err
+	}
+	return &captureI2C{b: b, p: p}, nil
+}
+
+// CaptureSPI returns a spi.Conn that writes the transactions on c to w in the
+// pcap format with LinkTypeSPI.
+//
+// Each Tx() call and each packet of a TxPackets() call is a record made of
+// the flags byte, then the big endian uint32 length of the data written
+// followed by the data, then the length of the data read followed by the
+// data.
+//
+// Error handling is the same as CaptureI2C. The returned object also
+// implements conn.ConnContext, conn.Limits, spi.Pins and io.Closer.
+func CaptureSPI(c spi.Conn, w io.Writer) (spi.Conn, error) {
+	p, err := newPcapWriter(w, LinkTypeSPI)
+	if err != nil {
+		return nil, err
+	}
+	return &captureSPI{captureConn{c: c, p: p}, c}, nil
+}
+
+// CaptureConn returns a conn.Conn that writes the transactions on c to w in
+// the pcap format with LinkTypeConn. It is meant for UART connections.
+//
+// The records have the same format as CaptureSPI. Error handling is the same
+// as CaptureI2C. The returned object also implements conn.ConnContext,
+// conn.Limits and io.Closer.
+func CaptureConn(c conn.Conn, w io.Writer) (conn.Conn, error) {
+	p, err := newPcapWriter(w, LinkTypeConn)
+	if err != nil {
+		return nil, err
+	}
+	return &captureConn{c: c, p: p}, nil
+}
+
+// CaptureOneWire returns a onewire.Bus that writes the transactions on b to w
+// in the pcap format with LinkTypeOneWire.
+//
+// The records have the same format as CaptureSPI. Searches are not captured.
+// Error handling is the same as CaptureI2C. The returned object also
+// implements onewire.BusContext, onewire.Pins and io.Closer.
+func CaptureOneWire(b onewire.Bus, w io.Writer) (onewire.Bus, error) {
+	p, err := newPcapWriter(w, LinkTypeOneWire)
+	if err != nil {
+		return nil, err
+	}
+	return &captureOneWire{b: b, p: p}, nil
+}
+
+//
+
+const (
+	pcapMagic   = 0xa1b2c3d4
+	pcapSnapLen = 0x40000
+
+	i2cFlagRead = 0x0001 // I2C_M_RD
+	i2cFlagTen  = 0x0010 // I2C_M_TEN
+)
+
+// pcapWriter writes the classic pcap format with microsecond timestamps.
+type pcapWriter struct {
+	now func() time.Time
+
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+	buf []byte
+}
+
+func newPcapWriter(w io.Writer, linkType uint32) (*pcapWriter, error) {
+	var hdr [24]byte
+	binary.LittleEndian.PutUint32(hdr[0:], pcapMagic)
+	binary.LittleEndian.PutUint16(hdr[4:], 2)
+	binary.LittleEndian.PutUint16(hdr[6:], 4)
+	// hdr[8:16] are the timezone and the timestamp accuracy, both 0.
+	binary.LittleEndian.PutUint32(hdr[16:], pcapSnapLen)
+	binary.LittleEndian.PutUint32(hdr[20:], linkType)
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	return &pcapWriter{now: time.Now, w: w}, nil
+}
+
+// write writes a packet whose content is built by fill.
+func (p *pcapWriter) write(ts time.Time, fill func(b []byte) []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return
+	}
+	b := append(p.buf[:0], make([]byte, 16)...)
+	b = fill(b)
+	n := len(b) - 16
+	binary.LittleEndian.PutUint32(b[0:], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(b[4:], uint32(ts.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(b[8:], uint32(min(n, pcapSnapLen)))
+	binary.LittleEndian.PutUint32(b[12:], uint32(n))
+	_, p.err = p.w.Write(b[:16+min(n, pcapSnapLen)])
+	p.buf = b
+}
+
+// record writes a record for the USER link types.
+func (p *pcapWriter) record(flags byte, w, r []byte, err error) {
+	if err != nil {
+		flags |= CaptureFailed
+	}
+	p.write(p.now(), func(b []byte) []byte {
+		b = append(b, flags)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(w)))
+		b = append(b, w...)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(r)))
+		return append(b, r...)
+	})
+}
+
+// i2c writes the messages of an I²C transaction.
+//
+// A 10 bit address is written as the two address bytes sent on the wire.
+func (p *pcapWriter) i2c(addr uint16, w, r []byte, err error) {
+	ts := p.now()
+	ten := addr > 0x7F
+	msg := func(flags uint32, data []byte) {
+		p.write(ts, func(b []byte) []byte {
+			rd := byte(flags & i2cFlagRead)
+			if ten {
+				flags |= i2cFlagTen
+			}
+			b = append(b, 0)
+			b = binary.BigEndian.AppendUint32(b, flags)
+			if ten {
+				b = append(b, 0xF0|byte(addr>>7)&0x06|rd, byte(addr))
+			} else {
+				b = append(b, byte(addr<<1)|rd)
+			}
+			return append(b, data...)
+		})
+	}
+	if len(w) != 0 || len(r) == 0 {
+		msg(0, w)
+	}
+	if len(r) != 0 {
+		if err != nil {
+			r = nil
+		}
+		msg(i2cFlagRead, r)
+	}
+}
+
+func (p *pcapWriter) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+type captureConn struct {
+	c conn.Conn
+	p *pcapWriter
+}
+
+func (c *captureConn) String() string {
+	return c.c.String()
+}
+
+func (c *captureConn) Tx(w, r []byte) error {
+	err := c.c.Tx(w, r)
+	c.p.record(0, w, r, err)
+	return err
+}
+
+func (c *captureConn) TxContext(ctx context.Context, w, r []byte) error {
+	err := conn.WithContext(c.c).TxContext(ctx, w, r)
+	c.p.record(0, w, r, err)
+	return err
+}
+
+func (c *captureConn) Duplex() conn.Duplex {
+	return c.c.Duplex()
+}
+
+func (c *captureConn) MaxTxSize() int {
+	return maxTxSize(c.c)
+}
+
+func (c *captureConn) Close() error {
+	return c.p.close()
+}
+
+type captureSPI struct {
+	captureConn
+	s spi.Conn
+}
+
+func (c *captureSPI) TxPackets(p []spi.Packet) error {
+	err := c.s.TxPackets(p)
+	for i := range p {
+		var flags byte
+		if p[i].KeepCS {
+			flags = CaptureKeepCS
+		}
+		c.p.record(flags, p[i].W, p[i].R, err)
+	}
+	return err
+}
+
+func (c *captureSPI) CLK() gpio.PinOut {
+	return spiCLK(c.s)
+}
+
+func (c *captureSPI) MOSI() gpio.PinOut {
+	return spiMOSI(c.s)
+}
+
+func (c *captureSPI) MISO() gpio.PinIn {
+	return spiMISO(c.s)
+}
+
+func (c *captureSPI) CS() gpio.PinOut {
+	return spiCS(c.s)
+}
+
+type captureI2C struct {
+	b i2c.Bus
+	p *pcapWriter
+}
+
+func (c *captureI2C) String() string {
+	return c.b.String()
+}
+
+func (c *captureI2C) Tx(addr uint16, w, r []byte) error {
+	err := c.b.Tx(addr, w, r)
+	c.p.i2c(addr, w, r, err)
+	return err
+}
+
+func (c *captureI2C) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	err := i2cTxContext(ctx, c.b, addr, w, r)
+	c.p.i2c(addr, w, r, err)
+	return err
+}
+
+func (c *captureI2C) SetSpeed(f physic.Frequency) error {
+	return c.b.SetSpeed(f)
+}
+
+func (c *captureI2C) SCL() gpio.PinIO {
+	return i2cSCL(c.b)
+}
+
+func (c *captureI2C) SDA() gpio.PinIO {
+	return i2cSDA(c.b)
+}
+
+func (c *captureI2C) Close() error {
+	return c.p.close()
+}
+
+type captureOneWire struct {
+	b onewire.Bus
+	p *pcapWriter
+}
+
+func (c *captureOneWire) String() string {
+	return c.b.String()
+}
+
+func (c *captureOneWire) Tx(w, r []byte, power onewire.Pullup) error {
+	err := c.b.Tx(w, r, power)
+	c.p.record(pullupFlag(power), w, r, err)
+	return err
+}
+
+func (c *captureOneWire) TxContext(ctx context.Context, w, r []byte, power onewire.Pullup) error {
+	err := oneWireTxContext(ctx, c.b, w, r, power)
+	c.p.record(pullupFlag(power), w, r, err)
+	return err
+}
+
+func (c *captureOneWire) Search(alarmOnly bool) ([]onewire.Address, error) {
+	return c.b.Search(alarmOnly)
+}
+
+func (c *captureOneWire) Q() gpio.PinIO {
+	return oneWireQ(c.b)
+}
+
+func (c *captureOneWire) Close() error {
+	return c.p.close()
+}
+
+func pullupFlag(power onewire.Pullup) byte {
+	if power {
+		return CaptureStrongPullup
+	}
+	return 0
+}
+
+var _ conn.ConnContext = &captureConn{}
+var _ conn.Limits = &captureConn{}
+var _ io.Closer = &captureConn{}
+var _ spi.Conn = &captureSPI{}
+var _ spi.Pins = &captureSPI{}
+var _ i2c.BusContext = &captureI2C{}
+var _ i2c.Pins = &captureI2C{}
+var _ io.Closer = &captureI2C{}
+var _ onewire.BusContext = &captureOneWire{}
+var _ onewire.Pins = &captureOneWire{}
+var _ io.Closer = &captureOneWire{}
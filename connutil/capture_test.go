@@ -0,0 +1,273 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewiretest"
+	"periph.io/x/conn/v3/spi"
+)
+
+func TestCaptureI2C(t *testing.T) {
+	var buf bytes.Buffer
+	p := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x23, W: []byte{1}, R: []byte{2, 3}},
+			{Addr: 0x23, W: []byte{4}},
+		},
+		DontPanic: true,
+	}
+	b, err := CaptureI2C(p, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.(*captureI2C).p.now = fakeNow
+	if s := b.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if err := b.Tx(0x23, []byte{1}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.(*captureI2C).TxContext(context.Background(), 0x23, []byte{4}, nil); err != nil {
+		t.Fatal(err)
+	}
+	// Fails: no more ops.
+	if err := b.Tx(0x24, nil, make([]byte, 1)); err == nil {
+		t.Fatal("expected failure")
+	}
+	if err := b.(*captureI2C).Close(); err != nil {
+		t.Fatal(err)
+	}
+	link, pkts := parsePcap(t, buf.Bytes())
+	if link != LinkTypeI2CLinux {
+		t.Fatal(link)
+	}
+	want := [][]byte{
+		{0, 0, 0, 0, 0, 0x46, 1},
+		{0, 0, 0, 0, 1, 0x47, 2, 3},
+		{0, 0, 0, 0, 0, 0x46, 4},
+		{0, 0, 0, 0, 1, 0x49},
+	}
+	checkPackets(t, pkts, want)
+}
+
+func TestCaptureI2C_10bit(t *testing.T) {
+	var buf bytes.Buffer
+	p := &i2ctest.Playback{Ops: []i2ctest.IO{{Addr: 0x2A5, W: []byte{1}, R: []byte{2}}}}
+	b, err := CaptureI2C(p, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.(*captureI2C).p.now = fakeNow
+	if err := b.Tx(0x2A5, []byte{1}, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	_, pkts := parsePcap(t, buf.Bytes())
+	want := [][]byte{
+		{0, 0, 0, 0, 0x10, 0xF4, 0xA5, 1},
+		{0, 0, 0, 0, 0x11, 0xF5, 0xA5, 2},
+	}
+	checkPackets(t, pkts, want)
+}
+
+func TestCaptureSPI(t *testing.T) {
+	var buf bytes.Buffer
+	p := &fakeSPI{}
+	c, err := CaptureSPI(p, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.(*captureSPI).p.now = fakeNow
+	if err := c.Tx([]byte{1, 2}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	pkts := []spi.Packet{{W: []byte{3}, KeepCS: true}, {W: []byte{4}, R: make([]byte, 1)}}
+	if err := c.TxPackets(pkts); err != nil {
+		t.Fatal(err)
+	}
+	if c.(spi.Pins).CLK() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	link, got := parsePcap(t, buf.Bytes())
+	if link != LinkTypeSPI {
+		t.Fatal(link)
+	}
+	want := [][]byte{
+		{0, 0, 0, 0, 2, 1, 2, 0, 0, 0, 2, 0, 0},
+		{CaptureKeepCS, 0, 0, 0, 1, 3, 0, 0, 0, 0},
+		{0, 0, 0, 0, 1, 4, 0, 0, 0, 1, 0},
+	}
+	checkPackets(t, got, want)
+}
+
+func TestCaptureConn(t *testing.T) {
+	var buf bytes.Buffer
+	p := &conntest.Playback{
+		Ops: []conntest.IO{
+			{W: []byte{1}, R: []byte{2}},
+			{W: []byte{3}, Err: conn.ErrTimeout},
+		},
+		D: conn.Full,
+	}
+	c, err := CaptureConn(p, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.(*captureConn).p.now = fakeNow
+	if d := c.Duplex(); d != conn.Full {
+		t.Fatal(d)
+	}
+	if l := c.(conn.Limits).MaxTxSize(); l != 0 {
+		t.Fatal(l)
+	}
+	if err := c.Tx([]byte{1}, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.(conn.ConnContext).TxContext(context.Background(), []byte{3}, nil); err != conn.ErrTimeout {
+		t.Fatal(err)
+	}
+	link, got := parsePcap(t, buf.Bytes())
+	if link != LinkTypeConn {
+		t.Fatal(link)
+	}
+	want := [][]byte{
+		{0, 0, 0, 0, 1, 1, 0, 0, 0, 1, 2},
+		{CaptureFailed, 0, 0, 0, 1, 3, 0, 0, 0, 0},
+	}
+	checkPackets(t, got, want)
+}
+
+func TestCaptureOneWire(t *testing.T) {
+	var buf bytes.Buffer
+	p := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: []byte{0x44}, Pull: onewire.StrongPullup},
+			{W: []byte{0xbe}, R: []byte{1}},
+		},
+	}
+	b, err := CaptureOneWire(p, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.(*captureOneWire).p.now = fakeNow
+	if err := b.Tx([]byte{0x44}, nil, onewire.StrongPullup); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.(onewire.BusContext).TxContext(context.Background(), []byte{0xbe}, make([]byte, 1), onewire.WeakPullup); err != nil {
+		t.Fatal(err)
+	}
+	link, got := parsePcap(t, buf.Bytes())
+	if link != LinkTypeOneWire {
+		t.Fatal(link)
+	}
+	want := [][]byte{
+		{CaptureStrongPullup, 0, 0, 0, 1, 0x44, 0, 0, 0, 0},
+		{0, 0, 0, 0, 1, 0xbe, 0, 0, 0, 1, 1},
+	}
+	checkPackets(t, got, want)
+}
+
+func TestCapture_WriteError(t *testing.T) {
+	if _, err := CaptureConn(&conntest.Playback{}, &failWriter{}); err == nil {
+		t.Fatal("expected header failure")
+	}
+	w := &failWriter{n: 1}
+	p := &conntest.Playback{Ops: []conntest.IO{{W: []byte{1}}, {W: []byte{2}}}}
+	c, err := CaptureConn(p, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The capture failure doesn't affect the connection.
+	for i := byte(1); i < 3; i++ {
+		if err := c.Tx([]byte{i}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.(*captureConn).Close(); err != errWrite {
+		t.Fatal(err)
+	}
+	if w.calls != 2 {
+		t.Fatal(w.calls)
+	}
+}
+
+//
+
+var errWrite = errors.New("write failed")
+
+func fakeNow() time.Time {
+	return time.Unix(1000, 2000)
+}
+
+// failWriter fails after n writes.
+type failWriter struct {
+	n     int
+	calls int
+}
+
+func (f *failWriter) Write(b []byte) (int, error) {
+	f.calls++
+	if f.calls > f.n {
+		return 0, errWrite
+	}
+	return len(b), nil
+}
+
+// parsePcap returns the link type and the packets, after checking that the
+// timestamps are the one from fakeNow.
+func parsePcap(t *testing.T, b []byte) (uint32, [][]byte) {
+	if len(b) < 24 {
+		t.Fatalf("short header: %d", len(b))
+	}
+	if m := binary.LittleEndian.Uint32(b); m != pcapMagic {
+		t.Fatalf("%#x", m)
+	}
+	if v := binary.LittleEndian.Uint16(b[4:]); v != 2 {
+		t.Fatal(v)
+	}
+	if v := binary.LittleEndian.Uint16(b[6:]); v != 4 {
+		t.Fatal(v)
+	}
+	link := binary.LittleEndian.Uint32(b[20:])
+	var pkts [][]byte
+	for b = b[24:]; len(b) != 0; {
+		if len(b) < 16 {
+			t.Fatalf("short record: %d", len(b))
+		}
+		if s, us := binary.LittleEndian.Uint32(b), binary.LittleEndian.Uint32(b[4:]); s != 1000 || us != 2 {
+			t.Fatal(s, us)
+		}
+		n := binary.LittleEndian.Uint32(b[8:])
+		if l := binary.LittleEndian.Uint32(b[12:]); l != n {
+			t.Fatal(l, n)
+		}
+		pkts = append(pkts, b[16:16+n])
+		b = b[16+n:]
+	}
+	return link, pkts
+}
+
+func checkPackets(t *testing.T, got, want [][]byte) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d packets, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("#%d: %#v != %#v", i, got[i], want[i])
+		}
+	}
+}
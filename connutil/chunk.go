@@ -0,0 +1,297 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"errors"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Chunk returns a conn.Conn that splits transactions larger than size bytes
+// into multiple transactions on c.
+//
+// If size is 0, c.(conn.Limits).MaxTxSize() is used. If there is still no
+// limit, transactions are passed through as-is.
+//
+// For full duplex connections, w and r are split in lockstep. For half duplex
+// connections, a transaction transfers len(w)+len(r) bytes: w is written
+// first in as many transactions as needed, with the last write chunk sharing
+// its transaction with as much of r as fits, then the rest of r is read in
+// as many transactions as needed. This keeps the common "write a register
+// address then read" transaction intact as long as both fit in size bytes.
+//
+// Each chunk is an independent transaction on c, so this is only valid with
+// devices that tolerate it. Use ChunkSPI to keep the CS line asserted across
+// chunks with SPI.
+//
+// The returned object also implements conn.ConnContext and conn.Limits; the
+// context is checked between each chunk.
+func Chunk(c conn.Conn, size int) conn.Conn {
+	if size <= 0 {
+		size = maxTxSize(c)
+	}
+	return &chunkConn{c: c, size: size}
+}
+
+// ChunkSPI returns a spi.Conn that splits transactions larger than size bytes
+// into multiple TxPackets() calls on c, keeping the CS line asserted between
+// them.
+//
+// If size is 0, c.(conn.Limits).MaxTxSize() is used. If there is still no
+// limit, transactions are passed through as-is.
+//
+// Packets larger than size are split in smaller packets, and packets are
+// grouped so that each TxPackets() call on c transfers at most size bytes.
+// For half duplex connections, a packet with both W and R is first split in
+// a write packet followed by a read packet.
+// All the pieces of a split packet but the last one have KeepCS set to true,
+// so the transaction continues over multiple calls as documented on
+// spi.Conn.TxPackets(). The last piece retains the original packet's KeepCS.
+// When a packet specifies BitsPerWord above 8, it is only split on word
+// boundaries.
+//
+// The packets are validated before anything is sent. If a call on c fails
+// within a split packet, an empty packet is sent to deassert CS.
+//
+// The returned object also implements spi.Pins and conn.Limits.
+func ChunkSPI(c spi.Conn, size int) spi.Conn {
+	if size <= 0 {
+		size = maxTxSize(c)
+	}
+	return &chunkSPI{c: c, size: size}
+}
+
+//
+
+var (
+	errChunkLen  = errors.New("connutil: full duplex requires w and r to have the same length")
+	errChunkWord = errors.New("connutil: word size is larger than the maximum transaction size")
+)
+
+func maxTxSize(c conn.Conn) int {
+	if l, ok := c.(conn.Limits); ok {
+		return l.MaxTxSize()
+	}
+	return 0
+}
+
+// isLarger returns true if the transaction transfers more than size bytes on
+// c. w and r are transferred one after the other unless c is full duplex.
+func isLarger(c conn.Conn, size int, w, r []byte) bool {
+	if size <= 0 {
+		return false
+	}
+	if c.Duplex() != conn.Full {
+		return len(w)+len(r) > size
+	}
+	return len(w) > size || len(r) > size
+}
+
+type chunkConn struct {
+	c    conn.Conn
+	size int
+}
+
+func (c *chunkConn) String() string {
+	return c.c.String()
+}
+
+func (c *chunkConn) Tx(w, r []byte) error {
+	return c.TxContext(context.Background(), w, r)
+}
+
+func (c *chunkConn) TxContext(ctx context.Context, w, r []byte) error {
+	cc := conn.WithContext(c.c)
+	if !isLarger(c.c, c.size, w, r) {
+		return cc.TxContext(ctx, w, r)
+	}
+	if c.c.Duplex() == conn.Full {
+		if len(w) != len(r) {
+			return errChunkLen
+		}
+		for i := 0; i < len(w); i += c.size {
+			end := min(i+c.size, len(w))
+			if err := cc.TxContext(ctx, w[i:end], r[i:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// Write all but the last write chunk.
+	for len(w) > c.size {
+		if err := cc.TxContext(ctx, w[:c.size], nil); err != nil {
+			return err
+		}
+		w = w[c.size:]
+	}
+	// Last write chunk with as much of r as fits, then the rest of the reads.
+	n := min(c.size-len(w), len(r))
+	if err := cc.TxContext(ctx, w, r[:n]); err != nil {
+		return err
+	}
+	for r = r[n:]; len(r) != 0; r = r[n:] {
+		n = min(c.size, len(r))
+		if err := cc.TxContext(ctx, nil, r[:n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *chunkConn) Duplex() conn.Duplex {
+	return c.c.Duplex()
+}
+
+// MaxTxSize implements conn.Limits.
+//
+// It returns 0 since there is no limit anymore.
+func (c *chunkConn) MaxTxSize() int {
+	return 0
+}
+
+//
+
+type chunkSPI struct {
+	c    spi.Conn
+	size int
+}
+
+func (c *chunkSPI) String() string {
+	return c.c.String()
+}
+
+func (c *chunkSPI) Tx(w, r []byte) error {
+	if !isLarger(c.c, c.size, w, r) {
+		return c.c.Tx(w, r)
+	}
+	return c.TxPackets([]spi.Packet{{W: w, R: r}})
+}
+
+func (c *chunkSPI) TxPackets(p []spi.Packet) error {
+	if c.size <= 0 {
+		return c.c.TxPackets(p)
+	}
+	if c.c.Duplex() == conn.Half {
+		p = splitPhases(p)
+	}
+	// Validate all the packets first, so an invalid packet doesn't leave a
+	// partial transaction on the wire.
+	for _, pkt := range p {
+		if len(pkt.W) != 0 && len(pkt.R) != 0 && len(pkt.W) != len(pkt.R) {
+			return errChunkLen
+		}
+		if wordSize(pkt) > c.size {
+			return errChunkWord
+		}
+	}
+	var batch []spi.Packet
+	n := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := c.c.TxPackets(batch)
+		if err != nil && batch[len(batch)-1].KeepCS {
+			// The transaction is aborted; try to deassert CS.
+			_ = c.c.TxPackets([]spi.Packet{{}})
+		}
+		batch = batch[:0]
+		n = 0
+		return err
+	}
+	for _, pkt := range p {
+		l := max(len(pkt.W), len(pkt.R))
+		if l == 0 {
+			batch = append(batch, pkt)
+			continue
+		}
+		// Never split a word in two.
+		word := wordSize(pkt)
+		for off := 0; off < l; {
+			room := c.size - n
+			if room -= room % word; room == 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+				continue
+			}
+			end := min(off+room, l)
+			// Keep CS asserted within a split packet, even across calls.
+			sub := spi.Packet{BitsPerWord: pkt.BitsPerWord, KeepCS: true}
+			if len(pkt.W) != 0 {
+				sub.W = pkt.W[off:end]
+			}
+			if len(pkt.R) != 0 {
+				sub.R = pkt.R[off:end]
+			}
+			if end == l {
+				sub.KeepCS = pkt.KeepCS
+			}
+			batch = append(batch, sub)
+			n += end - off
+			off = end
+		}
+	}
+	return flush()
+}
+
+// wordSize returns the size in bytes of a word of pkt.
+func wordSize(pkt spi.Packet) int {
+	if pkt.BitsPerWord > 8 {
+		return (int(pkt.BitsPerWord) + 7) / 8
+	}
+	return 1
+}
+
+// splitPhases returns p with each packet that both writes and reads split in
+// a write packet followed by a read packet, keeping CS asserted in between.
+func splitPhases(p []spi.Packet) []spi.Packet {
+	out := make([]spi.Packet, 0, len(p))
+	for _, pkt := range p {
+		if len(pkt.W) != 0 && len(pkt.R) != 0 {
+			out = append(out, spi.Packet{W: pkt.W, BitsPerWord: pkt.BitsPerWord, KeepCS: true})
+			pkt.W = nil
+		}
+		out = append(out, pkt)
+	}
+	return out
+}
+
+func (c *chunkSPI) Duplex() conn.Duplex {
+	return c.c.Duplex()
+}
+
+// MaxTxSize implements conn.Limits.
+//
+// It returns 0 since there is no limit anymore.
+func (c *chunkSPI) MaxTxSize() int {
+	return 0
+}
+
+func (c *chunkSPI) CLK() gpio.PinOut {
+	return spiCLK(c.c)
+}
+
+func (c *chunkSPI) MOSI() gpio.PinOut {
+	return spiMOSI(c.c)
+}
+
+func (c *chunkSPI) MISO() gpio.PinIn {
+	return spiMISO(c.c)
+}
+
+func (c *chunkSPI) CS() gpio.PinOut {
+	return spiCS(c.c)
+}
+
+var _ conn.ConnContext = &chunkConn{}
+var _ conn.Limits = &chunkConn{}
+var _ spi.Conn = &chunkSPI{}
+var _ spi.Pins = &chunkSPI{}
+var _ conn.Limits = &chunkSPI{}
@@ -0,0 +1,324 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/spi"
+)
+
+func TestChunk_Half(t *testing.T) {
+	p := &conntest.Playback{
+		Ops: []conntest.IO{
+			{W: []byte{1, 2}},
+			{W: []byte{3}, R: []byte{10}},
+			{R: []byte{11, 12}},
+		},
+		D: conn.Half,
+	}
+	c := Chunk(p, 2)
+	if s := c.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if d := c.Duplex(); d != conn.Half {
+		t.Fatal(d)
+	}
+	if l := c.(conn.Limits).MaxTxSize(); l != 0 {
+		t.Fatal(l)
+	}
+	r := make([]byte, 3)
+	if err := c.Tx([]byte{1, 2, 3}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{10, 11, 12}) {
+		t.Fatal(r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChunk_Half_WriteOnly(t *testing.T) {
+	p := &conntest.Playback{
+		Ops: []conntest.IO{{W: []byte{1, 2}}, {W: []byte{3, 4}}, {W: []byte{5}}},
+		D:   conn.Half,
+	}
+	if err := Chunk(p, 2).Tx([]byte{1, 2, 3, 4, 5}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChunk_Half_Sum(t *testing.T) {
+	// w and r each fit but not together.
+	p := &conntest.Playback{
+		Ops: []conntest.IO{{W: []byte{1}, R: []byte{10}}, {R: []byte{11}}},
+		D:   conn.Half,
+	}
+	r := make([]byte, 2)
+	if err := Chunk(p, 2).Tx([]byte{1}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{10, 11}) {
+		t.Fatal(r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChunk_Full(t *testing.T) {
+	p := &limitedPlayback{
+		Playback: conntest.Playback{
+			Ops: []conntest.IO{
+				{W: []byte{1, 2}, R: []byte{10, 11}},
+				{W: []byte{3}, R: []byte{12}},
+			},
+			D: conn.Full,
+		},
+		max: 2,
+	}
+	c := Chunk(p, 0)
+	r := make([]byte, 3)
+	if err := c.Tx([]byte{1, 2, 3}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{10, 11, 12}) {
+		t.Fatal(r)
+	}
+	if err := c.Tx([]byte{1, 2, 3}, r[:2]); err != errChunkLen {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChunk_Small(t *testing.T) {
+	p := &conntest.Playback{Ops: []conntest.IO{{W: []byte{1, 2, 3}}}}
+	// No limit.
+	c := Chunk(p, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := c.(conn.ConnContext).TxContext(ctx, []byte{1, 2, 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := c.(conn.ConnContext).TxContext(ctx, []byte{1, 2, 3}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChunkSPI_Tx(t *testing.T) {
+	f := &fakeSPI{max: 4}
+	c := ChunkSPI(f, 0)
+	if s := c.String(); s != "fakeSPI" {
+		t.Fatal(s)
+	}
+	if d := c.Duplex(); d != conn.Full {
+		t.Fatal(d)
+	}
+	if l := c.(conn.Limits).MaxTxSize(); l != 0 {
+		t.Fatal(l)
+	}
+	// Small enough.
+	if err := c.Tx([]byte{1}, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if f.tx != 1 || len(f.calls) != 0 {
+		t.Fatal(f.tx, f.calls)
+	}
+	w := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	r := make([]byte, len(w))
+	if err := c.Tx(w, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]spi.Packet{
+		{{W: w[0:4], R: r[0:4], KeepCS: true}},
+		{{W: w[4:8], R: r[4:8], KeepCS: true}},
+		{{W: w[8:10], R: r[8:10]}},
+	}
+	if !reflect.DeepEqual(f.calls, expected) {
+		t.Fatalf("%#v", f.calls)
+	}
+}
+
+func TestChunkSPI_TxPackets(t *testing.T) {
+	f := &fakeSPI{}
+	c := ChunkSPI(f, 4)
+	cmd := []byte{0x01, 0x2C}
+	data := []byte{1, 2, 3, 4, 5, 6, 7}
+	p := []spi.Packet{
+		{W: cmd, BitsPerWord: 9, KeepCS: true},
+		{W: data, BitsPerWord: 8, KeepCS: false},
+		{},
+	}
+	if err := c.TxPackets(p); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]spi.Packet{
+		{
+			{W: cmd, BitsPerWord: 9, KeepCS: true},
+			{W: data[0:2], BitsPerWord: 8, KeepCS: true},
+		},
+		{{W: data[2:6], BitsPerWord: 8, KeepCS: true}},
+		{
+			{W: data[6:7], BitsPerWord: 8},
+			{},
+		},
+	}
+	if !reflect.DeepEqual(f.calls, expected) {
+		t.Fatalf("%#v", f.calls)
+	}
+	if err := c.TxPackets([]spi.Packet{{W: data, R: cmd}}); err != errChunkLen {
+		t.Fatal(err)
+	}
+	// 16 bits words are not split, so a 3 bytes room isn't filled.
+	f.calls = nil
+	if err := c.TxPackets([]spi.Packet{{W: cmd[:1], KeepCS: true}, {W: data[:6], BitsPerWord: 16}}); err != nil {
+		t.Fatal(err)
+	}
+	expected = [][]spi.Packet{
+		{
+			{W: cmd[:1], KeepCS: true},
+			{W: data[0:2], BitsPerWord: 16, KeepCS: true},
+		},
+		{{W: data[2:6], BitsPerWord: 16}},
+	}
+	if !reflect.DeepEqual(f.calls, expected) {
+		t.Fatalf("%#v", f.calls)
+	}
+}
+
+func TestChunkSPI_Half(t *testing.T) {
+	f := &fakeSPI{d: conn.Half}
+	c := ChunkSPI(f, 4)
+	w := []byte{1, 2}
+	r := make([]byte, 5)
+	if err := c.Tx(w, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]spi.Packet{
+		{
+			{W: w, KeepCS: true},
+			{R: r[0:2], KeepCS: true},
+		},
+		{{R: r[2:5]}},
+	}
+	if !reflect.DeepEqual(f.calls, expected) {
+		t.Fatalf("%#v", f.calls)
+	}
+	// Passed through when it fits.
+	f.calls = nil
+	if err := c.Tx(w, r[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if f.tx != 1 || f.calls != nil {
+		t.Fatal(f.tx, f.calls)
+	}
+}
+
+func TestChunkSPI_Word(t *testing.T) {
+	c := ChunkSPI(&fakeSPI{}, 1)
+	if err := c.TxPackets([]spi.Packet{{W: []byte{1, 2}, BitsPerWord: 16}}); err != errChunkWord {
+		t.Fatal(err)
+	}
+}
+
+func TestChunkSPI_invalid(t *testing.T) {
+	f := &fakeSPI{}
+	c := ChunkSPI(f, 2)
+	data := []byte{1, 2, 3, 4, 5}
+	if err := c.TxPackets([]spi.Packet{{W: data, KeepCS: true}, {W: data, R: data[:1]}}); err != errChunkLen {
+		t.Fatal(err)
+	}
+	if err := c.TxPackets([]spi.Packet{{W: data, KeepCS: true}, {W: data[:4], BitsPerWord: 24}}); err != errChunkWord {
+		t.Fatal(err)
+	}
+	if f.calls != nil {
+		t.Fatalf("%#v", f.calls)
+	}
+}
+
+func TestChunkSPI_err(t *testing.T) {
+	f := &fakeSPI{err: errors.New("oops")}
+	c := ChunkSPI(f, 2)
+	data := []byte{1, 2, 3, 4, 5}
+	if err := c.TxPackets([]spi.Packet{{W: data}}); err != f.err {
+		t.Fatal(err)
+	}
+	// CS is released after the failed split packet.
+	expected := [][]spi.Packet{{{W: data[0:2], KeepCS: true}}, {{}}}
+	if !reflect.DeepEqual(f.calls, expected) {
+		t.Fatalf("%#v", f.calls)
+	}
+}
+
+func TestChunkSPI_Pins(t *testing.T) {
+	c := ChunkSPI(&fakeSPI{}, 0).(spi.Pins)
+	if c.CLK() != gpio.INVALID || c.MOSI() != gpio.INVALID || c.MISO() != gpio.INVALID || c.CS() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	if err := ChunkSPI(&fakeSPI{}, 0).TxPackets(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//
+
+type limitedPlayback struct {
+	conntest.Playback
+	max int
+}
+
+func (l *limitedPlayback) MaxTxSize() int {
+	return l.max
+}
+
+// fakeSPI implements spi.Conn and records the packets.
+type fakeSPI struct {
+	max   int
+	d     conn.Duplex
+	err   error
+	tx    int
+	calls [][]spi.Packet
+}
+
+func (f *fakeSPI) String() string {
+	return "fakeSPI"
+}
+
+func (f *fakeSPI) Tx(w, r []byte) error {
+	f.tx++
+	return nil
+}
+
+func (f *fakeSPI) TxPackets(p []spi.Packet) error {
+	f.calls = append(f.calls, append([]spi.Packet(nil), p...))
+	return f.err
+}
+
+func (f *fakeSPI) Duplex() conn.Duplex {
+	if f.d == conn.DuplexUnknown {
+		return conn.Full
+	}
+	return f.d
+}
+
+func (f *fakeSPI) MaxTxSize() int {
+	return f.max
+}
@@ -0,0 +1,9 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package connutil includes utilities to augment connections and buses.
+//
+// Each utility wraps a conn.Conn or a bus and exposes the same interface, so
+// it can be transparently handed to a device driver.
+package connutil
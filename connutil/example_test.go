@@ -0,0 +1,40 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/connutil"
+	"periph.io/x/conn/v3/driver/driverreg"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2creg"
+)
+
+func ExampleRetryI2C() {
+	// Make sure periph is initialized.
+	// TODO: Use host.Init(). It is not used in this example to prevent circular
+	// go package import.
+	if _, err := driverreg.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	b, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer b.Close()
+
+	// Retry NACKs and other temporary errors with the default backoff.
+	d := &i2c.Dev{Addr: 0x76, Bus: connutil.RetryI2C(b, connutil.DefaultRetryPolicy)}
+
+	// Read the chip ID.
+	id := make([]byte, 1)
+	if err := d.Tx([]byte{0xD0}, id); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("0x%02X\n", id[0])
+}
@@ -0,0 +1,405 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// MetricsVar is the name of the expvar.Map that holds the Metrics of every
+// instrumented device, keyed by device string.
+const MetricsVar = "periph_conn"
+
+// Metrics holds the transaction statistics of a single device.
+//
+// It implements expvar.Var. All the members are safe to read concurrently.
+type Metrics struct {
+	// Tx is the number of transactions, including failed ones.
+	Tx expvar.Int
+	// BytesWritten and BytesRead count the bytes of successful transactions.
+	BytesWritten expvar.Int
+	BytesRead    expvar.Int
+	// Errors counts failed transactions per class. The keys are "addr_nack",
+	// "data_nack", "arbitration_lost", "timeout", "bus_busy", "crc", "parity",
+	// "canceled" and "other".
+	Errors expvar.Map
+	// Latency is the distribution of the transactions' duration.
+	Latency Histogram
+}
+
+// MetricsFor returns the Metrics for the device name, creating it as needed.
+//
+// The first call publishes the expvar.Map MetricsVar.
+func MetricsFor(name string) *Metrics {
+	metricsOnce.Do(func() {
+		metricsRoot = expvar.NewMap(MetricsVar)
+	})
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if v, ok := metricsRoot.Get(name).(*Metrics); ok {
+		return v
+	}
+	m := &Metrics{}
+	metricsRoot.Set(name, m)
+	return m
+}
+
+// String implements expvar.Var.
+//
+// It returns a valid JSON object.
+func (m *Metrics) String() string {
+	return "{\"tx\": " + m.Tx.String() +
+		", \"bytes_written\": " + m.BytesWritten.String() +
+		", \"bytes_read\": " + m.BytesRead.String() +
+		", \"errors\": " + m.Errors.String() +
+		", \"latency\": " + m.Latency.String() + "}"
+}
+
+// record updates the statistics for a transaction that started at start.
+func (m *Metrics) record(start time.Time, w, r int, err error) {
+	m.Latency.Observe(time.Since(start))
+	m.Tx.Add(1)
+	if err != nil {
+		m.Errors.Add(errorClass(err), 1)
+		return
+	}
+	m.BytesWritten.Add(int64(w))
+	m.BytesRead.Add(int64(r))
+}
+
+// HistogramBuckets returns the upper bounds of the Histogram buckets. The
+// last implicit bucket catches everything above.
+//
+// The returned slice is a copy.
+func HistogramBuckets() []time.Duration {
+	return append([]time.Duration(nil), histogramBuckets[:]...)
+}
+
+// histogramBuckets are the bounds returned by HistogramBuckets().
+var histogramBuckets = [...]time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// Histogram is a latency histogram with fixed buckets as returned by
+// HistogramBuckets().
+//
+// It implements expvar.Var. The zero value is ready to use.
+type Histogram struct {
+	counts [len(histogramBuckets) + 1]atomic.Int64
+	sum    atomic.Int64
+}
+
+// Observe adds a sample.
+func (h *Histogram) Observe(d time.Duration) {
+	i := 0
+	for ; i < len(histogramBuckets) && d > histogramBuckets[i]; i++ {
+	}
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+}
+
+// Count returns the number of samples in the bucket i. Use
+// len(HistogramBuckets()) for the overflow bucket.
+func (h *Histogram) Count(i int) int64 {
+	return h.counts[i].Load()
+}
+
+// Total returns the total number of samples.
+func (h *Histogram) Total() int64 {
+	var t int64
+	for i := range h.counts {
+		t += h.counts[i].Load()
+	}
+	return t
+}
+
+// Sum returns the sum of all the samples.
+func (h *Histogram) Sum() time.Duration {
+	return time.Duration(h.sum.Load())
+}
+
+// String implements expvar.Var.
+//
+// It returns a valid JSON object with one member per bucket named after its
+// upper bound, plus "inf" and "sum_us".
+func (h *Histogram) String() string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, d := range histogramBuckets {
+		b.WriteString("\"" + d.String() + "\": " + strconv.FormatInt(h.counts[i].Load(), 10) + ", ")
+	}
+	b.WriteString("\"inf\": " + strconv.FormatInt(h.counts[len(histogramBuckets)].Load(), 10))
+	b.WriteString(", \"sum_us\": " + strconv.FormatInt(h.Sum().Microseconds(), 10) + "}")
+	return b.String()
+}
+
+// Instrument returns a conn.Conn that records the statistics of the
+// transactions on c in MetricsFor(c.String()).
+//
+// The returned object also implements conn.ConnContext and conn.Limits.
+func Instrument(c conn.Conn) conn.Conn {
+	return &metricsConn{c: c, m: MetricsFor(c.String())}
+}
+
+// InstrumentI2C returns an i2c.Bus that records the statistics of the
+// transactions on b per device, keyed by the string of the equivalent
+// i2c.Dev, e.g. "I2C1(118)".
+//
+// The returned object also implements i2c.BusContext and i2c.Pins.
+func InstrumentI2C(b i2c.Bus) i2c.Bus {
+	return &metricsI2C{b: b}
+}
+
+// InstrumentSPI returns a spi.Conn that records the statistics of the
+// transactions on c in MetricsFor(c.String()). A TxPackets() call counts as
+// a single transaction.
+//
+// The returned object also implements conn.ConnContext, conn.Limits and
+// spi.Pins.
+func InstrumentSPI(c spi.Conn) spi.Conn {
+	return &metricsSPI{metricsConn{c: c, m: MetricsFor(c.String())}, c}
+}
+
+// InstrumentOneWire returns a onewire.Bus that records the statistics of the
+// transactions on b.
+//
+// Transactions starting with a "match ROM" command are keyed by the string of
+// the equivalent onewire.Dev, e.g. "1W(0x7a00000131825228)". Other
+// transactions and searches are keyed by b.String().
+//
+// The returned object also implements onewire.BusContext and onewire.Pins.
+func InstrumentOneWire(b onewire.Bus) onewire.Bus {
+	return &metricsOneWire{b: b, m: MetricsFor(b.String())}
+}
+
+//
+
+var (
+	metricsOnce sync.Once
+	metricsMu   sync.Mutex
+	metricsRoot *expvar.Map
+)
+
+// errorClass returns the key to use in Metrics.Errors.
+func errorClass(err error) string {
+	switch {
+	case errors.Is(err, conn.ErrAddrNACK):
+		return "addr_nack"
+	case errors.Is(err, conn.ErrDataNACK):
+		return "data_nack"
+	case errors.Is(err, conn.ErrArbitrationLost):
+		return "arbitration_lost"
+	case errors.Is(err, conn.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, conn.ErrBusBusy):
+		return "bus_busy"
+	case errors.Is(err, conn.ErrCRC):
+		return "crc"
+	case errors.Is(err, conn.ErrParity):
+		return "parity"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "other"
+	}
+}
+
+type metricsConn struct {
+	c conn.Conn
+	m *Metrics
+}
+
+func (c *metricsConn) String() string {
+	return c.c.String()
+}
+
+func (c *metricsConn) Tx(w, r []byte) error {
+	start := time.Now()
+	err := c.c.Tx(w, r)
+	c.m.record(start, len(w), len(r), err)
+	return err
+}
+
+func (c *metricsConn) TxContext(ctx context.Context, w, r []byte) error {
+	start := time.Now()
+	err := conn.WithContext(c.c).TxContext(ctx, w, r)
+	c.m.record(start, len(w), len(r), err)
+	return err
+}
+
+func (c *metricsConn) Duplex() conn.Duplex {
+	return c.c.Duplex()
+}
+
+func (c *metricsConn) MaxTxSize() int {
+	return maxTxSize(c.c)
+}
+
+//
+
+type metricsSPI struct {
+	metricsConn
+	s spi.Conn
+}
+
+func (c *metricsSPI) TxPackets(p []spi.Packet) error {
+	w, r := 0, 0
+	for i := range p {
+		w += len(p[i].W)
+		r += len(p[i].R)
+	}
+	start := time.Now()
+	err := c.s.TxPackets(p)
+	c.m.record(start, w, r, err)
+	return err
+}
+
+func (c *metricsSPI) CLK() gpio.PinOut {
+	return spiCLK(c.s)
+}
+
+func (c *metricsSPI) MOSI() gpio.PinOut {
+	return spiMOSI(c.s)
+}
+
+func (c *metricsSPI) MISO() gpio.PinIn {
+	return spiMISO(c.s)
+}
+
+func (c *metricsSPI) CS() gpio.PinOut {
+	return spiCS(c.s)
+}
+
+//
+
+type metricsI2C struct {
+	b    i2c.Bus
+	devs sync.Map // map[uint16]*Metrics
+}
+
+func (b *metricsI2C) String() string {
+	return b.b.String()
+}
+
+func (b *metricsI2C) Tx(addr uint16, w, r []byte) error {
+	start := time.Now()
+	err := b.b.Tx(addr, w, r)
+	b.metrics(addr).record(start, len(w), len(r), err)
+	return err
+}
+
+func (b *metricsI2C) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	start := time.Now()
+	err := i2cTxContext(ctx, b.b, addr, w, r)
+	b.metrics(addr).record(start, len(w), len(r), err)
+	return err
+}
+
+func (b *metricsI2C) SetSpeed(f physic.Frequency) error {
+	return b.b.SetSpeed(f)
+}
+
+func (b *metricsI2C) SCL() gpio.PinIO {
+	return i2cSCL(b.b)
+}
+
+func (b *metricsI2C) SDA() gpio.PinIO {
+	return i2cSDA(b.b)
+}
+
+func (b *metricsI2C) metrics(addr uint16) *Metrics {
+	if m, ok := b.devs.Load(addr); ok {
+		return m.(*Metrics)
+	}
+	d := i2c.Dev{Bus: b.b, Addr: addr}
+	m, _ := b.devs.LoadOrStore(addr, MetricsFor(d.String()))
+	return m.(*Metrics)
+}
+
+//
+
+type metricsOneWire struct {
+	b    onewire.Bus
+	m    *Metrics
+	devs sync.Map // map[onewire.Address]*Metrics
+}
+
+func (b *metricsOneWire) String() string {
+	return b.b.String()
+}
+
+func (b *metricsOneWire) Tx(w, r []byte, power onewire.Pullup) error {
+	start := time.Now()
+	err := b.b.Tx(w, r, power)
+	b.metrics(w).record(start, len(w), len(r), err)
+	return err
+}
+
+func (b *metricsOneWire) TxContext(ctx context.Context, w, r []byte, power onewire.Pullup) error {
+	start := time.Now()
+	err := oneWireTxContext(ctx, b.b, w, r, power)
+	b.metrics(w).record(start, len(w), len(r), err)
+	return err
+}
+
+func (b *metricsOneWire) Search(alarmOnly bool) ([]onewire.Address, error) {
+	start := time.Now()
+	addrs, err := b.b.Search(alarmOnly)
+	b.m.record(start, 0, 8*len(addrs), err)
+	return addrs, err
+}
+
+func (b *metricsOneWire) Q() gpio.PinIO {
+	return oneWireQ(b.b)
+}
+
+// metrics returns the Metrics for the device selected by the "match ROM"
+// command in w, if any.
+func (b *metricsOneWire) metrics(w []byte) *Metrics {
+	if len(w) < 9 || w[0] != 0x55 {
+		return b.m
+	}
+	var a onewire.Address
+	for i := 8; i > 0; i-- {
+		a = a<<8 | onewire.Address(w[i])
+	}
+	if m, ok := b.devs.Load(a); ok {
+		return m.(*Metrics)
+	}
+	d := onewire.Dev{Bus: b.b, Addr: a}
+	m, _ := b.devs.LoadOrStore(a, MetricsFor(d.String()))
+	return m.(*Metrics)
+}
+
+var _ expvar.Var = &Metrics{}
+var _ expvar.Var = &Histogram{}
+var _ conn.ConnContext = &metricsConn{}
+var _ conn.Limits = &metricsConn{}
+var _ spi.Conn = &metricsSPI{}
+var _ spi.Pins = &metricsSPI{}
+var _ conn.ConnContext = &metricsSPI{}
+var _ i2c.BusContext = &metricsI2C{}
+var _ i2c.Pins = &metricsI2C{}
+var _ onewire.BusContext = &metricsOneWire{}
+var _ onewire.Pins = &metricsOneWire{}
@@ -0,0 +1,256 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewiretest"
+	"periph.io/x/conn/v3/spi"
+)
+
+func TestInstrument(t *testing.T) {
+	resetMetrics("TestInstrument")
+	p := &namedPlayback{
+		Playback: conntest.Playback{
+			Ops: []conntest.IO{
+				{W: []byte{1}, R: []byte{2, 3}},
+				{W: []byte{1}, Err: conn.ErrTimeout},
+				{W: []byte{1}, Err: errors.New("oops")},
+			},
+			D: conn.Half,
+		},
+		name: "TestInstrument",
+	}
+	c := Instrument(p)
+	if s := c.String(); s != "TestInstrument" {
+		t.Fatal(s)
+	}
+	if d := c.Duplex(); d != conn.Half {
+		t.Fatal(d)
+	}
+	if l := c.(conn.Limits).MaxTxSize(); l != 0 {
+		t.Fatal(l)
+	}
+	if err := c.Tx([]byte{1}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.(conn.ConnContext).TxContext(context.Background(), []byte{1}, nil); err != conn.ErrTimeout {
+		t.Fatal(err)
+	}
+	if err := c.Tx([]byte{1}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	m := MetricsFor("TestInstrument")
+	if v := m.Tx.Value(); v != 3 {
+		t.Fatal(v)
+	}
+	if v := m.BytesWritten.Value(); v != 1 {
+		t.Fatal(v)
+	}
+	if v := m.BytesRead.Value(); v != 2 {
+		t.Fatal(v)
+	}
+	if v := m.Errors.Get("timeout").(*expvar.Int).Value(); v != 1 {
+		t.Fatal(v)
+	}
+	if v := m.Errors.Get("other").(*expvar.Int).Value(); v != 1 {
+		t.Fatal(v)
+	}
+	if v := m.Latency.Total(); v != 3 {
+		t.Fatal(v)
+	}
+	// Published as valid JSON.
+	var all map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(expvar.Get(MetricsVar).String()), &all); err != nil {
+		t.Fatal(err)
+	}
+	if v := all["TestInstrument"]["tx"]; v != 3. {
+		t.Fatal(v)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstrumentSPI(t *testing.T) {
+	resetMetrics("fakeSPI")
+	f := &fakeSPI{}
+	c := InstrumentSPI(f)
+	if err := c.Tx([]byte{1, 2}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.TxPackets([]spi.Packet{{W: []byte{1}}, {R: make([]byte, 3)}}); err != nil {
+		t.Fatal(err)
+	}
+	m := MetricsFor("fakeSPI")
+	if v := m.Tx.Value(); v != 2 {
+		t.Fatal(v)
+	}
+	if v := m.BytesWritten.Value(); v != 3 {
+		t.Fatal(v)
+	}
+	if v := m.BytesRead.Value(); v != 5 {
+		t.Fatal(v)
+	}
+	p := c.(spi.Pins)
+	if p.CLK() == nil || p.MOSI() == nil || p.MISO() == nil || p.CS() == nil {
+		t.Fatal("expected INVALID")
+	}
+}
+
+func TestInstrumentI2C(t *testing.T) {
+	resetMetrics("playback(64)", "playback(65)")
+	p := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{1}, R: []byte{2}},
+			{Addr: 0x41, W: []byte{1}, Err: conn.ErrAddrNACK},
+			{Addr: 0x40, W: []byte{1}},
+		},
+	}
+	b := InstrumentI2C(p)
+	if s := b.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if err := b.SetSpeed(0); err != nil {
+		t.Fatal(err)
+	}
+	if p := b.(i2c.Pins); p.SCL() != nil || p.SDA() != nil {
+		t.Fatal("expected nil pins")
+	}
+	if err := b.Tx(0x40, []byte{1}, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx(0x41, []byte{1}, nil); err != conn.ErrAddrNACK {
+		t.Fatal(err)
+	}
+	if err := b.(i2c.BusContext).TxContext(context.Background(), 0x40, []byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	m := MetricsFor("playback(64)")
+	if v := m.Tx.Value(); v != 2 {
+		t.Fatal(v)
+	}
+	if v := m.BytesWritten.Value(); v != 2 {
+		t.Fatal(v)
+	}
+	m = MetricsFor("playback(65)")
+	if v := m.Errors.Get("addr_nack").(*expvar.Int).Value(); v != 1 {
+		t.Fatal(v)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstrumentOneWire(t *testing.T) {
+	resetMetrics("playback", "playback(0x7a00000131825228)")
+	p := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: []byte{0x55, 0x28, 0x52, 0x82, 0x31, 0x01, 0x00, 0x00, 0x7a, 0xbe}, R: []byte{1, 2}},
+			{W: []byte{0xcc, 0x44}, Err: conn.ErrCRC},
+			{W: []byte{0xf0}},
+		},
+		Devices: []onewire.Address{0x7a00000131825228},
+	}
+	b := InstrumentOneWire(p)
+	if s := b.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if q := b.(onewire.Pins).Q(); q != nil {
+		t.Fatal(q)
+	}
+	d := onewire.Dev{Bus: b, Addr: 0x7a00000131825228}
+	if err := d.Tx([]byte{0xbe}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.(onewire.BusContext).TxContext(context.Background(), []byte{0xcc, 0x44}, nil, onewire.WeakPullup); err != conn.ErrCRC {
+		t.Fatal(err)
+	}
+	if _, err := b.Search(false); err != nil {
+		t.Fatal(err)
+	}
+	m := MetricsFor("playback(0x7a00000131825228)")
+	if v := m.BytesRead.Value(); v != 2 {
+		t.Fatal(v)
+	}
+	m = MetricsFor("playback")
+	if v := m.Errors.Get("crc").(*expvar.Int).Value(); v != 1 {
+		t.Fatal(v)
+	}
+	if v := m.BytesRead.Value(); v != 8 {
+		t.Fatal(v)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := Histogram{}
+	h.Observe(time.Microsecond)
+	h.Observe(10 * time.Microsecond)
+	h.Observe(11 * time.Microsecond)
+	h.Observe(time.Hour)
+	if h.Count(0) != 2 || h.Count(1) != 1 || h.Count(len(HistogramBuckets())) != 1 || h.Total() != 4 {
+		t.Fatal(h.String())
+	}
+	if s := h.String(); s != `{"10µs": 2, "100µs": 1, "1ms": 0, "10ms": 0, "100ms": 0, "1s": 0, "inf": 1, "sum_us": 3600000022}` {
+		t.Fatal(s)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	data := []struct {
+		err      error
+		expected string
+	}{
+		{conn.ErrAddrNACK, "addr_nack"},
+		{conn.ErrDataNACK, "data_nack"},
+		{conn.ErrArbitrationLost, "arbitration_lost"},
+		{conn.ErrTimeout, "timeout"},
+		{context.DeadlineExceeded, "timeout"},
+		{conn.ErrBusBusy, "bus_busy"},
+		{conn.ErrCRC, "crc"},
+		{conn.ErrParity, "parity"},
+		{context.Canceled, "canceled"},
+		{errors.New("a"), "other"},
+	}
+	for i, line := range data {
+		if c := errorClass(line.err); c != line.expected {
+			t.Fatalf("#%d: %s != %s", i, c, line.expected)
+		}
+	}
+}
+
+//
+
+// resetMetrics forgets the Metrics of the devices, since the registry is
+// global.
+func resetMetrics(names ...string) {
+	for _, n := range names {
+		MetricsFor(n)
+		metricsRoot.Delete(n)
+	}
+}
+
+type namedPlayback struct {
+	conntest.Playback
+	name string
+}
+
+func (n *namedPlayback) String() string {
+	return n.name
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/physic"
+)
+
+// RetryPolicy determines how failed transactions are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a transaction is tried,
+	// including the first one. 0 and 1 both mean that no retry is done.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It is doubled before each
+	// subsequent retry.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between two attempts. 0 means no cap.
+	MaxBackoff time.Duration
+	// Jitter is the fraction of each delay, between 0 and 1, that is
+	// randomized to desynchronize multiple clients. For example with 0.5, a
+	// 10ms delay becomes a random value between 5ms and 10ms.
+	Jitter float64
+	// Retryable returns true if the transaction that failed with err should be
+	// retried. When nil, conn.IsTemporary is used.
+	//
+	// Context errors are never retried.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy is a reasonable policy for devices on long cables.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     time.Millisecond,
+	MaxBackoff:  100 * time.Millisecond,
+	Jitter:      0.5,
+}
+
+// Retry returns a conn.Conn that retries failed transactions on c according
+// to p.
+//
+// The returned object also implements conn.ConnContext and conn.Limits. The
+// delay between attempts is interrupted when the context is done.
+func Retry(c conn.Conn, p RetryPolicy) conn.Conn {
+	return &retryConn{c: c, r: newRetrier(p)}
+}
+
+// RetryI2C returns an i2c.Bus that retries failed transactions on b according
+// to p.
+//
+// The returned object also implements i2c.BusContext and i2c.Pins.
+func RetryI2C(b i2c.Bus, p RetryPolicy) i2c.Bus {
+	return &retryI2C{b: b, r: newRetrier(p)}
+}
+
+// RetryOneWire returns a onewire.Bus that retries failed transactions and
+// searches on b according to p.
+//
+// The returned object also implements onewire.BusContext and onewire.Pins.
+func RetryOneWire(b onewire.Bus, p RetryPolicy) onewire.Bus {
+	return &retryOneWire{b: b, r: newRetrier(p)}
+}
+
+//
+
+// retrier implements the retry loop shared by all the wrappers.
+type retrier struct {
+	p     RetryPolicy
+	clock clockwork.Clock
+	rand  func() float64
+}
+
+func newRetrier(p RetryPolicy) *retrier {
+	return &retrier{p: p, clock: clockwork.NewRealClock(), rand: rand.Float64}
+}
+
+// do calls tx until it succeeds, the error is not retryable, ctx is done or
+// the maximum number of attempts is reached.
+func (r *retrier) do(ctx context.Context, tx func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := tx()
+		if err == nil || attempt >= r.p.MaxAttempts || ctx.Err() != nil || !r.retryable(err) {
+			return err
+		}
+		if d := r.delay(attempt); d > 0 {
+			t := r.clock.NewTimer(d)
+			select {
+			case <-t.Chan():
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (r *retrier) retryable(err error) bool {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+	if r.p.Retryable != nil {
+		return r.p.Retryable(err)
+	}
+	return conn.IsTemporary(err)
+}
+
+// delay returns the delay to wait after the attempt-th failed attempt.
+func (r *retrier) delay(attempt int) time.Duration {
+	d := r.p.Backoff
+	for i := 1; i < attempt && d < math.MaxInt64/2; i++ {
+		d *= 2
+	}
+	if r.p.MaxBackoff > 0 && d > r.p.MaxBackoff {
+		d = r.p.MaxBackoff
+	}
+	if r.p.Jitter > 0 && d > 0 {
+		j := r.p.Jitter
+		if j > 1 {
+			j = 1
+		}
+		d -= time.Duration(float64(d) * j * r.rand())
+	}
+	return d
+}
+
+//
+
+type retryConn struct {
+	c conn.Conn
+	r *retrier
+}
+
+func (c *retryConn) String() string {
+	return c.c.String()
+}
+
+func (c *retryConn) Tx(w, r []byte) error {
+	return c.TxContext(context.Background(), w, r)
+}
+
+func (c *retryConn) TxContext(ctx context.Context, w, r []byte) error {
+	cc := conn.WithContext(c.c)
+	return c.r.do(ctx, func() error {
+		return cc.TxContext(ctx, w, r)
+	})
+}
+
+func (c *retryConn) Duplex() conn.Duplex {
+	return c.c.Duplex()
+}
+
+func (c *retryConn) MaxTxSize() int {
+	if l, ok := c.c.(conn.Limits); ok {
+		return l.MaxTxSize()
+	}
+	return 0
+}
+
+//
+
+type retryI2C struct {
+	b i2c.Bus
+	r *retrier
+}
+
+func (b *retryI2C) String() string {
+	return b.b.String()
+}
+
+func (b *retryI2C) Tx(addr uint16, w, r []byte) error {
+	return b.TxContext(context.Background(), addr, w, r)
+}
+
+func (b *retryI2C) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	return b.r.do(ctx, func() error {
+		return i2cTxContext(ctx, b.b, addr, w, r)
+	})
+}
+
+func (b *retryI2C) SetSpeed(f physic.Frequency) error {
+	return b.b.SetSpeed(f)
+}
+
+func (b *retryI2C) SCL() gpio.PinIO {
+	return i2cSCL(b.b)
+}
+
+func (b *retryI2C) SDA() gpio.PinIO {
+	return i2cSDA(b.b)
+}
+
+//
+
+type retryOneWire struct {
+	b onewire.Bus
+	r *retrier
+}
+
+func (b *retryOneWire) String() string {
+	return b.b.String()
+}
+
+func (b *retryOneWire) Tx(w, r []byte, power onewire.Pullup) error {
+	return b.TxContext(context.Background(), w, r, power)
+}
+
+func (b *retryOneWire) TxContext(ctx context.Context, w, r []byte, power onewire.Pullup) error {
+	return b.r.do(ctx, func() error {
+		return oneWireTxContext(ctx, b.b, w, r, power)
+	})
+}
+
+func (b *retryOneWire) Search(alarmOnly bool) ([]onewire.Address, error) {
+	var addrs []onewire.Address
+	err := b.r.do(context.Background(), func() error {
+		var err error
+		addrs, err = b.b.Search(alarmOnly)
+		return err
+	})
+	return addrs, err
+}
+
+func (b *retryOneWire) Q() gpio.PinIO {
+	return oneWireQ(b.b)
+}
+
+var _ conn.ConnContext = &retryConn{}
+var _ conn.Limits = &retryConn{}
+var _ i2c.BusContext = &retryI2C{}
+var _ i2c.Pins = &retryI2C{}
+var _ onewire.BusContext = &retryOneWire{}
+var _ onewire.Pins = &retryOneWire{}
@@ -0,0 +1,233 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewiretest"
+)
+
+func TestRetry(t *testing.T) {
+	p := &conntest.Playback{
+		Ops: []conntest.IO{
+			{W: []byte{1}, R: []byte{0}, Err: conn.ErrAddrNACK},
+			{W: []byte{1}, R: []byte{0}, Err: conn.ErrCRC},
+			{W: []byte{1}, R: []byte{42}},
+		},
+		D: conn.Half,
+	}
+	c := Retry(p, RetryPolicy{MaxAttempts: 3})
+	if s := c.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if d := c.Duplex(); d != conn.Half {
+		t.Fatal(d)
+	}
+	if l := c.(conn.Limits).MaxTxSize(); l != 0 {
+		t.Fatal(l)
+	}
+	r := make([]byte, 1)
+	if err := c.Tx([]byte{1}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 42 {
+		t.Fatal(r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetry_Exhausted(t *testing.T) {
+	p := &conntest.Playback{
+		Ops: []conntest.IO{
+			{W: []byte{1}, Err: conn.ErrTimeout},
+			{W: []byte{1}, Err: conn.ErrBusBusy},
+		},
+	}
+	c := Retry(p, RetryPolicy{MaxAttempts: 2})
+	if err := c.Tx([]byte{1}, nil); err != conn.ErrBusBusy {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetry_NotRetryable(t *testing.T) {
+	p := &conntest.Playback{Ops: []conntest.IO{{W: []byte{1}, Err: conn.ErrDataNACK}}}
+	c := Retry(p, RetryPolicy{MaxAttempts: 5})
+	if err := c.Tx([]byte{1}, nil); err != conn.ErrDataNACK {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetry_Retryable(t *testing.T) {
+	exErr := errors.New("flaky")
+	p := &conntest.Playback{Ops: []conntest.IO{{W: []byte{1}, Err: exErr}, {W: []byte{1}}}}
+	c := Retry(p, RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   func(err error) bool { return err == exErr },
+	})
+	if err := c.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetry_Backoff(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	p := &conntest.Playback{
+		Ops: []conntest.IO{
+			{W: []byte{1}, Err: conn.ErrAddrNACK},
+			{W: []byte{1}, Err: conn.ErrAddrNACK},
+			{W: []byte{1}},
+		},
+	}
+	c := Retry(p, RetryPolicy{MaxAttempts: 3, Backoff: time.Second})
+	c.(*retryConn).r.clock = clock
+	done := make(chan error)
+	go func() {
+		done <- c.Tx([]byte{1}, nil)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	clock.BlockUntil(1)
+	// The second delay is doubled.
+	clock.Advance(time.Second)
+	select {
+	case err := <-done:
+		t.Fatalf("unexpected early return: %v", err)
+	default:
+	}
+	clock.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetry_Cancel(t *testing.T) {
+	p := &conntest.Playback{Ops: []conntest.IO{{W: []byte{1}, Err: conn.ErrAddrNACK}}}
+	clock := clockwork.NewFakeClock()
+	c := Retry(p, RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}).(conn.ConnContext)
+	c.(*retryConn).r.clock = clock
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		clock.BlockUntil(1)
+		cancel()
+	}()
+	if err := c.TxContext(ctx, []byte{1}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	r := newRetrier(RetryPolicy{Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Jitter: 0.5})
+	r.rand = func() float64 { return 1 }
+	data := []time.Duration{500 * time.Microsecond, time.Millisecond, 2 * time.Millisecond, 2500 * time.Microsecond, 2500 * time.Microsecond}
+	for i, expected := range data {
+		if d := r.delay(i + 1); d != expected {
+			t.Fatalf("#%d: %s != %s", i, d, expected)
+		}
+	}
+	r = newRetrier(RetryPolicy{Backoff: time.Hour})
+	if d := r.delay(100); d <= 0 {
+		t.Fatal(d)
+	}
+}
+
+func TestRetryI2C(t *testing.T) {
+	p := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0}, Err: conn.ErrAddrNACK},
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}},
+		},
+	}
+	b := RetryI2C(p, RetryPolicy{MaxAttempts: 2})
+	if s := b.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if err := b.SetSpeed(0); err != nil {
+		t.Fatal(err)
+	}
+	if p := b.(i2c.Pins); p.SCL() != nil || p.SDA() != nil {
+		t.Fatal("expected nil pins")
+	}
+	d := i2c.Dev{Bus: b, Addr: 0x76}
+	r := make([]byte, 1)
+	if err := d.Tx([]byte{0xd0}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 0x60 {
+		t.Fatal(r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetryOneWire(t *testing.T) {
+	p := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: []byte{0xcc, 0x44}, Pull: onewire.StrongPullup, Err: conn.ErrCRC},
+			{W: []byte{0xcc, 0x44}, Pull: onewire.StrongPullup},
+		},
+	}
+	b := RetryOneWire(p, RetryPolicy{MaxAttempts: 2})
+	if s := b.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if q := b.(onewire.Pins).Q(); q != nil {
+		t.Fatal(q)
+	}
+	if err := b.Tx([]byte{0xcc, 0x44}, nil, onewire.StrongPullup); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetryOneWire_Search(t *testing.T) {
+	p := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: []byte{0xf0}, Err: conn.ErrBusBusy},
+			{W: []byte{0xf0}},
+		},
+		Devices: []onewire.Address{0x7a00000131825228},
+	}
+	b := RetryOneWire(p, RetryPolicy{MaxAttempts: 2})
+	addrs, err := b.Search(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 1 || addrs[0] != p.Devices[0] {
+		t.Fatal(addrs)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
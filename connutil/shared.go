@@ -0,0 +1,587 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// SharedI2C is an i2c.Bus that serializes the transactions on an underlying
+// bus and that can hand out exclusive sessions.
+//
+// Each Tx() call is atomic. Use Session() to run a sequence of transactions
+// without traffic from other goroutines in between. Waiters are served in
+// FIFO order, whether they wait for a single transaction or for a session.
+//
+// Calling a method of SharedI2C while holding a session from the same
+// goroutine deadlocks; use the session instead.
+type SharedI2C struct {
+	b  i2c.Bus
+	mu fairMutex
+}
+
+// NewSharedI2C returns a SharedI2C over b.
+func NewSharedI2C(b i2c.Bus) *SharedI2C {
+	return &SharedI2C{b: b}
+}
+
+func (s *SharedI2C) String() string {
+	return s.b.String()
+}
+
+// Tx implements i2c.Bus.
+func (s *SharedI2C) Tx(addr uint16, w, r []byte) error {
+	return s.TxContext(context.Background(), addr, w, r)
+}
+
+// TxContext implements i2c.BusContext.
+//
+// ctx also applies to the wait for the bus. It is only forwarded to the
+// transaction if the bus implements i2c.BusContext, so the bus is held until
+// the transaction really ends.
+func (s *SharedI2C) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	if err := s.mu.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.unlock()
+	return i2cTxHeld(ctx, s.b, addr, w, r)
+}
+
+// SetSpeed implements i2c.Bus.
+func (s *SharedI2C) SetSpeed(f physic.Frequency) error {
+	if err := s.mu.lock(context.Background()); err != nil {
+		return err
+	}
+	defer s.mu.unlock()
+	return s.b.SetSpeed(f)
+}
+
+// SCL implements i2c.Pins.
+func (s *SharedI2C) SCL() gpio.PinIO {
+	return i2cSCL(s.b)
+}
+
+// SDA implements i2c.Pins.
+func (s *SharedI2C) SDA() gpio.PinIO {
+	return i2cSDA(s.b)
+}
+
+// Session waits for exclusive access to the bus and returns a session
+// holding it until Release() is called.
+//
+// It returns ctx.Err() if ctx is done before the bus is available.
+func (s *SharedI2C) Session(ctx context.Context) (*I2CSession, error) {
+	if err := s.mu.lock(ctx); err != nil {
+		return nil, err
+	}
+	return &I2CSession{s: s}, nil
+}
+
+// I2CSession is an i2c.Bus with exclusive access to a SharedI2C.
+//
+// It is meant to be used by a single goroutine.
+type I2CSession struct {
+	s        *SharedI2C
+	released atomic.Bool
+}
+
+func (s *I2CSession) String() string {
+	return s.s.String()
+}
+
+// Tx implements i2c.Bus.
+//
+// It returns an error once the session is released.
+func (s *I2CSession) Tx(addr uint16, w, r []byte) error {
+	return s.TxContext(context.Background(), addr, w, r)
+}
+
+// TxContext implements i2c.BusContext.
+func (s *I2CSession) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	if s.released.Load() {
+		return errSessionReleased
+	}
+	return i2cTxHeld(ctx, s.s.b, addr, w, r)
+}
+
+// SetSpeed implements i2c.Bus.
+func (s *I2CSession) SetSpeed(f physic.Frequency) error {
+	if s.released.Load() {
+		return errSessionReleased
+	}
+	return s.s.b.SetSpeed(f)
+}
+
+// SCL implements i2c.Pins.
+func (s *I2CSession) SCL() gpio.PinIO {
+	return i2cSCL(s.s.b)
+}
+
+// SDA implements i2c.Pins.
+func (s *I2CSession) SDA() gpio.PinIO {
+	return i2cSDA(s.s.b)
+}
+
+// Release gives the bus back. It is safe to call it multiple times.
+func (s *I2CSession) Release() {
+	if s.released.CompareAndSwap(false, true) {
+		s.s.mu.unlock()
+	}
+}
+
+// SharedSPI is a spi.Conn that serializes the transactions on an underlying
+// connection and that can hand out exclusive sessions.
+//
+// It has the same semantics as SharedI2C.
+type SharedSPI struct {
+	c  spi.Conn
+	mu fairMutex
+}
+
+// NewSharedSPI returns a SharedSPI over c.
+func NewSharedSPI(c spi.Conn) *SharedSPI {
+	return &SharedSPI{c: c}
+}
+
+func (s *SharedSPI) String() string {
+	return s.c.String()
+}
+
+// Tx implements spi.Conn.
+func (s *SharedSPI) Tx(w, r []byte) error {
+	return s.TxContext(context.Background(), w, r)
+}
+
+// TxContext implements conn.ConnContext.
+//
+// ctx also applies to the wait for the connection. Like with SharedI2C, it is
+// only forwarded to the transaction if the connection implements
+// conn.ConnContext.
+func (s *SharedSPI) TxContext(ctx context.Context, w, r []byte) error {
+	if err := s.mu.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.unlock()
+	return connTxHeld(ctx, s.c, w, r)
+}
+
+// TxPackets implements spi.Conn.
+func (s *SharedSPI) TxPackets(p []spi.Packet) error {
+	if err := s.mu.lock(context.Background()); err != nil {
+		return err
+	}
+	defer s.mu.unlock()
+	return s.c.TxPackets(p)
+}
+
+// Duplex implements spi.Conn.
+func (s *SharedSPI) Duplex() conn.Duplex {
+	return s.c.Duplex()
+}
+
+// MaxTxSize implements conn.Limits.
+func (s *SharedSPI) MaxTxSize() int {
+	return maxTxSize(s.c)
+}
+
+// CLK implements spi.Pins.
+func (s *SharedSPI) CLK() gpio.PinOut {
+	return spiCLK(s.c)
+}
+
+// MOSI implements spi.Pins.
+func (s *SharedSPI) MOSI() gpio.PinOut {
+	return spiMOSI(s.c)
+}
+
+// MISO implements spi.Pins.
+func (s *SharedSPI) MISO() gpio.PinIn {
+	return spiMISO(s.c)
+}
+
+// CS implements spi.Pins.
+func (s *SharedSPI) CS() gpio.PinOut {
+	return spiCS(s.c)
+}
+
+// Session waits for exclusive access to the connection and returns a
+// session holding it until Release() is called.
+//
+// It returns ctx.Err() if ctx is done before the connection is available.
+func (s *SharedSPI) Session(ctx context.Context) (*SPISession, error) {
+	if err := s.mu.lock(ctx); err != nil {
+		return nil, err
+	}
+	return &SPISession{s: s}, nil
+}
+
+// SPISession is a spi.Conn with exclusive access to a SharedSPI.
+//
+// It is meant to be used by a single goroutine.
+type SPISession struct {
+	s        *SharedSPI
+	released atomic.Bool
+}
+
+func (s *SPISession) String() string {
+	return s.s.String()
+}
+
+// Tx implements spi.Conn.
+//
+// It returns an error once the session is released.
+func (s *SPISession) Tx(w, r []byte) error {
+	return s.TxContext(context.Background(), w, r)
+}
+
+// TxContext implements conn.ConnContext.
+func (s *SPISession) TxContext(ctx context.Context, w, r []byte) error {
+	if s.released.Load() {
+		return errSessionReleased
+	}
+	return connTxHeld(ctx, s.s.c, w, r)
+}
+
+// TxPackets implements spi.Conn.
+func (s *SPISession) TxPackets(p []spi.Packet) error {
+	if s.released.Load() {
+		return errSessionReleased
+	}
+	return s.s.c.TxPackets(p)
+}
+
+// Duplex implements spi.Conn.
+func (s *SPISession) Duplex() conn.Duplex {
+	return s.s.c.Duplex()
+}
+
+// MaxTxSize implements conn.Limits.
+func (s *SPISession) MaxTxSize() int {
+	return maxTxSize(s.s.c)
+}
+
+// CLK implements spi.Pins.
+func (s *SPISession) CLK() gpio.PinOut {
+	return spiCLK(s.s.c)
+}
+
+// MOSI implements spi.Pins.
+func (s *SPISession) MOSI() gpio.PinOut {
+	return spiMOSI(s.s.c)
+}
+
+// MISO implements spi.Pins.
+func (s *SPISession) MISO() gpio.PinIn {
+	return spiMISO(s.s.c)
+}
+
+// CS implements spi.Pins.
+func (s *SPISession) CS() gpio.PinOut {
+	return spiCS(s.s.c)
+}
+
+// Release gives the connection back. It is safe to call it multiple times.
+func (s *SPISession) Release() {
+	if s.released.CompareAndSwap(false, true) {
+		s.s.mu.unlock()
+	}
+}
+
+// SharedOneWire is a onewire.Bus that serializes the transactions on an
+// underlying bus and that can hand out exclusive sessions.
+//
+// It has the same semantics as SharedI2C. A Search() is atomic.
+type SharedOneWire struct {
+	b  onewire.Bus
+	mu fairMutex
+}
+
+// NewSharedOneWire returns a SharedOneWire over b.
+func NewSharedOneWire(b onewire.Bus) *SharedOneWire {
+	return &SharedOneWire{b: b}
+}
+
+func (s *SharedOneWire) String() string {
+	return s.b.String()
+}
+
+// Tx implements onewire.Bus.
+func (s *SharedOneWire) Tx(w, r []byte, power onewire.Pullup) error {
+	return s.TxContext(context.Background(), w, r, power)
+}
+
+// TxContext implements onewire.BusContext.
+//
+// ctx also applies to the wait for the bus. Like with SharedI2C, it is only
+// forwarded to the transaction if the bus implements onewire.BusContext.
+func (s *SharedOneWire) TxContext(ctx context.Context, w, r []byte, power onewire.Pullup) error {
+	if err := s.mu.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.unlock()
+	return oneWireTxHeld(ctx, s.b, w, r, power)
+}
+
+// Search implements onewire.Bus.
+func (s *SharedOneWire) Search(alarmOnly bool) ([]onewire.Address, error) {
+	if err := s.mu.lock(context.Background()); err != nil {
+		return nil, err
+	}
+	defer s.mu.unlock()
+	return s.b.Search(alarmOnly)
+}
+
+// Q implements onewire.Pins.
+func (s *SharedOneWire) Q() gpio.PinIO {
+	return oneWireQ(s.b)
+}
+
+// Session waits for exclusive access to the bus and returns a session
+// holding it until Release() is called.
+//
+// It returns ctx.Err() if ctx is done before the bus is available.
+func (s *SharedOneWire) Session(ctx context.Context) (*OneWireSession, error) {
+	if err := s.mu.lock(ctx); err != nil {
+		return nil, err
+	}
+	return &OneWireSession{s: s}, nil
+}
+
+// OneWireSession is a onewire.Bus with exclusive access to a SharedOneWire.
+//
+// It is meant to be used by a single goroutine.
+type OneWireSession struct {
+	s        *SharedOneWire
+	released atomic.Bool
+}
+
+func (s *OneWireSession) String() string {
+	return s.s.String()
+}
+
+// Tx implements onewire.Bus.
+//
+// It returns an error once the session is released.
+func (s *OneWireSession) Tx(w, r []byte, power onewire.Pullup) error {
+	return s.TxContext(context.Background(), w, r, power)
+}
+
+// TxContext implements onewire.BusContext.
+func (s *OneWireSession) TxContext(ctx context.Context, w, r []byte, power onewire.Pullup) error {
+	if s.released.Load() {
+		return errSessionReleased
+	}
+	return oneWireTxHeld(ctx, s.s.b, w, r, power)
+}
+
+// Search implements onewire.Bus.
+func (s *OneWireSession) Search(alarmOnly bool) ([]onewire.Address, error) {
+	if s.released.Load() {
+		return nil, errSessionReleased
+	}
+	return s.s.b.Search(alarmOnly)
+}
+
+// Q implements onewire.Pins.
+func (s *OneWireSession) Q() gpio.PinIO {
+	return oneWireQ(s.s.b)
+}
+
+// Release gives the bus back. It is safe to call it multiple times.
+func (s *OneWireSession) Release() {
+	if s.released.CompareAndSwap(false, true) {
+		s.s.mu.unlock()
+	}
+}
+
+//
+
+var errSessionReleased = errors.New("connutil: session already released")
+
+// fairMutex is a mutual exclusion lock that grants the lock in FIFO order and
+// supports cancellation while waiting.
+//
+// The zero value is unlocked.
+type fairMutex struct {
+	mu      sync.Mutex
+	held    bool
+	waiters []chan struct{}
+}
+
+// lock acquires the lock, or returns ctx.Err() if ctx is done first.
+func (f *fairMutex) lock(ctx context.Context) error {
+	f.mu.Lock()
+	if !f.held {
+		f.held = true
+		f.mu.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	f.waiters = append(f.waiters, ch)
+	f.mu.Unlock()
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+	}
+	f.mu.Lock()
+	for i := range f.waiters {
+		if f.waiters[i] == ch {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+	f.mu.Unlock()
+	// The lock was handed over concurrently with the cancellation; pass it on.
+	f.unlock()
+	return ctx.Err()
+}
+
+// unlock releases the lock, handing it over to the oldest waiter if any.
+func (f *fairMutex) unlock() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.held {
+		panic("connutil: unlock of unlocked fairMutex")
+	}
+	if len(f.waiters) == 0 {
+		f.held = false
+		return
+	}
+	close(f.waiters[0])
+	f.waiters = f.waiters[1:]
+}
+
+// i2cTxHeld runs a transaction while the bus is held.
+//
+// Unlike i2cTxContext(), it doesn't return before a Tx() without context
+// support ends, as the bus would be handed over while the transaction is
+// still on the wire. ctx is then only checked before starting.
+func i2cTxHeld(ctx context.Context, b i2c.Bus, addr uint16, w, r []byte) error {
+	if bc, ok := b.(i2c.BusContext); ok {
+		return bc.TxContext(ctx, addr, w, r)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.Tx(addr, w, r)
+}
+
+func i2cTxContext(ctx context.Context, b i2c.Bus, addr uint16, w, r []byte) error {
+	if bc, ok := b.(i2c.BusContext); ok {
+		return bc.TxContext(ctx, addr, w, r)
+	}
+	return conn.DoContext(ctx, w, r, func(w, r []byte) error {
+		return b.Tx(addr, w, r)
+	})
+}
+
+func i2cSCL(b i2c.Bus) gpio.PinIO {
+	if p, ok := b.(i2c.Pins); ok {
+		return p.SCL()
+	}
+	return gpio.INVALID
+}
+
+func i2cSDA(b i2c.Bus) gpio.PinIO {
+	if p, ok := b.(i2c.Pins); ok {
+		return p.SDA()
+	}
+	return gpio.INVALID
+}
+
+func spiCLK(c spi.Conn) gpio.PinOut {
+	if p, ok := c.(spi.Pins); ok {
+		return p.CLK()
+	}
+	return gpio.INVALID
+}
+
+func spiMOSI(c spi.Conn) gpio.PinOut {
+	if p, ok := c.(spi.Pins); ok {
+		return p.MOSI()
+	}
+	return gpio.INVALID
+}
+
+func spiMISO(c spi.Conn) gpio.PinIn {
+	if p, ok := c.(spi.Pins); ok {
+		return p.MISO()
+	}
+	return gpio.INVALID
+}
+
+func spiCS(c spi.Conn) gpio.PinOut {
+	if p, ok := c.(spi.Pins); ok {
+		return p.CS()
+	}
+	return gpio.INVALID
+}
+
+// connTxHeld is the conn.Conn version of i2cTxHeld().
+func connTxHeld(ctx context.Context, c conn.Conn, w, r []byte) error {
+	if cc, ok := c.(conn.ConnContext); ok {
+		return cc.TxContext(ctx, w, r)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Tx(w, r)
+}
+
+// oneWireTxHeld is the onewire.Bus version of i2cTxHeld().
+func oneWireTxHeld(ctx context.Context, b onewire.Bus, w, r []byte, power onewire.Pullup) error {
+	if bc, ok := b.(onewire.BusContext); ok {
+		return bc.TxContext(ctx, w, r, power)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.Tx(w, r, power)
+}
+
+func oneWireTxContext(ctx context.Context, b onewire.Bus, w, r []byte, power onewire.Pullup) error {
+	if bc, ok := b.(onewire.BusContext); ok {
+		return bc.TxContext(ctx, w, r, power)
+	}
+	return conn.DoContext(ctx, w, r, func(w, r []byte) error {
+		return b.Tx(w, r, power)
+	})
+}
+
+func oneWireQ(b onewire.Bus) gpio.PinIO {
+	if p, ok := b.(onewire.Pins); ok {
+		return p.Q()
+	}
+	return gpio.INVALID
+}
+
+var _ i2c.BusContext = &SharedI2C{}
+var _ i2c.Pins = &SharedI2C{}
+var _ i2c.BusContext = &I2CSession{}
+var _ i2c.Pins = &I2CSession{}
+var _ spi.Conn = &SharedSPI{}
+var _ spi.Pins = &SharedSPI{}
+var _ conn.ConnContext = &SharedSPI{}
+var _ conn.Limits = &SharedSPI{}
+var _ spi.Conn = &SPISession{}
+var _ spi.Pins = &SPISession{}
+var _ conn.ConnContext = &SPISession{}
+var _ conn.Limits = &SPISession{}
+var _ onewire.BusContext = &SharedOneWire{}
+var _ onewire.Pins = &SharedOneWire{}
+var _ onewire.BusContext = &OneWireSession{}
+var _ onewire.Pins = &OneWireSession{}
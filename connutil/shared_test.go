@@ -0,0 +1,323 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewiretest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+func TestSharedI2C(t *testing.T) {
+	b := &orderBus{}
+	s := NewSharedI2C(b)
+	if str := s.String(); str != "orderBus" {
+		t.Fatal(str)
+	}
+	if err := s.SetSpeed(physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if s.SCL() != gpio.INVALID || s.SDA() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	ss, err := s.Session(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Tx(1, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		done <- s.Tx(2, nil, nil)
+	}()
+	waitWaiters(&s.mu, 1)
+	// The other goroutine can't sneak in.
+	if err := ss.TxContext(context.Background(), 3, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.SetSpeed(physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if str := ss.String(); str != "orderBus" {
+		t.Fatal(str)
+	}
+	if ss.SCL() != gpio.INVALID || ss.SDA() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	ss.Release()
+	ss.Release()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(b.addrs, []uint16{1, 3, 2}) {
+		t.Fatal(b.addrs)
+	}
+	if err := ss.Tx(4, nil, nil); err != errSessionReleased {
+		t.Fatal(err)
+	}
+	if err := ss.SetSpeed(physic.KiloHertz); err != errSessionReleased {
+		t.Fatal(err)
+	}
+}
+
+func TestSharedI2C_Cancel(t *testing.T) {
+	s := NewSharedI2C(&orderBus{})
+	ss, err := s.Session(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := s.Session(ctx)
+		done <- err
+	}()
+	waitWaiters(&s.mu, 1)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := s.TxContext(ctx, 1, nil, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	ss.Release()
+	if err := s.Tx(1, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSharedI2C_Cancel_held(t *testing.T) {
+	b := &slowBus{started: make(chan struct{}), release: make(chan struct{})}
+	s := NewSharedI2C(b)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.TxContext(ctx, 1, nil, nil)
+	}()
+	<-b.started
+	cancel()
+	// The bus doesn't support a context, so it is held until Tx() returns.
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+	if _, err := s.Session(ctx2); err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+	close(b.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	ss, err := s.Session(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.Release()
+}
+
+func TestSharedSPI(t *testing.T) {
+	f := &fakeSPI{max: 4}
+	s := NewSharedSPI(f)
+	if str := s.String(); str != "fakeSPI" {
+		t.Fatal(str)
+	}
+	if d := s.Duplex(); d != conn.Full {
+		t.Fatal(d)
+	}
+	if m := s.MaxTxSize(); m != 4 {
+		t.Fatal(m)
+	}
+	if s.CLK() != gpio.INVALID || s.MOSI() != gpio.INVALID || s.MISO() != gpio.INVALID || s.CS() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	if err := s.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.TxPackets([]spi.Packet{{W: []byte{1}}}); err != nil {
+		t.Fatal(err)
+	}
+	ss, err := s.Session(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := ss.String(); str != "fakeSPI" {
+		t.Fatal(str)
+	}
+	if d := ss.Duplex(); d != conn.Full {
+		t.Fatal(d)
+	}
+	if m := ss.MaxTxSize(); m != 4 {
+		t.Fatal(m)
+	}
+	if ss.CLK() != gpio.INVALID || ss.MOSI() != gpio.INVALID || ss.MISO() != gpio.INVALID || ss.CS() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	if err := ss.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.TxPackets([]spi.Packet{{W: []byte{1}}}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := s.TxContext(ctx, []byte{1}, nil); err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+	ss.Release()
+	if err := ss.Tx([]byte{1}, nil); err != errSessionReleased {
+		t.Fatal(err)
+	}
+	if err := ss.TxPackets(nil); err != errSessionReleased {
+		t.Fatal(err)
+	}
+	if f.tx != 2 || len(f.calls) != 2 {
+		t.Fatal(f.tx, f.calls)
+	}
+}
+
+func TestSharedOneWire(t *testing.T) {
+	b := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: []byte{0xcc, 0x44}},
+			{W: []byte{0xf0}},
+			{W: []byte{0xcc, 0xbe}, R: []byte{1}},
+			{W: []byte{0xf0}},
+		},
+		Devices: []onewire.Address{0x7a00000131825228},
+	}
+	s := NewSharedOneWire(b)
+	if str := s.String(); str != "playback" {
+		t.Fatal(str)
+	}
+	if q := s.Q(); q != nil {
+		t.Fatal(q)
+	}
+	if err := s.Tx([]byte{0xcc, 0x44}, nil, onewire.WeakPullup); err != nil {
+		t.Fatal(err)
+	}
+	if a, err := s.Search(false); err != nil || len(a) != 1 {
+		t.Fatal(a, err)
+	}
+	ss, err := s.Session(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := ss.String(); str != "playback" {
+		t.Fatal(str)
+	}
+	if q := ss.Q(); q != nil {
+		t.Fatal(q)
+	}
+	r := make([]byte, 1)
+	if err := ss.Tx([]byte{0xcc, 0xbe}, r, onewire.WeakPullup); err != nil || r[0] != 1 {
+		t.Fatal(r, err)
+	}
+	if a, err := ss.Search(false); err != nil || len(a) != 1 {
+		t.Fatal(a, err)
+	}
+	ss.Release()
+	if err := ss.Tx(nil, nil, onewire.WeakPullup); err != errSessionReleased {
+		t.Fatal(err)
+	}
+	if _, err := ss.Search(false); err != errSessionReleased {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFairMutex_FIFO(t *testing.T) {
+	var f fairMutex
+	if err := f.lock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	const n = 5
+	order := make(chan int, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			if err := f.lock(context.Background()); err != nil {
+				t.Error(err)
+			}
+			order <- i
+			f.unlock()
+		}()
+		waitWaiters(&f, i+1)
+	}
+	f.unlock()
+	for i := 0; i < n; i++ {
+		if j := <-order; j != i {
+			t.Fatalf("#%d: got %d", i, j)
+		}
+	}
+}
+
+func TestFairMutex_unlock_panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	var f fairMutex
+	f.unlock()
+}
+
+//
+
+// waitWaiters waits until f has n goroutines waiting on it.
+func waitWaiters(f *fairMutex, n int) {
+	for {
+		f.mu.Lock()
+		l := len(f.waiters)
+		f.mu.Unlock()
+		if l == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// orderBus records the addresses of the transactions.
+type orderBus struct {
+	addrs []uint16
+}
+
+func (o *orderBus) String() string {
+	return "orderBus"
+}
+
+func (o *orderBus) Tx(addr uint16, w, r []byte) error {
+	o.addrs = append(o.addrs, addr)
+	return nil
+}
+
+func (o *orderBus) SetSpeed(f physic.Frequency) error {
+	return nil
+}
+
+// slowBus is an i2c.Bus without context support whose transactions block
+// until release is closed.
+type slowBus struct {
+	orderBus
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowBus) Tx(addr uint16, w, r []byte) error {
+	close(s.started)
+	<-s.release
+	return nil
+}
+
+var _ i2c.Bus = &orderBus{}
+var _ i2c.Bus = &slowBus{}
@@ -0,0 +1,610 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/uart"
+)
+
+// TraceOptions configures the tracing wrappers.
+//
+// A nil *TraceOptions is valid and is equivalent to the zero value.
+type TraceOptions struct {
+	// Logger is where the records are sent. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Level is the level of the records for successful operations. Defaults
+	// to slog.LevelDebug.
+	//
+	// Use a *slog.LevelVar to change it at runtime.
+	Level slog.Leveler
+	// ErrorLevel is the level of the records for failed operations. Defaults
+	// to slog.LevelWarn.
+	ErrorLevel slog.Leveler
+	// MaxDump is the maximum number of bytes of each payload to hex dump;
+	// longer payloads are truncated. Defaults to 64. Use a negative value to
+	// only log the payloads' length.
+	MaxDump int
+	// Redact, if set, is called with the name of the device and each payload
+	// before it is logged. It returns the bytes to dump instead, for example
+	// nil to only log the payload's length.
+	//
+	// For buses, the device name is the string of the equivalent i2c.Dev or
+	// onewire.Dev when the address is known.
+	Redact func(dev string, b []byte) []byte
+}
+
+// Trace returns a conn.Conn that logs the transactions on c.
+//
+// The returned object also implements conn.ConnContext and conn.Limits.
+func Trace(c conn.Conn, o *TraceOptions) conn.Conn {
+	return &traceConn{c: c, t: newTracer(o)}
+}
+
+// TraceI2C returns an i2c.Bus that logs the transactions on b.
+//
+// The returned object also implements i2c.BusContext and i2c.Pins.
+func TraceI2C(b i2c.Bus, o *TraceOptions) i2c.Bus {
+	return &traceI2C{b: b, t: newTracer(o)}
+}
+
+// TraceSPIPort returns a spi.Port that logs the calls to Connect() and the
+// transactions on the connections it returns.
+//
+// If p implements spi.PortCloser, so does the returned object.
+func TraceSPIPort(p spi.Port, o *TraceOptions) spi.Port {
+	tp := traceSPIPort{p: p, t: newTracer(o)}
+	if pc, ok := p.(spi.PortCloser); ok {
+		return &traceSPIPortCloser{tp, pc}
+	}
+	return &tp
+}
+
+// TraceSPI returns a spi.Conn that logs the transactions on c.
+//
+// A TxPackets() call is logged as a single record with one group per packet.
+//
+// The returned object also implements conn.ConnContext, conn.Limits and
+// spi.Pins.
+func TraceSPI(c spi.Conn, o *TraceOptions) spi.Conn {
+	return &traceSPI{traceConn{c: c, t: newTracer(o)}, c}
+}
+
+// TraceUART returns a uart.Port that logs the calls to Connect() and the
+// transactions on the connections it returns.
+//
+// If p implements uart.PortCloser, so does the returned object.
+func TraceUART(p uart.Port, o *TraceOptions) uart.Port {
+	tp := traceUARTPort{p: p, t: newTracer(o)}
+	if pc, ok := p.(uart.PortCloser); ok {
+		return &traceUARTPortCloser{tp, pc}
+	}
+	return &tp
+}
+
+// TraceOneWire returns a onewire.Bus that logs the transactions and searches
+// on b.
+//
+// The returned object also implements onewire.BusContext and onewire.Pins.
+func TraceOneWire(b onewire.Bus, o *TraceOptions) onewire.Bus {
+	return &traceOneWire{b: b, t: newTracer(o)}
+}
+
+// TracePin returns a gpio.PinIO that logs the calls to In(), Read(),
+// WaitForEdge(), Out(), PWM() and Halt() on p.
+//
+// The returned object also implements gpio.RealPin.
+func TracePin(p gpio.PinIO, o *TraceOptions) gpio.PinIO {
+	return &tracePin{PinIO: p, t: newTracer(o)}
+}
+
+// TraceADC returns an analog.PinADC that logs the calls to Read() and Halt()
+// on p.
+func TraceADC(p analog.PinADC, o *TraceOptions) analog.PinADC {
+	return &traceADC{PinADC: p, t: newTracer(o)}
+}
+
+// TraceDrawer returns a display.Drawer that logs the calls to Draw() and
+// Halt() on d. The image content is not logged.
+func TraceDrawer(d display.Drawer, o *TraceOptions) display.Drawer {
+	return &traceDrawer{d: d, t: newTracer(o)}
+}
+
+//
+
+// tracer emits the records on behalf of the tracing wrappers.
+type tracer struct {
+	l        *slog.Logger
+	level    slog.Leveler
+	errLevel slog.Leveler
+	maxDump  int
+	redact   func(dev string, b []byte) []byte
+}
+
+func newTracer(o *TraceOptions) *tracer {
+	t := &tracer{level: slog.LevelDebug, errLevel: slog.LevelWarn, maxDump: 64}
+	if o == nil {
+		t.l = slog.Default()
+		return t
+	}
+	t.l = o.Logger
+	if t.l == nil {
+		t.l = slog.Default()
+	}
+	if o.Level != nil {
+		t.level = o.Level
+	}
+	if o.ErrorLevel != nil {
+		t.errLevel = o.ErrorLevel
+	}
+	if o.MaxDump != 0 {
+		t.maxDump = o.MaxDump
+	}
+	t.redact = o.Redact
+	return t
+}
+
+// log emits a record for the operation msg on dev that started at start.
+//
+// w and r are dumped when not nil. dev.String() and the dumps are only
+// evaluated when the record is enabled.
+func (t *tracer) log(ctx context.Context, msg string, dev fmt.Stringer, start time.Time, err error, w, r []byte, attrs ...slog.Attr) {
+	level := t.levelFor(err)
+	if !t.l.Enabled(ctx, level) {
+		return
+	}
+	name := dev.String()
+	all := make([]slog.Attr, 0, len(attrs)+5)
+	all = append(all, slog.String("dev", name))
+	all = append(all, attrs...)
+	if w != nil {
+		all = append(all, slog.String("w", t.dump(name, w)))
+	}
+	if r != nil {
+		all = append(all, slog.String("r", t.dump(name, r)))
+	}
+	all = append(all, slog.Duration("duration", time.Since(start)))
+	if err != nil {
+		all = append(all, slog.Any("err", err))
+	}
+	t.l.LogAttrs(ctx, level, msg, all...)
+}
+
+// levelFor returns the level of a record for an operation that returned err.
+func (t *tracer) levelFor(err error) slog.Level {
+	if err != nil {
+		return t.errLevel.Level()
+	}
+	return t.level.Level()
+}
+
+// dump returns the hex representation of b, truncated to maxDump bytes.
+//
+// The length is appended in parenthesis when the dump is incomplete.
+func (t *tracer) dump(name string, b []byte) string {
+	l := len(b)
+	if t.redact != nil {
+		b = t.redact(name, b)
+	}
+	if t.maxDump < 0 {
+		b = nil
+	} else if len(b) > t.maxDump {
+		b = b[:t.maxDump]
+	}
+	s := hex.EncodeToString(b)
+	if len(b) != l {
+		if s != "" {
+			s += "..."
+		}
+		s += "(" + strconv.Itoa(l) + " bytes)"
+	}
+	return s
+}
+
+// packetAttrs returns one group per packet.
+func (t *tracer) packetAttrs(name string, p []spi.Packet) []slog.Attr {
+	out := make([]slog.Attr, len(p))
+	for i := range p {
+		a := make([]any, 0, 4)
+		if p[i].W != nil {
+			a = append(a, slog.String("w", t.dump(name, p[i].W)))
+		}
+		if p[i].R != nil {
+			a = append(a, slog.String("r", t.dump(name, p[i].R)))
+		}
+		if p[i].BitsPerWord != 0 {
+			a = append(a, slog.Int("bits", int(p[i].BitsPerWord)))
+		}
+		if p[i].KeepCS {
+			a = append(a, slog.Bool("keepcs", true))
+		}
+		out[i] = slog.Group(strconv.Itoa(i), a...)
+	}
+	return out
+}
+
+type traceConn struct {
+	c conn.Conn
+	t *tracer
+}
+
+func (c *traceConn) String() string {
+	return c.c.String()
+}
+
+func (c *traceConn) Tx(w, r []byte) error {
+	start := time.Now()
+	err := c.c.Tx(w, r)
+	c.t.log(context.Background(), "Tx", c.c, start, err, w, r)
+	return err
+}
+
+func (c *traceConn) TxContext(ctx context.Context, w, r []byte) error {
+	start := time.Now()
+	err := conn.WithContext(c.c).TxContext(ctx, w, r)
+	c.t.log(ctx, "Tx", c.c, start, err, w, r)
+	return err
+}
+
+func (c *traceConn) Duplex() conn.Duplex {
+	return c.c.Duplex()
+}
+
+func (c *traceConn) MaxTxSize() int {
+	return maxTxSize(c.c)
+}
+
+//
+
+type traceSPI struct {
+	traceConn
+	s spi.Conn
+}
+
+func (c *traceSPI) TxPackets(p []spi.Packet) error {
+	start := time.Now()
+	err := c.s.TxPackets(p)
+	// Skip building the groups when the record is dropped anyway.
+	if c.t.l.Enabled(context.Background(), c.t.levelFor(err)) {
+		c.t.log(context.Background(), "TxPackets", c.s, start, err, nil, nil, c.t.packetAttrs(c.s.String(), p)...)
+	}
+	return err
+}
+
+func (c *traceSPI) CLK() gpio.PinOut {
+	return spiCLK(c.s)
+}
+
+func (c *traceSPI) MOSI() gpio.PinOut {
+	return spiMOSI(c.s)
+}
+
+func (c *traceSPI) MISO() gpio.PinIn {
+	return spiMISO(c.s)
+}
+
+func (c *traceSPI) CS() gpio.PinOut {
+	return spiCS(c.s)
+}
+
+//
+
+type traceSPIPort struct {
+	p spi.Port
+	t *tracer
+}
+
+func (p *traceSPIPort) String() string {
+	return p.p.String()
+}
+
+func (p *traceSPIPort) Connect(f physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	start := time.Now()
+	c, err := p.p.Connect(f, mode, bits)
+	p.t.log(context.Background(), "Connect", p.p, start, err, nil, nil, slog.Any("f", f), slog.Any("mode", mode), slog.Int("bits", bits))
+	if err != nil {
+		return c, err
+	}
+	return &traceSPI{traceConn{c: c, t: p.t}, c}, nil
+}
+
+type traceSPIPortCloser struct {
+	traceSPIPort
+	pc spi.PortCloser
+}
+
+func (p *traceSPIPortCloser) Close() error {
+	start := time.Now()
+	err := p.pc.Close()
+	p.t.log(context.Background(), "Close", p.pc, start, err, nil, nil)
+	return err
+}
+
+func (p *traceSPIPortCloser) LimitSpeed(f physic.Frequency) error {
+	start := time.Now()
+	err := p.pc.LimitSpeed(f)
+	p.t.log(context.Background(), "LimitSpeed", p.pc, start, err, nil, nil, slog.Any("f", f))
+	return err
+}
+
+//
+
+type traceUARTPort struct {
+	p uart.Port
+	t *tracer
+}
+
+func (p *traceUARTPort) String() string {
+	return p.p.String()
+}
+
+func (p *traceUARTPort) Connect(f physic.Frequency, stopBit uart.Stop, parity uart.Parity, flow uart.Flow, bits int) (conn.Conn, error) {
+	start := time.Now()
+	c, err := p.p.Connect(f, stopBit, parity, flow, bits)
+	p.t.log(context.Background(), "Connect", p.p, start, err, nil, nil, slog.Any("f", f), slog.Int("stop", int(stopBit)), slog.String("parity", string(rune(parity))), slog.Any("flow", flow), slog.Int("bits", bits))
+	if err != nil {
+		return c, err
+	}
+	return &traceConn{c: c, t: p.t}, nil
+}
+
+type traceUARTPortCloser struct {
+	traceUARTPort
+	pc uart.PortCloser
+}
+
+func (p *traceUARTPortCloser) Close() error {
+	start := time.Now()
+	err := p.pc.Close()
+	p.t.log(context.Background(), "Close", p.pc, start, err, nil, nil)
+	return err
+}
+
+func (p *traceUARTPortCloser) LimitSpeed(f physic.Frequency) error {
+	start := time.Now()
+	err := p.pc.LimitSpeed(f)
+	p.t.log(context.Background(), "LimitSpeed", p.pc, start, err, nil, nil, slog.Any("f", f))
+	return err
+}
+
+//
+
+type traceI2C struct {
+	b i2c.Bus
+	t *tracer
+}
+
+func (b *traceI2C) String() string {
+	return b.b.String()
+}
+
+func (b *traceI2C) Tx(addr uint16, w, r []byte) error {
+	start := time.Now()
+	err := b.b.Tx(addr, w, r)
+	b.t.log(context.Background(), "Tx", &i2c.Dev{Bus: b.b, Addr: addr}, start, err, w, r)
+	return err
+}
+
+func (b *traceI2C) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	start := time.Now()
+	err := i2cTxContext(ctx, b.b, addr, w, r)
+	b.t.log(ctx, "Tx", &i2c.Dev{Bus: b.b, Addr: addr}, start, err, w, r)
+	return err
+}
+
+func (b *traceI2C) SetSpeed(f physic.Frequency) error {
+	start := time.Now()
+	err := b.b.SetSpeed(f)
+	b.t.log(context.Background(), "SetSpeed", b.b, start, err, nil, nil, slog.Any("f", f))
+	return err
+}
+
+func (b *traceI2C) SCL() gpio.PinIO {
+	return i2cSCL(b.b)
+}
+
+func (b *traceI2C) SDA() gpio.PinIO {
+	return i2cSDA(b.b)
+}
+
+//
+
+type traceOneWire struct {
+	b onewire.Bus
+	t *tracer
+}
+
+func (b *traceOneWire) String() string {
+	return b.b.String()
+}
+
+func (b *traceOneWire) Tx(w, r []byte, power onewire.Pullup) error {
+	start := time.Now()
+	err := b.b.Tx(w, r, power)
+	b.t.log(context.Background(), "Tx", b.dev(w), start, err, w, r, slog.Any("power", power))
+	return err
+}
+
+func (b *traceOneWire) TxContext(ctx context.Context, w, r []byte, power onewire.Pullup) error {
+	start := time.Now()
+	err := oneWireTxContext(ctx, b.b, w, r, power)
+	b.t.log(ctx, "Tx", b.dev(w), start, err, w, r, slog.Any("power", power))
+	return err
+}
+
+func (b *traceOneWire) Search(alarmOnly bool) ([]onewire.Address, error) {
+	start := time.Now()
+	addrs, err := b.b.Search(alarmOnly)
+	b.t.log(context.Background(), "Search", b.b, start, err, nil, nil, slog.Bool("alarm", alarmOnly), slog.Any("addrs", addrList(addrs)))
+	return addrs, err
+}
+
+func (b *traceOneWire) Q() gpio.PinIO {
+	return oneWireQ(b.b)
+}
+
+// dev returns the device selected by the "match ROM" command in w, if any.
+func (b *traceOneWire) dev(w []byte) fmt.Stringer {
+	if len(w) < 9 || w[0] != 0x55 {
+		return b.b
+	}
+	var a onewire.Address
+	for i := 8; i > 0; i-- {
+		a = a<<8 | onewire.Address(w[i])
+	}
+	return &onewire.Dev{Bus: b.b, Addr: a}
+}
+
+// addrList formats 1-wire addresses in hex when logged.
+type addrList []onewire.Address
+
+func (a addrList) LogValue() slog.Value {
+	s := make([]string, len(a))
+	for i := range a {
+		s[i] = fmt.Sprintf("%#016x", uint64(a[i]))
+	}
+	return slog.AnyValue(s)
+}
+
+//
+
+type tracePin struct {
+	gpio.PinIO
+	t *tracer
+}
+
+func (p *tracePin) Real() gpio.PinIO {
+	return p.PinIO
+}
+
+func (p *tracePin) Halt() error {
+	start := time.Now()
+	err := p.PinIO.Halt()
+	p.t.log(context.Background(), "Halt", p.PinIO, start, err, nil, nil)
+	return err
+}
+
+func (p *tracePin) In(pull gpio.Pull, edge gpio.Edge) error {
+	start := time.Now()
+	err := p.PinIO.In(pull, edge)
+	p.t.log(context.Background(), "In", p.PinIO, start, err, nil, nil, slog.Any("pull", pull), slog.Any("edge", edge))
+	return err
+}
+
+func (p *tracePin) Read() gpio.Level {
+	start := time.Now()
+	l := p.PinIO.Read()
+	p.t.log(context.Background(), "Read", p.PinIO, start, nil, nil, nil, slog.Any("level", l))
+	return l
+}
+
+func (p *tracePin) WaitForEdge(timeout time.Duration) bool {
+	start := time.Now()
+	b := p.PinIO.WaitForEdge(timeout)
+	p.t.log(context.Background(), "WaitForEdge", p.PinIO, start, nil, nil, nil, slog.Duration("timeout", timeout), slog.Bool("edge", b))
+	return b
+}
+
+func (p *tracePin) Out(l gpio.Level) error {
+	start := time.Now()
+	err := p.PinIO.Out(l)
+	p.t.log(context.Background(), "Out", p.PinIO, start, err, nil, nil, slog.Any("level", l))
+	return err
+}
+
+func (p *tracePin) PWM(duty gpio.Duty, f physic.Frequency) error {
+	start := time.Now()
+	err := p.PinIO.PWM(duty, f)
+	p.t.log(context.Background(), "PWM", p.PinIO, start, err, nil, nil, slog.Any("duty", duty), slog.Any("f", f))
+	return err
+}
+
+//
+
+type traceADC struct {
+	analog.PinADC
+	t *tracer
+}
+
+func (p *traceADC) Halt() error {
+	start := time.Now()
+	err := p.PinADC.Halt()
+	p.t.log(context.Background(), "Halt", p.PinADC, start, err, nil, nil)
+	return err
+}
+
+func (p *traceADC) Read() (analog.Sample, error) {
+	start := time.Now()
+	s, err := p.PinADC.Read()
+	p.t.log(context.Background(), "Read", p.PinADC, start, err, nil, nil, slog.Any("v", s.V), slog.Int("raw", int(s.Raw)))
+	return s, err
+}
+
+//
+
+type traceDrawer struct {
+	d display.Drawer
+	t *tracer
+}
+
+func (d *traceDrawer) String() string {
+	return d.d.String()
+}
+
+func (d *traceDrawer) Halt() error {
+	start := time.Now()
+	err := d.d.Halt()
+	d.t.log(context.Background(), "Halt", d.d, start, err, nil, nil)
+	return err
+}
+
+func (d *traceDrawer) ColorModel() color.Model {
+	return d.d.ColorModel()
+}
+
+func (d *traceDrawer) Bounds() image.Rectangle {
+	return d.d.Bounds()
+}
+
+func (d *traceDrawer) Draw(dstRect image.Rectangle, src image.Image, srcPts image.Point) error {
+	start := time.Now()
+	err := d.d.Draw(dstRect, src, srcPts)
+	d.t.log(context.Background(), "Draw", d.d, start, err, nil, nil, slog.Any("dst", dstRect), slog.Any("src", src.Bounds()), slog.Any("sp", srcPts))
+	return err
+}
+
+var _ conn.ConnContext = &traceConn{}
+var _ conn.Limits = &traceConn{}
+var _ spi.Conn = &traceSPI{}
+var _ spi.Pins = &traceSPI{}
+var _ spi.Port = &traceSPIPort{}
+var _ spi.PortCloser = &traceSPIPortCloser{}
+var _ uart.Port = &traceUARTPort{}
+var _ uart.PortCloser = &traceUARTPortCloser{}
+var _ i2c.BusContext = &traceI2C{}
+var _ i2c.Pins = &traceI2C{}
+var _ onewire.BusContext = &traceOneWire{}
+var _ onewire.Pins = &traceOneWire{}
+var _ slog.LogValuer = addrList{}
+var _ gpio.PinIO = &tracePin{}
+var _ gpio.RealPin = &tracePin{}
+var _ analog.PinADC = &traceADC{}
+var _ display.Drawer = &traceDrawer{}
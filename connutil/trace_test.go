@@ -0,0 +1,470 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package connutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"log/slog"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/display/displaytest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewiretest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spitest"
+	"periph.io/x/conn/v3/uart"
+)
+
+func TestTrace(t *testing.T) {
+	var buf bytes.Buffer
+	p := &conntest.Playback{
+		Ops: []conntest.IO{
+			{W: []byte{1}, R: []byte{2, 3}},
+			{W: []byte{1, 2, 3, 4}, Err: conn.ErrTimeout},
+			{W: []byte{1}},
+		},
+		D: conn.Half,
+	}
+	c := Trace(p, &TraceOptions{Logger: newTestLogger(&buf), MaxDump: 2})
+	if s := c.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if d := c.Duplex(); d != conn.Half {
+		t.Fatal(d)
+	}
+	if l := c.(conn.Limits).MaxTxSize(); l != 0 {
+		t.Fatal(l)
+	}
+	if err := c.Tx([]byte{1}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.(conn.ConnContext).TxContext(context.Background(), []byte{1, 2, 3, 4}, nil); err != conn.ErrTimeout {
+		t.Fatal(err)
+	}
+	if err := c.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=Tx dev=playback w=01 r=0203\n" +
+		"level=WARN msg=Tx dev=playback w=\"0102...(4 bytes)\" err=\"conn: timeout\"\n" +
+		"level=DEBUG msg=Tx dev=playback w=01\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrace_Level(t *testing.T) {
+	var buf bytes.Buffer
+	level := &slog.LevelVar{}
+	level.Set(slog.LevelDebug - 1)
+	p := &conntest.Playback{Ops: []conntest.IO{{W: []byte{1}}, {W: []byte{1}}}}
+	c := Trace(p, &TraceOptions{Logger: newTestLogger(&buf), Level: level, ErrorLevel: slog.LevelError})
+	if err := c.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != "" {
+		t.Fatal(s)
+	}
+	level.Set(slog.LevelInfo)
+	if err := c.Tx([]byte{1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != "level=INFO msg=Tx dev=playback w=01\n" {
+		t.Fatal(s)
+	}
+}
+
+func TestTrace_Redact(t *testing.T) {
+	var buf bytes.Buffer
+	b := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x50, W: []byte{0, 1, 2}},
+			{Addr: 0x51, W: []byte{0, 1, 2}},
+		},
+	}
+	redact := func(dev string, b []byte) []byte {
+		if dev == "playback(80)" {
+			return nil
+		}
+		return b
+	}
+	tb := TraceI2C(b, &TraceOptions{Logger: newTestLogger(&buf), MaxDump: -1, Redact: redact})
+	if err := tb.Tx(0x50, []byte{0, 1, 2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	tb = TraceI2C(b, &TraceOptions{Logger: newTestLogger(&buf), Redact: redact})
+	if err := tb.Tx(0x51, []byte{0, 1, 2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=Tx dev=playback(80) w=\"(3 bytes)\"\n" +
+		"level=DEBUG msg=Tx dev=playback(81) w=000102\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+}
+
+func TestTraceI2C(t *testing.T) {
+	var buf bytes.Buffer
+	b := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{1}, R: []byte{2}},
+			{Addr: 0x41, W: []byte{1}, Err: conn.ErrAddrNACK},
+		},
+	}
+	tb := TraceI2C(b, &TraceOptions{Logger: newTestLogger(&buf)})
+	if s := tb.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if p := tb.(i2c.Pins); p.SCL() != nil || p.SDA() != nil {
+		t.Fatal("expected nil pins")
+	}
+	if err := tb.SetSpeed(physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if err := tb.Tx(0x40, []byte{1}, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tb.(i2c.BusContext).TxContext(context.Background(), 0x41, []byte{1}, nil); err != conn.ErrAddrNACK {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=SetSpeed dev=playback f=1kHz\n" +
+		"level=DEBUG msg=Tx dev=playback(64) w=01 r=02\n" +
+		"level=WARN msg=Tx dev=playback(65) w=01 err=\"conn: address not acknowledged\"\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTraceSPIPort(t *testing.T) {
+	var buf bytes.Buffer
+	p := &spitest.Playback{
+		Playback: conntest.Playback{
+			Ops: []conntest.IO{
+				{W: []byte{1, 2}, R: []byte{3, 4}},
+				{W: []byte{5}, R: []byte{6}},
+			},
+		},
+	}
+	tp := TraceSPIPort(p, &TraceOptions{Logger: newTestLogger(&buf)})
+	if s := tp.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	pc := tp.(spi.PortCloser)
+	if err := pc.LimitSpeed(physic.MegaHertz); err != nil {
+		t.Fatal(err)
+	}
+	c, err := tp.Connect(physic.MegaHertz, spi.Mode3, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Tx([]byte{1, 2}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.(conn.ConnContext).TxContext(context.Background(), []byte{5}, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tp.Connect(physic.MegaHertz, spi.Mode3, 8); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := pc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=LimitSpeed dev=playback f=1MHz\n" +
+		"level=DEBUG msg=Connect dev=playback f=1MHz mode=Mode3 bits=8\n" +
+		"level=DEBUG msg=Tx dev=playback w=0102 r=0304\n" +
+		"level=DEBUG msg=Tx dev=playback w=05 r=06\n" +
+		"level=WARN msg=Connect dev=playback f=1MHz mode=Mode3 bits=8 err=\"spitest: Connect cannot be called twice\"\n" +
+		"level=DEBUG msg=Close dev=playback\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+	// Not a PortCloser.
+	if _, ok := TraceSPIPort(&fakePort{p}, nil).(spi.PortCloser); ok {
+		t.Fatal("unexpected PortCloser")
+	}
+}
+
+func TestTraceSPI(t *testing.T) {
+	var buf bytes.Buffer
+	f := &fakeSPI{}
+	c := TraceSPI(f, &TraceOptions{Logger: newTestLogger(&buf)})
+	if err := c.TxPackets([]spi.Packet{{W: []byte{1}, KeepCS: true}, {R: make([]byte, 2), BitsPerWord: 16}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=TxPackets dev=fakeSPI 0.w=01 0.keepcs=true 1.r=0000 1.bits=16\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+	p := c.(spi.Pins)
+	if p.CLK() != gpio.INVALID || p.MOSI() != gpio.INVALID || p.MISO() != gpio.INVALID || p.CS() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	// Disabled.
+	buf.Reset()
+	c = TraceSPI(f, &TraceOptions{Logger: newTestLogger(&buf), Level: slog.LevelDebug - 1})
+	if err := c.TxPackets([]spi.Packet{{W: []byte{1}}}); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != "" {
+		t.Fatal(s)
+	}
+}
+
+func TestTraceUART(t *testing.T) {
+	var buf bytes.Buffer
+	f := &fakeUART{c: &conntest.Playback{Ops: []conntest.IO{{W: []byte("AT\r")}}}}
+	tp := TraceUART(f, &TraceOptions{Logger: newTestLogger(&buf)})
+	if s := tp.String(); s != "fakeUART" {
+		t.Fatal(s)
+	}
+	if _, ok := tp.(uart.PortCloser); ok {
+		t.Fatal("unexpected PortCloser")
+	}
+	c, err := tp.Connect(115200*physic.Hertz, uart.One, uart.NoParity, uart.NoFlow, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Tx([]byte("AT\r"), nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=Connect dev=fakeUART f=115.200kHz stop=1 parity=N flow=None bits=8\n" +
+		"level=DEBUG msg=Tx dev=playback w=41540d\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+	buf.Reset()
+	tp = TraceUART(&fakeUARTCloser{fakeUART{err: errors.New("busy")}}, &TraceOptions{Logger: newTestLogger(&buf)})
+	pc := tp.(uart.PortCloser)
+	if err := pc.LimitSpeed(physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tp.Connect(physic.KiloHertz, uart.Two, uart.Even, uart.RTSCTS, 7); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := pc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expected = "level=DEBUG msg=LimitSpeed dev=fakeUART f=1kHz\n" +
+		"level=WARN msg=Connect dev=fakeUART f=1kHz stop=2 parity=E flow=RTS/CTS bits=7 err=busy\n" +
+		"level=DEBUG msg=Close dev=fakeUART\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+}
+
+func TestTraceOneWire(t *testing.T) {
+	var buf bytes.Buffer
+	b := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: []byte{0x55, 0x28, 0x52, 0x82, 0x31, 0x01, 0x00, 0x00, 0x7a, 0xbe}, R: []byte{1}},
+			{W: []byte{0xcc, 0x44}, Pull: onewire.StrongPullup},
+			{W: []byte{0xf0}},
+		},
+		Devices: []onewire.Address{0x7a00000131825228},
+	}
+	tb := TraceOneWire(b, &TraceOptions{Logger: newTestLogger(&buf)})
+	if s := tb.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if q := tb.(onewire.Pins).Q(); q != nil {
+		t.Fatal(q)
+	}
+	d := onewire.Dev{Bus: tb, Addr: 0x7a00000131825228}
+	if err := d.Tx([]byte{0xbe}, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tb.(onewire.BusContext).TxContext(context.Background(), []byte{0xcc, 0x44}, nil, onewire.StrongPullup); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tb.Search(false); err != nil {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=Tx dev=playback(0x7a00000131825228) power=Weak w=55285282310100007abe r=01\n" +
+		"level=DEBUG msg=Tx dev=playback power=Strong w=cc44\n" +
+		"level=DEBUG msg=Search dev=playback alarm=false addrs=[0x7a00000131825228]\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTracePin(t *testing.T) {
+	var buf bytes.Buffer
+	p := &gpiotest.Pin{N: "GPIO1", Num: 1}
+	tp := TracePin(p, &TraceOptions{Logger: newTestLogger(&buf)})
+	if r := tp.(gpio.RealPin).Real(); r != p {
+		t.Fatal(r)
+	}
+	if s := tp.Name(); s != "GPIO1" {
+		t.Fatal(s)
+	}
+	if err := tp.In(gpio.PullDown, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if l := tp.Read(); l != gpio.Low {
+		t.Fatal(l)
+	}
+	if tp.WaitForEdge(0) {
+		t.Fatal("unexpected edge")
+	}
+	if err := tp.Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if err := tp.PWM(gpio.DutyHalf, physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if err := tp.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=In dev=GPIO1(1) pull=PullDown edge=NoEdge\n" +
+		"level=DEBUG msg=Read dev=GPIO1(1) level=Low\n" +
+		"level=DEBUG msg=WaitForEdge dev=GPIO1(1) timeout=0s edge=false\n" +
+		"level=DEBUG msg=Out dev=GPIO1(1) level=High\n" +
+		"level=DEBUG msg=PWM dev=GPIO1(1) duty=50% f=1kHz\n" +
+		"level=DEBUG msg=Halt dev=GPIO1(1)\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+}
+
+func TestTraceADC(t *testing.T) {
+	var buf bytes.Buffer
+	p := &fakeADC{BasicPin: pin.BasicPin{N: "ADC0"}}
+	tp := TraceADC(p, &TraceOptions{Logger: newTestLogger(&buf)})
+	if s, err := tp.Read(); err != nil || s.Raw != 512 {
+		t.Fatal(s, err)
+	}
+	if err := tp.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, max := tp.Range(); max.Raw != 1023 {
+		t.Fatal(max)
+	}
+	expected := "level=DEBUG msg=Read dev=ADC0 v=1.650V raw=512\n" +
+		"level=DEBUG msg=Halt dev=ADC0\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+}
+
+func TestTraceDrawer(t *testing.T) {
+	var buf bytes.Buffer
+	d := &displaytest.Drawer{Img: image.NewNRGBA(image.Rect(0, 0, 8, 4))}
+	td := TraceDrawer(d, &TraceOptions{Logger: newTestLogger(&buf)})
+	if s := td.String(); s != "Drawer" {
+		t.Fatal(s)
+	}
+	if b := td.Bounds(); b != d.Bounds() {
+		t.Fatal(b)
+	}
+	if m := td.ColorModel(); m != d.ColorModel() {
+		t.Fatal(m)
+	}
+	if err := td.Draw(td.Bounds(), image.NewGray(image.Rect(0, 0, 2, 2)), image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := td.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "level=DEBUG msg=Draw dev=Drawer dst=(0,0)-(8,4) src=(0,0)-(2,2) sp=(0,0)\n" +
+		"level=DEBUG msg=Halt dev=Drawer\n"
+	if s := buf.String(); s != expected {
+		t.Fatal(s)
+	}
+}
+
+func TestTrace_nilOptions(t *testing.T) {
+	tr := newTracer(nil)
+	if tr.l != slog.Default() || tr.level.Level() != slog.LevelDebug || tr.errLevel.Level() != slog.LevelWarn || tr.maxDump != 64 {
+		t.Fatal(tr)
+	}
+	tr = newTracer(&TraceOptions{})
+	if tr.l != slog.Default() {
+		t.Fatal(tr)
+	}
+}
+
+//
+
+// newTestLogger returns a logger with reproducible output.
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == "duration") {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+type fakePort struct {
+	p spi.Port
+}
+
+func (f *fakePort) String() string {
+	return f.p.String()
+}
+
+func (f *fakePort) Connect(freq physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	return f.p.Connect(freq, mode, bits)
+}
+
+type fakeUART struct {
+	c   conn.Conn
+	err error
+}
+
+func (f *fakeUART) String() string {
+	return "fakeUART"
+}
+
+func (f *fakeUART) Connect(freq physic.Frequency, stopBit uart.Stop, parity uart.Parity, flow uart.Flow, bits int) (conn.Conn, error) {
+	return f.c, f.err
+}
+
+type fakeUARTCloser struct {
+	fakeUART
+}
+
+func (f *fakeUARTCloser) Close() error {
+	return nil
+}
+
+func (f *fakeUARTCloser) LimitSpeed(freq physic.Frequency) error {
+	return nil
+}
+
+type fakeADC struct {
+	pin.BasicPin
+}
+
+func (f *fakeADC) Range() (analog.Sample, analog.Sample) {
+	return analog.Sample{}, analog.Sample{V: 3300 * physic.MilliVolt, Raw: 1023}
+}
+
+func (f *fakeADC) Read() (analog.Sample, error) {
+	return analog.Sample{V: 1650 * physic.MilliVolt, Raw: 512}, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conn
+
+import "context"
+
+// ConnContext is a Conn that supports cancellation and deadlines.
+//
+// Implementations that can abort a transaction in flight, for example a
+// driver that can cancel a pending kernel request, should implement this
+// interface directly. Use WithContext to adapt any other Conn.
+type ConnContext interface {
+	Conn
+	// TxContext does a single transaction like Tx, except that it returns
+	// ctx.Err() as soon as ctx is done.
+	//
+	// If ctx is already done, no transaction is started. If ctx becomes done
+	// while the transaction is in flight, the content of r is undefined unless
+	// the implementation documents otherwise.
+	TxContext(ctx context.Context, w, r []byte) error
+}
+
+// WithContext returns a ConnContext for c.
+//
+// If c already implements ConnContext, it is returned as-is. Otherwise, the
+// returned object runs c.Tx() via DoContext.
+func WithContext(c Conn) ConnContext {
+	if cc, ok := c.(ConnContext); ok {
+		return cc
+	}
+	return &contextConn{c}
+}
+
+// DoContext runs tx, which is a blocking transaction writing w and reading
+// into r, and returns early with ctx.Err() if ctx is done before tx
+// completes.
+//
+// It is meant to be used by implementations of ConnContext that wrap a
+// blocking call that cannot be aborted.
+//
+// tx is run on a separate goroutine with private copies of w and r. When ctx
+// is done before tx returns, tx keeps running in the background until the
+// underlying transaction completes but r is left untouched and the caller is
+// free to reuse both buffers. Otherwise, r receives the data read by tx.
+//
+// The error returned on cancellation is exactly ctx.Err(), so it can be
+// compared against context.Canceled or context.DeadlineExceeded.
+func DoContext(ctx context.Context, w, r []byte, tx func(w, r []byte) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() == nil {
+		// The context can never be canceled, skip the goroutine.
+		return tx(w, r)
+	}
+	var wb, rb []byte
+	if w != nil {
+		wb = make([]byte, len(w))
+		copy(wb, w)
+	}
+	if r != nil {
+		rb = make([]byte, len(r))
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- tx(wb, rb)
+	}()
+	select {
+	case err := <-done:
+		copy(r, rb)
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//
+
+// contextConn adapts a Conn into a ConnContext.
+type contextConn struct {
+	Conn
+}
+
+// TxContext implements ConnContext.
+func (c *contextConn) TxContext(ctx context.Context, w, r []byte) error {
+	return DoContext(ctx, w, r, c.Conn.Tx)
+}
+
+// Unwrap returns the underlying connection.
+func (c *contextConn) Unwrap() Conn {
+	return c.Conn
+}
+
+var _ ConnContext = &contextConn{}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithContext(t *testing.T) {
+	c := &blockConn{r: []byte{1, 2}}
+	cc := WithContext(c)
+	if WithContext(cc) != cc {
+		t.Fatal("expected ConnContext to be returned as-is")
+	}
+	if s := cc.String(); s != "block" {
+		t.Fatal(s)
+	}
+	r := make([]byte, 2)
+	if err := cc.TxContext(context.Background(), []byte{3}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{1, 2}) {
+		t.Fatal(r)
+	}
+	if !bytes.Equal(c.w, []byte{3}) {
+		t.Fatal(c.w)
+	}
+}
+
+func TestWithContext_Done(t *testing.T) {
+	c := &blockConn{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := WithContext(c).TxContext(ctx, nil, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if c.w != nil {
+		t.Fatal("unexpected Tx")
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	exErr := errors.New("yes")
+	r := make([]byte, 1)
+	err := DoContext(ctx, []byte{1}, r, func(w, r []byte) error {
+		r[0] = w[0] + 1
+		return exErr
+	})
+	if err != exErr {
+		t.Fatal(err)
+	}
+	if r[0] != 2 {
+		t.Fatal(r)
+	}
+}
+
+func TestDoContext_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	unblock := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+	w := []byte{1}
+	r := make([]byte, 1)
+	go func() {
+		<-started
+		cancel()
+	}()
+	err := DoContext(ctx, w, r, func(w, r []byte) error {
+		defer close(done)
+		close(started)
+		<-unblock
+		r[0] = 42
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatal(err)
+	}
+	// Buffers can be reused right away.
+	w[0] = 2
+	close(unblock)
+	<-done
+	if r[0] != 0 {
+		t.Fatal("r must not be modified after cancellation")
+	}
+}
+
+func TestDoContext_Deadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	unblock := make(chan struct{})
+	defer close(unblock)
+	err := DoContext(ctx, nil, nil, func(w, r []byte) error {
+		<-unblock
+		return nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+}
+
+//
+
+type blockConn struct {
+	w, r []byte
+}
+
+func (b *blockConn) String() string {
+	return "block"
+}
+
+func (b *blockConn) Tx(w, r []byte) error {
+	b.w = append(b.w, w...)
+	copy(r, b.r)
+	return nil
+}
+
+func (b *blockConn) Duplex() Duplex {
+	return Half
+}
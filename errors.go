@@ -0,0 +1,122 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conn
+
+import "errors"
+
+// Well known transaction failures.
+//
+// Implementations of Conn and of the various buses should return errors that
+// match one of these with errors.Is() when the failure can be classified, so
+// device drivers can handle them without inspecting error strings. They can
+// either return them as-is, wrap them with fmt.Errorf("...: %w", err) or
+// implement an Is(target error) bool method.
+var (
+	// ErrAddrNACK means that no device acknowledged the address, for example
+	// an I²C address NACK or no presence pulse after a 1-wire reset.
+	ErrAddrNACK error = &classError{"conn: address not acknowledged", true}
+	// ErrDataNACK means that the device acknowledged its address but not a
+	// data byte that followed.
+	ErrDataNACK error = &classError{"conn: data not acknowledged", false}
+	// ErrArbitrationLost means that another master took over the bus during
+	// the transaction.
+	ErrArbitrationLost error = &classError{"conn: arbitration lost", true}
+	// ErrTimeout means that the transaction didn't complete in time, for
+	// example because a device stretched the clock for too long.
+	ErrTimeout error = &timeoutError{classError{"conn: timeout", true}}
+	// ErrBusBusy means that the bus lines were held by another party when the
+	// transaction was about to start.
+	ErrBusBusy error = &classError{"conn: bus busy", true}
+	// ErrCRC means that a checksum over the data didn't match.
+	ErrCRC error = &classError{"conn: CRC error", true}
+	// ErrParity means that a parity bit didn't match the data.
+	ErrParity error = &classError{"conn: parity error", true}
+)
+
+// TemporaryError is implemented by errors that can tell if the condition may
+// go away when the operation is retried.
+//
+// All the well known errors above implement it.
+type TemporaryError interface {
+	error
+	Temporary() bool
+}
+
+// TimeoutError is implemented by errors that can tell if they are caused by
+// an operation taking too long.
+//
+// It matches net.Error and context.DeadlineExceeded.
+type TimeoutError interface {
+	error
+	Timeout() bool
+}
+
+// IsTemporary returns true if the first error in err's chain that implements
+// TemporaryError reports that it is temporary.
+//
+// It is a hint for generic retry logic.
+func IsTemporary(err error) bool {
+	var t TemporaryError
+	return errors.As(err, &t) && t.Temporary()
+}
+
+// IsTimeout returns true if the first error in err's chain that implements
+// TimeoutError reports that it is a timeout.
+func IsTimeout(err error) bool {
+	var t TimeoutError
+	return errors.As(err, &t) && t.Timeout()
+}
+
+// Error annotates an error with the connection it occurred on.
+//
+// It wraps Err so errors.Is() and errors.As() see through it.
+type Error struct {
+	// Conn is the user readable name of the connection, generally the value
+	// returned by String().
+	Conn string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Conn + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Temporary implements TemporaryError.
+func (e *Error) Temporary() bool {
+	return IsTemporary(e.Err)
+}
+
+// Timeout implements TimeoutError.
+func (e *Error) Timeout() bool {
+	return IsTimeout(e.Err)
+}
+
+//
+
+// classError implements TemporaryError.
+type classError struct {
+	msg       string
+	temporary bool
+}
+
+func (e *classError) Error() string   { return e.msg }
+func (e *classError) Temporary() bool { return e.temporary }
+
+// timeoutError implements TemporaryError and TimeoutError.
+type timeoutError struct {
+	classError
+}
+
+func (e *timeoutError) Timeout() bool { return true }
+
+var _ TemporaryError = &Error{}
+var _ TimeoutError = &Error{}
+var _ TemporaryError = &classError{}
+var _ TimeoutError = &timeoutError{}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package conn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	data := []struct {
+		err       error
+		temporary bool
+		timeout   bool
+	}{
+		{ErrAddrNACK, true, false},
+		{ErrDataNACK, false, false},
+		{ErrArbitrationLost, true, false},
+		{ErrTimeout, true, true},
+		{ErrBusBusy, true, false},
+		{ErrCRC, true, false},
+		{ErrParity, true, false},
+	}
+	for i, line := range data {
+		wrapped := fmt.Errorf("driver: %w", &Error{Conn: "I2C1(118)", Err: line.err})
+		if !errors.Is(wrapped, line.err) {
+			t.Fatalf("#%d: %v", i, wrapped)
+		}
+		if v := IsTemporary(wrapped); v != line.temporary {
+			t.Fatalf("#%d: IsTemporary(%v) = %t", i, wrapped, v)
+		}
+		if v := IsTimeout(wrapped); v != line.timeout {
+			t.Fatalf("#%d: IsTimeout(%v) = %t", i, wrapped, v)
+		}
+		for j, other := range data {
+			if i != j && errors.Is(wrapped, other.err) {
+				t.Fatalf("#%d: %v matches %v", i, wrapped, other.err)
+			}
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	e := &Error{Conn: "I2C1(118)", Err: ErrAddrNACK}
+	if s := e.Error(); s != "I2C1(118): conn: address not acknowledged" {
+		t.Fatal(s)
+	}
+	var c *Error
+	if !errors.As(fmt.Errorf("a: %w", e), &c) || c != e {
+		t.Fatal("expected errors.As to work")
+	}
+}
+
+func TestIsTemporary(t *testing.T) {
+	if IsTemporary(nil) || IsTimeout(nil) {
+		t.Fatal("nil")
+	}
+	if IsTemporary(errors.New("a")) || IsTimeout(errors.New("a")) {
+		t.Fatal("unclassified")
+	}
+	if !IsTimeout(context.DeadlineExceeded) {
+		t.Fatal("context.DeadlineExceeded")
+	}
+}
@@ -7,6 +7,7 @@ package gpio_test
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"periph.io/x/conn/v3/driver/driverreg"
 	"periph.io/x/conn/v3/gpio"
@@ -43,6 +44,19 @@ func ExampleParseDuty() {
 	// 33%
 }
 
+func ExampleDutyFromPulse() {
+	// A hobby servo centered with a 1.5ms pulse at 50Hz.
+	d, err := gpio.DutyFromPulse(1500*time.Microsecond, 50*physic.Hertz)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(d)
+	fmt.Println(d.PulseWidth(50 * physic.Hertz))
+	// Output:
+	// 7.5%
+	// 1.5ms
+}
+
 func ExamplePinIn() {
 	// Make sure periph is initialized.
 	// TODO: Use host.Init(). It is not used in this example to prevent circular
@@ -10,6 +10,8 @@ package gpio
 
 import (
 	"errors"
+	"math"
+	"math/bits"
 	"strconv"
 	"strings"
 	"time"
@@ -95,9 +97,41 @@ const (
 // Valid values are between 0 and DutyMax.
 type Duty int32
 
+// DutyFromPulse returns the duty cycle of a pulse of width repeated at
+// frequency f, rounded to the nearest value.
+//
+// It is useful to drive a servo, where the position is set by a pulse width
+// usually between 1ms and 2ms at 50Hz.
+//
+// If width is negative or longer than the period, it returns the nearest
+// valid value, 0 or DutyMax, along with an error.
+func DutyFromPulse(width time.Duration, f physic.Frequency) (Duty, error) {
+	return DutyFromPulseRound(width, f, RoundNearest)
+}
+
+// DutyFromPulseRound is like DutyFromPulse with a choice of rounding.
+func DutyFromPulseRound(width time.Duration, f physic.Frequency, r Rounding) (Duty, error) {
+	if f <= 0 {
+		return 0, errors.New("gpio: frequency must be > 0")
+	}
+	if f > maxDutyFrequency {
+		return 0, errors.New("gpio: frequency must be <= " + maxDutyFrequency.String())
+	}
+	if width < 0 {
+		return 0, errors.New("gpio: pulse width must be >= 0")
+	}
+	// duty = width * f * DutyMax / (time.Second * Hertz), which simplifies as
+	// width * f * 2**9 / 5**15 since DutyMax is 2**24.
+	q, ok := mulDiv(uint64(width), uint64(f)<<9, fivePow15, r)
+	if !ok || q > uint64(DutyMax) {
+		return DutyMax, errors.New("gpio: pulse width must be <= " + f.Period().String())
+	}
+	return Duty(q), nil
+}
+
 func (d Duty) String() string {
-	// TODO(maruel): Implement one fractional number.
-	return strconv.Itoa(int((d+50)/(DutyMax/100))) + "%"
+	tenths := (int64(d)*1000 + int64(DutyMax/2)) / int64(DutyMax)
+	return strconv.FormatFloat(float64(tenths)/10, 'f', -1, 64) + "%"
 }
 
 // Valid returns true if the Duty cycle value is valid.
@@ -105,34 +139,106 @@ func (d Duty) Valid() bool {
 	return d >= 0 && d <= DutyMax
 }
 
+// Clamp returns the nearest valid Duty.
+func (d Duty) Clamp() Duty {
+	if d < 0 {
+		return 0
+	}
+	if d > DutyMax {
+		return DutyMax
+	}
+	return d
+}
+
+// PulseWidth returns the width of the pulse of the duty cycle at frequency f,
+// rounded to the nearest nanosecond.
+//
+// An invalid Duty is clamped first. Returns 0 if f is not above 0.
+func (d Duty) PulseWidth(f physic.Frequency) time.Duration {
+	return d.PulseWidthRound(f, RoundNearest)
+}
+
+// PulseWidthRound is like PulseWidth with a choice of rounding.
+func (d Duty) PulseWidthRound(f physic.Frequency, r Rounding) time.Duration {
+	d = d.Clamp()
+	if f <= 0 || d == 0 {
+		return 0
+	}
+	if f > maxDutyFrequency {
+		// The period is shorter than 1ns.
+		if r == RoundUp {
+			return 1
+		}
+		return 0
+	}
+	q, _ := mulDiv(uint64(d), fivePow15, uint64(f)<<9, r)
+	return time.Duration(q)
+}
+
 // ParseDuty parses a string and converts it to a Duty value.
+//
+// The string can be a percentage like "12.5%", a ratio between 0 and 1 with a
+// decimal point like "0.125", or an integer between 0 and DutyMax. Fractional
+// values are rounded to the nearest Duty.
 func ParseDuty(s string) (Duty, error) {
 	percent := strings.HasSuffix(s, "%")
 	if percent {
 		s = s[:len(s)-1]
 	}
-	i64, err := strconv.ParseInt(s, 10, 32)
+	if !percent && !strings.Contains(s, ".") {
+		i64, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, err
+		}
+		i := Duty(i64)
+		if i < 0 {
+			return 0, errors.New("duty must be >= 0")
+		}
+		if i > DutyMax {
+			return 0, errors.New("duty must be <= " + strconv.Itoa(int(DutyMax)))
+		}
+		return i, nil
+	}
+	lo, hi := "0", "1"
+	if percent {
+		lo, hi = "0%", "100%"
+	}
+	if strings.HasPrefix(s, "-") {
+		return 0, errors.New("duty must be >= " + lo)
+	}
+	num, den, err := parseDecimal(s)
 	if err != nil {
 		return 0, err
 	}
-	i := Duty(i64)
 	if percent {
-		// TODO(maruel): Add support for fractional number.
-		if i < 0 {
-			return 0, errors.New("duty must be >= 0%")
-		}
-		if i > 100 {
-			return 0, errors.New("duty must be <= 100%")
-		}
-		return ((i * DutyMax) + 49) / 100, nil
+		den *= 100
 	}
-	if i < 0 {
-		return 0, errors.New("duty must be >= 0")
+	if num > den {
+		return 0, errors.New("duty must be <= " + hi)
 	}
-	if i > DutyMax {
-		return 0, errors.New("duty must be <= " + strconv.Itoa(int(DutyMax)))
+	q, _ := mulDiv(num, uint64(DutyMax), den, RoundNearest)
+	return Duty(q), nil
+}
+
+// Rounding selects how a conversion rounds a result that is not exact.
+type Rounding uint8
+
+// Acceptable rounding values.
+const (
+	RoundNearest Rounding = 0 // Round to nearest, half away from zero
+	RoundDown    Rounding = 1 // Round toward zero
+	RoundUp      Rounding = 2 // Round away from zero
+)
+
+const roundName = "RoundNearestRoundDownRoundUp"
+
+var roundIndex = [...]uint8{0, 12, 21, 28}
+
+func (r Rounding) String() string {
+	if r >= Rounding(len(roundIndex)-1) {
+		return "Rounding(" + strconv.Itoa(int(r)) + ")"
 	}
-	return i, nil
+	return roundName[roundIndex[r]:roundIndex[r+1]]
 }
 
 // PinIn is an input GPIO pin.
@@ -250,7 +356,97 @@ type RealPin interface {
 	Real() PinIO // Real returns the real pin behind an Alias
 }
 
+// EdgeEvent is an edge detected on an input pin.
+type EdgeEvent struct {
+	// Time is when the edge was detected. Its precision depends on the driver.
+	Time time.Time
+	// Edge is either RisingEdge or FallingEdge.
+	Edge Edge
+	// Level is the level of the pin right after the edge.
+	Level Level
+	// Seq is the sequence number of the event, starting at 1. It is
+	// incremented for every edge detected, including the ones lost, so a gap
+	// between two events means that events were dropped.
+	Seq uint64
+}
+
+// PinEdgeEvents is an input pin that reports timestamped edges.
+//
+// It is optionally implemented by a PinIn that supports edge detection, like
+// the GPIO character device on linux. Use gpioutil.EdgeEvents() to get one
+// from any pin.
+type PinEdgeEvents interface {
+	PinIn
+	// WaitForEdgeEvent waits for the next edge or immediately return the
+	// oldest edge that occurred since the last call.
+	//
+	// It follows the same rules as WaitForEdge(). The edges are queued instead
+	// of being coalesced; when the queue overflows, the oldest edges are
+	// dropped and Seq skips accordingly.
+	//
+	// Returns false if the timeout occurred or In() was called while waiting.
+	WaitForEdgeEvent(timeout time.Duration) (EdgeEvent, bool)
+}
+
+//
+
+const (
+	// fivePow15 is 5**15, so time.Second * Hertz is 2**15 * fivePow15.
+	fivePow15 = 30517578125
+	// maxDutyFrequency is the highest frequency where f * 2**9 fits in an
+	// int64.
+	maxDutyFrequency = physic.Frequency(math.MaxInt64 >> 9)
+)
+
+// mulDiv returns a * b / c, rounded with r.
+//
+// Returns false if the result doesn't fit in an uint64.
+func mulDiv(a, b, c uint64, r Rounding) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= c {
+		return 0, false
+	}
+	q, rem := bits.Div64(hi, lo, c)
+	switch r {
+	case RoundNearest:
+		if rem >= c-rem {
+			q++
+		}
+	case RoundUp:
+		if rem != 0 {
+			q++
+		}
+	}
+	return q, true
+}
+
+// parseDecimal parses an unsigned decimal number as num / den.
 //
+// Digits past the 9th fractional one are ignored.
+func parseDecimal(s string) (uint64, uint64, error) {
+	i, f, _ := strings.Cut(s, ".")
+	if i == "" && f == "" {
+		return 0, 0, errors.New("invalid number " + strconv.Quote(s))
+	}
+	num := uint64(0)
+	if i != "" {
+		var err error
+		if num, err = strconv.ParseUint(i, 10, 32); err != nil {
+			return 0, 0, err
+		}
+	}
+	den := uint64(1)
+	for j := 0; j < len(f); j++ {
+		if f[j] < '0' || f[j] > '9' {
+			return 0, 0, errors.New("invalid number " + strconv.Quote(s))
+		}
+		if j < 9 {
+			num = num*10 + uint64(f[j]-'0')
+			den *= 10
+		}
+	}
+	return num, den, nil
+}
 
 // errInvalidPin is returned when trying to use INVALID.
 var errInvalidPin = errors.New("gpio: invalid pin")
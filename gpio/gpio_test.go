@@ -42,7 +42,11 @@ func TestDuty_String(t *testing.T) {
 	}{
 		{0, "0%"},
 		{1, "0%"},
-		{DutyMax / 200, "0%"},
+		{DutyMax / 200, "0.5%"},
+		{DutyMax / 8, "12.5%"},
+		{DutyMax / 3, "33.3%"},
+		{DutyMax / 1000, "0.1%"},
+		{DutyMax / 2000, "0%"},
 		{DutyMax/100 - 1, "1%"},
 		{DutyMax / 100, "1%"},
 		{DutyMax, "100%"},
@@ -95,6 +99,22 @@ func TestParseDuty(t *testing.T) {
 		{"101%", 0, true},
 		{"-1", 0, true},
 		{"-1%", 0, true},
+		{"12.5%", DutyMax / 8, false},
+		{"0.125", DutyMax / 8, false},
+		{".5", DutyHalf, false},
+		{"1.", DutyMax, false},
+		{"1.0", DutyMax, false},
+		{"0.0000001", 2, false},
+		{"33.33333333333%", 5592405, false},
+		{"100.0%", DutyMax, false},
+		{"100.1%", 0, true},
+		{"1.5", 0, true},
+		{"-0.5", 0, true},
+		{"-0.5%", 0, true},
+		{".", 0, true},
+		{"%", 0, true},
+		{"0.1a", 0, true},
+		{"a.1", 0, true},
 	}
 	for i, line := range data {
 		if d, err := ParseDuty(line.input); d != line.d || (err != nil) != line.hasErr {
@@ -103,6 +123,103 @@ func TestParseDuty(t *testing.T) {
 	}
 }
 
+func TestDuty_Clamp(t *testing.T) {
+	data := []struct {
+		d        Duty
+		expected Duty
+	}{
+		{-1, 0},
+		{0, 0},
+		{DutyHalf, DutyHalf},
+		{DutyMax, DutyMax},
+		{DutyMax + 1, DutyMax},
+	}
+	for i, line := range data {
+		if actual := line.d.Clamp(); actual != line.expected {
+			t.Fatalf("line %d: Duty(%d).Clamp() == %d, expected %d", i, line.d, actual, line.expected)
+		}
+	}
+}
+
+func TestDutyFromPulse(t *testing.T) {
+	data := []struct {
+		width    time.Duration
+		f        physic.Frequency
+		r        Rounding
+		expected Duty
+		hasErr   bool
+	}{
+		{1500 * time.Microsecond, 50 * physic.Hertz, RoundNearest, 1258291, false},
+		{1500 * time.Microsecond, 50 * physic.Hertz, RoundDown, 1258291, false},
+		{1500 * time.Microsecond, 50 * physic.Hertz, RoundUp, 1258292, false},
+		{10 * time.Millisecond, 50 * physic.Hertz, RoundUp, DutyHalf, false},
+		{20 * time.Millisecond, 50 * physic.Hertz, RoundNearest, DutyMax, false},
+		{0, 50 * physic.Hertz, RoundNearest, 0, false},
+		{500 * time.Nanosecond, physic.MegaHertz, RoundNearest, DutyHalf, false},
+		{time.Second / 3, 1 * physic.Hertz, RoundNearest, 5592405, false},
+		{time.Second / 3, 1 * physic.Hertz, RoundUp, 5592406, false},
+		{-1, 50 * physic.Hertz, RoundNearest, 0, true},
+		{20*time.Millisecond + 1, 50 * physic.Hertz, RoundNearest, DutyMax, true},
+		{time.Hour, physic.GigaHertz, RoundNearest, DutyMax, true},
+		{time.Millisecond, 0, RoundNearest, 0, true},
+		{time.Millisecond, -physic.Hertz, RoundNearest, 0, true},
+		{0, 100 * physic.GigaHertz, RoundNearest, 0, true},
+	}
+	for i, line := range data {
+		d, err := DutyFromPulseRound(line.width, line.f, line.r)
+		if d != line.expected || (err != nil) != line.hasErr {
+			t.Fatalf("line %d: DutyFromPulseRound(%s, %s, %s) == %d, %v, expected %d, %t", i, line.width, line.f, line.r, d, err, line.expected, line.hasErr)
+		}
+		if line.r == RoundNearest {
+			if d2, err2 := DutyFromPulse(line.width, line.f); d2 != d || (err2 != nil) != (err != nil) {
+				t.Fatalf("line %d: DutyFromPulse(%s, %s) == %d, %v", i, line.width, line.f, d2, err2)
+			}
+		}
+	}
+}
+
+func TestDuty_PulseWidth(t *testing.T) {
+	data := []struct {
+		d        Duty
+		f        physic.Frequency
+		r        Rounding
+		expected time.Duration
+	}{
+		{DutyHalf, 50 * physic.Hertz, RoundNearest, 10 * time.Millisecond},
+		{DutyMax, 50 * physic.Hertz, RoundNearest, 20 * time.Millisecond},
+		{DutyMax + 1, 50 * physic.Hertz, RoundNearest, 20 * time.Millisecond},
+		{-1, 50 * physic.Hertz, RoundUp, 0},
+		{1258291, 50 * physic.Hertz, RoundNearest, 1500000},
+		{1258291, 50 * physic.Hertz, RoundDown, 1499999},
+		{1258292, 50 * physic.Hertz, RoundDown, 1500000},
+		{1, physic.MegaHertz, RoundNearest, 0},
+		{1, physic.MegaHertz, RoundUp, 1},
+		{DutyMax / 3, physic.Hertz, RoundNearest, 333333313},
+		{DutyHalf, 0, RoundNearest, 0},
+		{DutyHalf, 100 * physic.GigaHertz, RoundNearest, 0},
+		{DutyHalf, 100 * physic.GigaHertz, RoundUp, 1},
+	}
+	for i, line := range data {
+		if actual := line.d.PulseWidthRound(line.f, line.r); actual != line.expected {
+			t.Fatalf("line %d: Duty(%d).PulseWidthRound(%s, %s) == %s, expected %s", i, line.d, line.f, line.r, actual, line.expected)
+		}
+		if line.r == RoundNearest {
+			if actual := line.d.PulseWidth(line.f); actual != line.expected {
+				t.Fatalf("line %d: Duty(%d).PulseWidth(%s) == %s, expected %s", i, line.d, line.f, actual, line.expected)
+			}
+		}
+	}
+}
+
+func TestRounding_String(t *testing.T) {
+	if s := RoundUp.String(); s != "RoundUp" {
+		t.Fatal(s)
+	}
+	if s := Rounding(10).String(); s != "Rounding(10)" {
+		t.Fatal(s)
+	}
+}
+
 func TestInvalid(t *testing.T) {
 	// conn.Resource
 	if s := INVALID.String(); s != "INVALID" {
@@ -0,0 +1,87 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpiotest
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// TestPinIO verifies that p follows the contracts documented in package gpio.
+//
+// It changes the state of p and leaves it as an input without edge
+// detection. p must not be connected to anything that could be damaged by
+// driving it:
+//   - String() and Name() are not empty;
+//   - Halt() is idempotent;
+//   - In() succeeds without pull nor edge detection;
+//   - Pull() returns the pull set with In() or PullNoChange;
+//   - Out() succeeds for both levels;
+//   - WaitForEdge() returns false when In() is called while waiting, if edge
+//     detection is supported.
+//
+// It reports failures with t.Errorf().
+func TestPinIO(t testing.TB, p gpio.PinIO) {
+	t.Helper()
+	if p.String() == "" {
+		t.Errorf("String() is empty")
+	}
+	if p.Name() == "" {
+		t.Errorf("Name() is empty")
+	}
+	if err1, err2 := p.Halt(), p.Halt(); (err1 == nil) != (err2 == nil) {
+		t.Errorf("Halt() is not idempotent: %v then %v", err1, err2)
+	}
+	for _, l := range []gpio.Level{gpio.Low, gpio.High} {
+		if err := p.Out(l); err != nil {
+			t.Errorf("Out(%s): %v", l, err)
+		}
+	}
+	if err := p.In(gpio.PullNoChange, gpio.NoEdge); err != nil {
+		t.Errorf("In(PullNoChange, NoEdge): %v", err)
+	}
+	for _, pull := range []gpio.Pull{gpio.PullDown, gpio.PullUp, gpio.Float} {
+		if p.In(pull, gpio.NoEdge) != nil {
+			// The pull is not supported.
+			continue
+		}
+		if got := p.Pull(); got != pull && got != gpio.PullNoChange {
+			t.Errorf("Pull() returned %s after In(%s, NoEdge)", got, pull)
+		}
+	}
+	if p.In(gpio.PullNoChange, gpio.BothEdges) == nil {
+		const timeout = 10 * time.Second
+		done := make(chan bool)
+		go func() {
+			done <- p.WaitForEdge(timeout)
+		}()
+		// There's no way to know when WaitForEdge() started waiting; call In()
+		// until it returns.
+		start := time.Now()
+	loop:
+		for {
+			select {
+			case got := <-done:
+				if got {
+					// A real edge happened; it doesn't prove anything.
+					break loop
+				}
+				if time.Since(start) >= timeout {
+					t.Errorf("WaitForEdge() didn't return when In() was called")
+				}
+				break loop
+			case <-time.After(10 * time.Millisecond):
+				if err := p.In(gpio.PullNoChange, gpio.BothEdges); err != nil {
+					t.Errorf("In(PullNoChange, BothEdges): %v", err)
+				}
+			}
+		}
+	}
+	if err := p.In(gpio.PullNoChange, gpio.NoEdge); err != nil {
+		t.Errorf("In(PullNoChange, NoEdge): %v", err)
+	}
+}
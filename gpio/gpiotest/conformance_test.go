@@ -0,0 +1,43 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpiotest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+func TestTestPinIO(t *testing.T) {
+	TestPinIO(t, &Pin{N: "GPIO1", Num: 1, EdgesChan: make(chan gpio.Level)})
+	// Without EdgesChan, edge detection is not supported.
+	TestPinIO(t, &Pin{N: "GPIO2", Num: 2})
+
+	f := &fakeTB{}
+	TestPinIO(f, &Pin{})
+	if !strings.Contains(f.String(), "Name() is empty") {
+		t.Fatal(f.String())
+	}
+}
+
+//
+
+// fakeTB records the errors reported by the conformance tests.
+type fakeTB struct {
+	testing.TB
+	errs []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errs = append(f.errs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) String() string {
+	return strings.Join(f.errs, "\n")
+}
@@ -37,6 +37,8 @@ type Pin struct {
 	EdgesChan chan gpio.Level  // Use it to fake edges
 	D         gpio.Duty        // PWM duty
 	F         physic.Frequency // PWM period
+
+	wake chan struct{} // Closed by In() to abort WaitForEdge().
 }
 
 // String implements conn.Resource.
@@ -94,6 +96,10 @@ func (p *Pin) In(pull gpio.Pull, edge gpio.Edge) error {
 	if edge != gpio.NoEdge && p.EdgesChan == nil {
 		return errors.New("gpiotest: please set p.EdgesChan first")
 	}
+	if p.wake != nil {
+		close(p.wake)
+		p.wake = nil
+	}
 	// Flush any buffered edges.
 	for {
 		select {
@@ -112,17 +118,27 @@ func (p *Pin) Read() gpio.Level {
 }
 
 // WaitForEdge implements gpio.PinIn.
+//
+// It returns false if In() is called while waiting.
 func (p *Pin) WaitForEdge(timeout time.Duration) bool {
 	if p.Clock == nil {
 		p.Clock = clockwork.NewRealClock()
 	}
+	p.Lock()
+	if p.wake == nil {
+		p.wake = make(chan struct{})
+	}
+	wake := p.wake
+	p.Unlock()
 
-	if timeout == -1 {
-		_ = p.Out(<-p.EdgesChan)
-		return true
+	var after <-chan time.Time
+	if timeout != -1 {
+		after = p.Clock.After(timeout)
 	}
 	select {
-	case <-p.Clock.After(timeout):
+	case <-after:
+		return false
+	case <-wake:
 		return false
 	case l := <-p.EdgesChan:
 		_ = p.Out(l)
@@ -158,6 +174,8 @@ func (p *Pin) PWM(duty gpio.Duty, f physic.Frequency) error {
 }
 
 // LogPinIO logs when its state changes.
+//
+// Use connutil.TracePin for structured logging outside of tests.
 type LogPinIO struct {
 	gpio.PinIO
 }
@@ -0,0 +1,115 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpiotest
+
+import (
+	"errors"
+	"sync"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// Wire simulates an open-drain line with a pull-up resistor, shared by
+// multiple pins, like the lines of an I²C or 1-wire bus.
+//
+// The line is Low as long as at least one of its pins drives it Low, High
+// otherwise.
+//
+// Use it to test a bit-banged protocol against a simulated device, by
+// reacting to the changes with OnChange.
+type Wire struct {
+	// OnChange, if set, is called after each change of the level of the line.
+	//
+	// It is called synchronously from the In() or Out() call that caused the
+	// change, without any lock held, so it can drive the pins of the Wire.
+	OnChange func(l gpio.Level)
+
+	mu sync.Mutex
+	// low is the number of pins driving the line Low.
+	low int
+}
+
+// Pin returns a new pin connected to the line.
+//
+// The pin is initially released.
+func (w *Wire) Pin(name string, num int) *WirePin {
+	return &WirePin{Pin: Pin{N: name, Num: num, L: gpio.High}, w: w}
+}
+
+// Read returns the level of the line.
+func (w *Wire) Read() gpio.Level {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.low == 0
+}
+
+// WirePin implements gpio.PinIO and is a pin connected to a Wire.
+//
+// Edge detection is not supported.
+type WirePin struct {
+	Pin
+
+	w     *Wire
+	drive bool // Guarded by w.mu.
+}
+
+// In implements gpio.PinIn.
+//
+// It releases the line.
+func (p *WirePin) In(pull gpio.Pull, edge gpio.Edge) error {
+	if edge != gpio.NoEdge {
+		return errors.New("gpiotest: edge detection is not supported on a Wire")
+	}
+	p.Lock()
+	p.P = pull
+	p.Unlock()
+	p.set(false)
+	return nil
+}
+
+// Read implements gpio.PinIn.
+//
+// It returns the level of the line.
+func (p *WirePin) Read() gpio.Level {
+	return p.w.Read()
+}
+
+// Out implements gpio.PinOut.
+//
+// Low drives the line Low, High releases it.
+func (p *WirePin) Out(l gpio.Level) error {
+	p.Lock()
+	p.L = l
+	p.Unlock()
+	p.set(l == gpio.Low)
+	return nil
+}
+
+//
+
+// set drives the line Low or releases it.
+func (p *WirePin) set(drive bool) {
+	w := p.w
+	w.mu.Lock()
+	if p.drive == drive {
+		w.mu.Unlock()
+		return
+	}
+	before := w.low == 0
+	p.drive = drive
+	if drive {
+		w.low++
+	} else {
+		w.low--
+	}
+	after := w.low == 0
+	f := w.OnChange
+	w.mu.Unlock()
+	if before != after && f != nil {
+		f(gpio.Level(after))
+	}
+}
+
+var _ gpio.PinIO = &WirePin{}
@@ -0,0 +1,56 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpiotest
+
+import (
+	"reflect"
+	"testing"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+func TestWire(t *testing.T) {
+	var changes []gpio.Level
+	w := Wire{}
+	a := w.Pin("A", 1)
+	b := w.Pin("B", 2)
+	w.OnChange = func(l gpio.Level) {
+		changes = append(changes, l)
+		if l == gpio.Low {
+			// The callback can drive the line.
+			_ = b.Out(gpio.Low)
+		}
+	}
+	if w.Read() != gpio.High || a.Read() != gpio.High {
+		t.Fatal("the line is pulled up")
+	}
+	if err := a.Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if w.Read() != gpio.Low || b.Read() != gpio.Low {
+		t.Fatal("expected Low")
+	}
+	if err := a.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if b.Read() != gpio.Low {
+		t.Fatal("b still drives the line")
+	}
+	if err := b.Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if a.Read() != gpio.High || a.Pull() != gpio.PullUp {
+		t.Fatal("expected High")
+	}
+	if expected := []gpio.Level{gpio.Low, gpio.High}; !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("%v != %v", changes, expected)
+	}
+	if a.In(gpio.PullUp, gpio.BothEdges) == nil {
+		t.Fatal("edge detection is not supported")
+	}
+	if a.String() != "A(1)" {
+		t.Fatal(a.String())
+	}
+}
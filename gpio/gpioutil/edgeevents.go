@@ -0,0 +1,90 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3/gpio"
+)
+
+// EdgeEvents returns a gpio.PinEdgeEvents from a gpio.PinIn.
+//
+// If p already implements gpio.PinEdgeEvents, it is returned as is. Otherwise
+// the edges are detected with WaitForEdge() and the level is then sampled with
+// Read(). The timestamp is taken as soon as WaitForEdge() returns, so its
+// precision is limited by the scheduling latency of the OS.
+//
+// The fallback cannot queue edges. With gpio.BothEdges, an edge reporting the
+// same level as the previous event means that the opposite edge was missed and
+// Seq skips one. With gpio.RisingEdge or gpio.FallingEdge, lost edges are not
+// detectable. In() must be called on the returned pin for the edge
+// detection to be known, otherwise the edge is inferred from the level.
+func EdgeEvents(p gpio.PinIn) gpio.PinEdgeEvents {
+	if e, ok := p.(gpio.PinEdgeEvents); ok {
+		return e
+	}
+	return &edgeEvents{PinIn: p, clock: clockwork.NewRealClock()}
+}
+
+// edgeEvents is a gpio.PinEdgeEvents built on WaitForEdge and Read.
+type edgeEvents struct {
+	// Immutable.
+	gpio.PinIn
+	clock clockwork.Clock
+
+	// Mutable.
+	mu sync.Mutex
+	// edge is the edge detection set with In().
+	edge gpio.Edge
+	// last is the level of the previous event, or the level when In() was
+	// called.
+	last gpio.Level
+	seq  uint64
+}
+
+// In implements gpio.PinIn.
+func (e *edgeEvents) In(pull gpio.Pull, edge gpio.Edge) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.edge = gpio.NoEdge
+	if err := e.PinIn.In(pull, edge); err != nil {
+		return err
+	}
+	e.edge = edge
+	e.last = e.PinIn.Read()
+	return nil
+}
+
+// WaitForEdgeEvent implements gpio.PinEdgeEvents.
+func (e *edgeEvents) WaitForEdgeEvent(timeout time.Duration) (gpio.EdgeEvent, bool) {
+	if !e.PinIn.WaitForEdge(timeout) {
+		return gpio.EdgeEvent{}, false
+	}
+	ev := gpio.EdgeEvent{Time: e.clock.Now(), Level: e.PinIn.Read()}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch e.edge {
+	case gpio.RisingEdge, gpio.FallingEdge:
+		ev.Edge = e.edge
+	default:
+		if e.edge == gpio.BothEdges && ev.Level == e.last {
+			// The opposite edge was missed.
+			e.seq++
+		}
+		ev.Edge = gpio.FallingEdge
+		if ev.Level == gpio.High {
+			ev.Edge = gpio.RisingEdge
+		}
+	}
+	e.seq++
+	ev.Seq = e.seq
+	e.last = ev.Level
+	return ev, true
+}
+
+var _ gpio.PinEdgeEvents = &edgeEvents{}
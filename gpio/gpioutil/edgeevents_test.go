@@ -0,0 +1,117 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+)
+
+func TestEdgeEvents_BothEdges(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	p := &gpiotest.Pin{N: "GPIO1", EdgesChan: make(chan gpio.Level, 1), Clock: clock}
+	e := EdgeEvents(p).(*edgeEvents)
+	e.clock = clock
+	if err := e.In(gpio.PullNoChange, gpio.BothEdges); err != nil {
+		t.Fatal(err)
+	}
+	start := clock.Now()
+	data := []struct {
+		l     gpio.Level
+		edge  gpio.Edge
+		seq   uint64
+		after time.Duration
+	}{
+		{gpio.High, gpio.RisingEdge, 1, time.Millisecond},
+		{gpio.Low, gpio.FallingEdge, 2, 2 * time.Millisecond},
+		// The rising edge was missed.
+		{gpio.Low, gpio.FallingEdge, 4, 5 * time.Millisecond},
+		{gpio.High, gpio.RisingEdge, 5, time.Second},
+	}
+	var total time.Duration
+	for i, line := range data {
+		clock.Advance(line.after)
+		total += line.after
+		p.EdgesChan <- line.l
+		ev, ok := e.WaitForEdgeEvent(-1)
+		if !ok {
+			t.Fatalf("#%d: expected an event", i)
+		}
+		expected := gpio.EdgeEvent{Time: start.Add(total), Edge: line.edge, Level: line.l, Seq: line.seq}
+		if ev != expected {
+			t.Fatalf("#%d: %#v != %#v", i, expected, ev)
+		}
+	}
+	go func() {
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+	}()
+	if ev, ok := e.WaitForEdgeEvent(time.Second); ok {
+		t.Fatalf("unexpected event %#v", ev)
+	}
+}
+
+func TestEdgeEvents_RisingEdge(t *testing.T) {
+	p := &gpiotest.Pin{N: "GPIO1", EdgesChan: make(chan gpio.Level, 1)}
+	e := EdgeEvents(p)
+	if err := e.In(gpio.PullNoChange, gpio.RisingEdge); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		// A short pulse may already be over when the level is read.
+		p.EdgesChan <- gpio.Low
+		ev, ok := e.WaitForEdgeEvent(-1)
+		if !ok {
+			t.Fatal("expected an event")
+		}
+		if ev.Edge != gpio.RisingEdge || ev.Level != gpio.Low || ev.Seq != uint64(i) {
+			t.Fatalf("unexpected %#v", ev)
+		}
+	}
+}
+
+func TestEdgeEvents_NoIn(t *testing.T) {
+	p := &gpiotest.Pin{N: "GPIO1", EdgesChan: make(chan gpio.Level, 1)}
+	e := EdgeEvents(p)
+	for i, l := range []gpio.Level{gpio.High, gpio.High} {
+		p.EdgesChan <- l
+		ev, ok := e.WaitForEdgeEvent(-1)
+		if !ok {
+			t.Fatal("expected an event")
+		}
+		// Without In(), lost edges are not detected.
+		if ev.Edge != gpio.RisingEdge || ev.Level != gpio.High || ev.Seq != uint64(i+1) {
+			t.Fatalf("unexpected %#v", ev)
+		}
+	}
+}
+
+func TestEdgeEvents_In_Err(t *testing.T) {
+	e := EdgeEvents(&gpiotest.Pin{N: "GPIO1"})
+	if e.In(gpio.PullNoChange, gpio.BothEdges) == nil {
+		t.Fatal("expected error without EdgesChan")
+	}
+}
+
+func TestEdgeEvents_Native(t *testing.T) {
+	p := &nativeEdgeEvents{}
+	if e := EdgeEvents(p); e != p {
+		t.Fatal("expected the pin to be returned as is")
+	}
+}
+
+//
+
+type nativeEdgeEvents struct {
+	gpiotest.Pin
+}
+
+func (n *nativeEdgeEvents) WaitForEdgeEvent(timeout time.Duration) (gpio.EdgeEvent, bool) {
+	return gpio.EdgeEvent{}, false
+}
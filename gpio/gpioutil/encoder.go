@@ -0,0 +1,282 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3/gpio"
+)
+
+// EncoderEvent is a change of an Encoder.
+type EncoderEvent struct {
+	// Time is when the edge that caused the event was detected.
+	Time time.Time
+	// Position is the position of the encoder after the event.
+	Position int64
+	// Delta is 1 for a detent turned forward, -1 backward, 0 for a change of
+	// the button.
+	Delta int
+	// Pressed is true while the button is pressed.
+	Pressed bool
+}
+
+// Encoder decodes the signals of a quadrature rotary encoder, like the knobs
+// of a panel.
+//
+// The position increases when the signal A leads B. Each change of A or B is
+// a step in a gray code sequence; a detent is usually 4 steps for a knob with
+// as many detents as pulses per revolution.
+//
+// The edge detection must have been enabled with gpio.BothEdges on each pin
+// before calling NewEncoder(). Wrap the pins with Debounce() for mechanical
+// encoders; the decoder tolerates bounces on a single signal, as they cancel
+// out. When both signals changed between two edges processed, the encoder is
+// assumed to have continued in the same direction.
+type Encoder struct {
+	// Immutable.
+	pins   []gpio.PinIn
+	steps  int
+	clock  clockwork.Clock
+	events chan EncoderEvent
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	// Mutable.
+	mu sync.Mutex
+	// state is A<<1 | B.
+	state uint8
+	// sub is the steps since the last detent.
+	sub int
+	// lastStep is the direction of the last step.
+	lastStep int
+	pos      int64
+	dir      int
+	pressed  bool
+	// prev and last are the times of the last two detents in the direction
+	// dir.
+	prev   time.Time
+	last   time.Time
+	closed bool
+}
+
+// NewEncoder returns an Encoder decoding the signals a and b.
+//
+// stepsPerDetent is the number of steps of the quadrature sequence per
+// detent; it is 4 for most knobs, some use 2 or 1. button is an optional push
+// button, pressed when Low; it can be nil.
+//
+// A goroutine is started per pin; they are stopped by Close().
+func NewEncoder(a, b, button gpio.PinIn, stepsPerDetent int) (*Encoder, error) {
+	return newEncoderWithClock(a, b, button, stepsPerDetent, clockwork.NewRealClock())
+}
+
+// Position returns the number of detents turned since the creation.
+func (e *Encoder) Position() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.pos
+}
+
+// Direction returns the direction of the last detent, 1 forward, -1 backward,
+// or 0 if the encoder was not turned yet.
+func (e *Encoder) Direction() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.dir
+}
+
+// Velocity returns the estimated speed, in detents per second, negative when
+// turning backward.
+//
+// It is derived from the interval between the last two detents and decays
+// when the encoder stops turning. It is 0 until two detents were turned in the
+// same direction, and after one second without a detent.
+func (e *Encoder) Velocity() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.prev.IsZero() {
+		return 0
+	}
+	since := e.clock.Since(e.last)
+	if since > maxEncoderIdle {
+		return 0
+	}
+	interval := max(e.last.Sub(e.prev), since)
+	if interval <= 0 {
+		return 0
+	}
+	return float64(e.dir) / interval.Seconds()
+}
+
+// Pressed returns true while the button is pressed.
+func (e *Encoder) Pressed() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.pressed
+}
+
+// Events returns the channel of the events.
+//
+// Events are dropped when the channel is full; Position() stays accurate. The
+// channel is closed by Close().
+func (e *Encoder) Events() <-chan EncoderEvent {
+	return e.events
+}
+
+// Close stops decoding the signals.
+//
+// It waits for the goroutines to exit, which takes up to 100ms, then closes
+// the channel returned by Events(). The pins are left as is.
+func (e *Encoder) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return errEncoderClosed
+	}
+	e.closed = true
+	close(e.done)
+	e.mu.Unlock()
+	e.wg.Wait()
+	close(e.events)
+	return nil
+}
+
+//
+
+// maxEncoderIdle is the time after which the encoder is considered stopped.
+const maxEncoderIdle = time.Second
+
+var errEncoderClosed = errors.New("gpioutil: encoder closed")
+
+// quadrature is the step of each transition between two states, indexed by
+// the previous state << 2 | the new state. The forward sequence is 00, 10,
+// 11, 01.
+var quadrature = [16]int8{
+	0b0010: 1, 0b1011: 1, 0b1101: 1, 0b0100: 1,
+	0b0001: -1, 0b0111: -1, 0b1110: -1, 0b1000: -1,
+}
+
+func newEncoderWithClock(a, b, button gpio.PinIn, stepsPerDetent int, clock clockwork.Clock) (*Encoder, error) {
+	if stepsPerDetent < 1 {
+		return nil, errors.New("gpioutil: invalid steps per detent " + strconv.Itoa(stepsPerDetent))
+	}
+	e := &Encoder{
+		pins:   []gpio.PinIn{a, b},
+		steps:  stepsPerDetent,
+		clock:  clock,
+		events: make(chan EncoderEvent, 16),
+		done:   make(chan struct{}),
+	}
+	if a.Read() == gpio.High {
+		e.state |= 2
+	}
+	if b.Read() == gpio.High {
+		e.state |= 1
+	}
+	if button != nil {
+		e.pins = append(e.pins, button)
+		e.pressed = button.Read() == gpio.Low
+	}
+	e.wg.Add(len(e.pins))
+	for i := range e.pins {
+		go e.watch(i)
+	}
+	return e, nil
+}
+
+// watch loops on the edges of the pin at index i.
+func (e *Encoder) watch(i int) {
+	defer e.wg.Done()
+	p := EdgeEvents(e.pins[i])
+	for {
+		select {
+		case <-e.done:
+			return
+		default:
+		}
+		start := time.Now()
+		ev, ok := p.WaitForEdgeEvent(pollTimeout)
+		if !ok {
+			if !backOff(start, e.done) {
+				return
+			}
+			continue
+		}
+		e.update(i, ev)
+	}
+}
+
+// update processes an edge of the pin at index i.
+func (e *Encoder) update(i int, ev gpio.EdgeEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if i == 2 {
+		if pressed := ev.Level == gpio.Low; pressed != e.pressed {
+			e.pressed = pressed
+			e.emit(EncoderEvent{Time: ev.Time, Position: e.pos, Pressed: pressed})
+		}
+		return
+	}
+	// A is the bit 1, B the bit 0. The level of the other signal is read, as
+	// its edge may not be processed yet.
+	bit := uint8(2 >> uint(i))
+	next := uint8(0)
+	if ev.Level == gpio.High {
+		next |= bit
+	}
+	if e.pins[1-i].Read() == gpio.High {
+		next |= 3 &^ bit
+	}
+	if next == e.state {
+		// Both edges of a bounce were missed, or the edge was already seen
+		// when reading the other signal.
+		return
+	}
+	step := int(quadrature[e.state<<2|next])
+	if step == 0 {
+		// Both signals changed; assume the encoder continued in the same
+		// direction.
+		step = 2 * e.lastStep
+	}
+	e.state = next
+	if step > 0 {
+		e.lastStep = 1
+	} else if step < 0 {
+		e.lastStep = -1
+	}
+	e.sub += step
+	var dir int
+	switch {
+	case e.sub >= e.steps:
+		dir = 1
+	case e.sub <= -e.steps:
+		dir = -1
+	default:
+		return
+	}
+	// Keep the extra step when both signals changed past the detent.
+	e.sub -= dir * e.steps
+	e.pos += int64(dir)
+	if dir != e.dir {
+		e.dir = dir
+		e.last = time.Time{}
+	}
+	e.prev = e.last
+	e.last = ev.Time
+	e.emit(EncoderEvent{Time: ev.Time, Position: e.pos, Delta: dir, Pressed: e.pressed})
+}
+
+// emit sends ev without blocking. e.mu must be held.
+func (e *Encoder) emit(ev EncoderEvent) {
+	select {
+	case e.events <- ev:
+	default:
+	}
+}
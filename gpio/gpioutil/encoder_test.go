@@ -0,0 +1,280 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+)
+
+func TestEncoder(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	a, b, btn := newEncoderPin("A"), newEncoderPin("B"), newEncoderPin("BTN")
+	btn.L = gpio.High
+	e, err := newEncoderWithClock(a, b, btn, 4, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A leads B.
+	for _, p := range []*encoderPin{a, b, a, b} {
+		p.toggle(e, clock)
+	}
+	ev := <-e.Events()
+	if ev.Delta != 1 || ev.Position != 1 || ev.Pressed || !ev.Time.Equal(clock.Now()) {
+		t.Fatalf("%#v", ev)
+	}
+	if p := e.Position(); p != 1 {
+		t.Fatal(p)
+	}
+	btn.set(e, clock, gpio.Low)
+	if ev := <-e.Events(); ev.Delta != 0 || ev.Position != 1 || !ev.Pressed {
+		t.Fatalf("%#v", ev)
+	}
+	if !e.Pressed() {
+		t.Fatal("expected pressed")
+	}
+	// B leads A.
+	for _, p := range []*encoderPin{b, a, b, a} {
+		p.toggle(e, clock)
+	}
+	if ev := <-e.Events(); ev.Delta != -1 || ev.Position != 0 || !ev.Pressed {
+		t.Fatalf("%#v", ev)
+	}
+	if d := e.Direction(); d != -1 {
+		t.Fatal(d)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-e.Events(); ok {
+		t.Fatal("expected closed channel")
+	}
+	if e.Close() == nil {
+		t.Fatal("second Close() must fail")
+	}
+}
+
+func TestEncoder_Steps(t *testing.T) {
+	data := []struct {
+		steps int
+		seq   string
+		want  int64
+	}{
+		// A full forward cycle.
+		{4, "abab", 1},
+		{2, "abab", 2},
+		{1, "abab", 4},
+		{4, "baba", -1},
+		// A bounce on A cancels out.
+		{4, "aaabab", 1},
+		// Half a cycle forward then back.
+		{4, "abba", 0},
+		{1, "abba", 0},
+		{4, "abababab", 2},
+	}
+	for i, line := range data {
+		e, a, b := newTestEncoder(t, line.steps)
+		for _, c := range line.seq {
+			p, idx := a, 0
+			if c == 'b' {
+				p, idx = b, 1
+			}
+			e.update(idx, p.flip())
+		}
+		if p := e.Position(); p != line.want {
+			t.Fatalf("#%d: %d != %d", i, p, line.want)
+		}
+	}
+}
+
+func TestEncoder_Skipped(t *testing.T) {
+	e, a, b := newTestEncoder(t, 4)
+	e.update(0, a.flip())
+	// The edge of B is processed after A changed again: both signals changed.
+	b.flip()
+	e.update(0, a.flip())
+	e.update(1, gpio.EdgeEvent{Level: b.Read()})
+	e.update(1, b.flip())
+	if p := e.Position(); p != 1 {
+		t.Fatal(p)
+	}
+	// Both edges of a bounce were missed.
+	e.update(0, gpio.EdgeEvent{Level: a.Read()})
+	if p := e.Position(); p != 1 || e.sub != 0 {
+		t.Fatal(p, e.sub)
+	}
+	// Both signals changed one step before the detent, overshooting it.
+	e.update(0, a.flip())
+	e.update(1, b.flip())
+	e.update(0, a.flip())
+	a.flip()
+	e.update(1, b.flip())
+	e.update(0, gpio.EdgeEvent{Level: a.Read()})
+	if p := e.Position(); p != 2 || e.sub != 1 {
+		t.Fatal(p, e.sub)
+	}
+	// The next detent still lands on the same state.
+	e.update(1, b.flip())
+	e.update(0, a.flip())
+	e.update(1, b.flip())
+	if p := e.Position(); p != 3 || e.sub != 0 || e.state != 0 {
+		t.Fatal(p, e.sub, e.state)
+	}
+}
+
+func TestEncoder_Velocity(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	a, b := newEncoderPin("A"), newEncoderPin("B")
+	e, err := newEncoderWithClock(a, b, nil, 1, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if v := e.Velocity(); v != 0 {
+		t.Fatal(v)
+	}
+	turn := func(p *encoderPin, i int) {
+		ev := p.flip()
+		ev.Time = clock.Now()
+		e.update(i, ev)
+	}
+	turn(a, 0)
+	// A single detent doesn't tell the speed, however close to it.
+	clock.Advance(time.Millisecond)
+	if v := e.Velocity(); v != 0 {
+		t.Fatal(v)
+	}
+	clock.Advance(99 * time.Millisecond)
+	turn(b, 1)
+	if v := e.Velocity(); v != 10 {
+		t.Fatal(v)
+	}
+	// Slowing down.
+	clock.Advance(200 * time.Millisecond)
+	if v := e.Velocity(); v != 5 {
+		t.Fatal(v)
+	}
+	clock.Advance(time.Second)
+	if v := e.Velocity(); v != 0 {
+		t.Fatal(v)
+	}
+	// Reversing restarts the estimation.
+	turn(b, 1)
+	clock.Advance(time.Millisecond)
+	if v := e.Velocity(); v != 0 {
+		t.Fatal(v)
+	}
+	clock.Advance(249 * time.Millisecond)
+	turn(a, 0)
+	if v := e.Velocity(); v != -4 {
+		t.Fatal(v)
+	}
+}
+
+func TestEncoder_noEdges(t *testing.T) {
+	// Edge detection is not enabled so WaitForEdge() returns immediately.
+	a := &countingPin{PinIn: &gpiotest.Pin{N: "A"}}
+	b := &countingPin{PinIn: &gpiotest.Pin{N: "B"}}
+	e, err := NewEncoder(a, b, nil, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(pollTimeout + pollTimeout/2)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := a.n.Load() + b.n.Load(); n > 6 {
+		t.Fatalf("WaitForEdge() called %d times", n)
+	}
+}
+
+func TestEncoder_Invalid(t *testing.T) {
+	if _, err := NewEncoder(newEncoderPin("A"), newEncoderPin("B"), nil, 0); err == nil {
+		t.Fatal("invalid steps per detent")
+	}
+}
+
+//
+
+// newTestEncoder returns an Encoder whose edges are processed by calling
+// update().
+func newTestEncoder(t *testing.T, steps int) (*Encoder, *encoderPin, *encoderPin) {
+	a, b := newEncoderPin("A"), newEncoderPin("B")
+	e, err := newEncoderWithClock(a, b, nil, steps, clockwork.NewFakeClock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := e.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return e, a, b
+}
+
+// encoderPin implements gpio.PinEdgeEvents with edges sent by the test.
+type encoderPin struct {
+	gpiotest.Pin
+	events chan gpio.EdgeEvent
+}
+
+func newEncoderPin(name string) *encoderPin {
+	return &encoderPin{Pin: gpiotest.Pin{N: name}, events: make(chan gpio.EdgeEvent, 16)}
+}
+
+func (p *encoderPin) WaitForEdgeEvent(timeout time.Duration) (gpio.EdgeEvent, bool) {
+	select {
+	case ev := <-p.events:
+		return ev, true
+	case <-time.After(timeout):
+		return gpio.EdgeEvent{}, false
+	}
+}
+
+// flip inverts the level and returns the corresponding event, without
+// sending it.
+func (p *encoderPin) flip() gpio.EdgeEvent {
+	p.Lock()
+	defer p.Unlock()
+	p.L = !p.L
+	if p.L {
+		return gpio.EdgeEvent{Edge: gpio.RisingEdge, Level: p.L}
+	}
+	return gpio.EdgeEvent{Edge: gpio.FallingEdge, Level: p.L}
+}
+
+// toggle inverts the level, sends the event and waits for e to process it.
+func (p *encoderPin) toggle(e *Encoder, clock clockwork.Clock) {
+	p.set(e, clock, !p.Read())
+}
+
+func (p *encoderPin) set(e *Encoder, clock clockwork.Clock, l gpio.Level) {
+	if p.Read() != l {
+		ev := p.flip()
+		ev.Time = clock.Now()
+		p.events <- ev
+	}
+	for {
+		e.mu.Lock()
+		var done bool
+		switch p.N {
+		case "A":
+			done = (e.state&2 != 0) == bool(l)
+		case "B":
+			done = (e.state&1 != 0) == bool(l)
+		default:
+			done = e.pressed == (l == gpio.Low)
+		}
+		e.mu.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -44,6 +44,75 @@ func ExampleDebounce() {
 	}
 }
 
+func ExampleEdgeEvents() {
+	// Make sure periph is initialized.
+	// TODO: Use host.Init(). It is not used in this example to prevent circular
+	// go package import.
+	if _, err := driverreg.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	p := gpioreg.ByName("GPIO17")
+	if p == nil {
+		log.Fatal("please open another GPIO")
+	}
+
+	// Measure the period of a flow meter pulsing on each rising edge.
+	e := gpioutil.EdgeEvents(p)
+	if err := e.In(gpio.PullUp, gpio.RisingEdge); err != nil {
+		log.Fatal(err)
+	}
+	var prev gpio.EdgeEvent
+	for {
+		ev, ok := e.WaitForEdgeEvent(-1)
+		if !ok {
+			continue
+		}
+		if prev.Seq != 0 && ev.Seq == prev.Seq+1 {
+			fmt.Println(ev.Time.Sub(prev.Time))
+		}
+		prev = ev
+	}
+}
+
+func ExampleNewEncoder() {
+	// Make sure periph is initialized.
+	// TODO: Use host.Init(). It is not used in this example to prevent circular
+	// go package import.
+	if _, err := driverreg.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	a := gpioreg.ByName("GPIO5")
+	b := gpioreg.ByName("GPIO6")
+	sw := gpioreg.ByName("GPIO13")
+	if a == nil || b == nil || sw == nil {
+		log.Fatal("please open other GPIOs")
+	}
+	for _, p := range []gpio.PinIn{a, b, sw} {
+		if err := p.In(gpio.PullUp, gpio.BothEdges); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Only the push button needs debouncing, bounces on the signals of the
+	// knob cancel out.
+	btn, err := gpioutil.Debounce(sw, 3*time.Millisecond, 30*time.Millisecond, gpio.BothEdges)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer btn.Halt()
+
+	e, err := gpioutil.NewEncoder(a, b, btn, 4)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer e.Close()
+	for ev := range e.Events() {
+		fmt.Printf("position %d, pressed %t\n", ev.Position, ev.Pressed)
+	}
+}
+
 func ExamplePollEdge() {
 	// Make sure periph is initialized.
 	// TODO: Use host.Init(). It is not used in this example to prevent circular
@@ -0,0 +1,145 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/pin"
+)
+
+// NewGroup returns a gpio.Group made of individual pins, in order: bit i of
+// the values is the pin at offset i.
+//
+// WaitForEdge() is backed by a Watcher started on the first call, so the edge
+// detection must have been enabled on each pin with In() before. It returns
+// the GPIO number of the pin, as returned by Number(). Halt() stops the
+// Watcher and halts the pins.
+//
+// It returns an error if there are more pins than bits in gpio.GPIOValue.
+func NewGroup(pins ...gpio.PinIO) (gpio.Group, error) {
+	if len(pins) > 64 {
+		return nil, errors.New("gpioutil: a group has at most 64 pins")
+	}
+	return &pinGroup{pins: pins}, nil
+}
+
+//
+
+type pinGroup struct {
+	// Immutable.
+	pins []gpio.PinIO
+
+	// mu protects w.
+	mu sync.Mutex
+	w  *Watcher
+}
+
+func (g *pinGroup) String() string {
+	names := make([]string, len(g.pins))
+	for i, p := range g.pins {
+		names[i] = p.Name()
+	}
+	return "group(" + strings.Join(names, ",") + ")"
+}
+
+func (g *pinGroup) Pins() []pin.Pin {
+	out := make([]pin.Pin, len(g.pins))
+	for i, p := range g.pins {
+		out[i] = p
+	}
+	return out
+}
+
+func (g *pinGroup) ByOffset(offset int) pin.Pin {
+	if offset < 0 || offset >= len(g.pins) {
+		return nil
+	}
+	return g.pins[offset]
+}
+
+func (g *pinGroup) ByName(name string) pin.Pin {
+	for _, p := range g.pins {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (g *pinGroup) ByNumber(number int) pin.Pin {
+	for _, p := range g.pins {
+		if p.Number() == number {
+			return p
+		}
+	}
+	return nil
+}
+
+func (g *pinGroup) Out(value, mask gpio.GPIOValue) error {
+	for i, p := range g.pins {
+		if mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		if err := p.Out(value&(1<<uint(i)) != 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *pinGroup) Read(mask gpio.GPIOValue) (gpio.GPIOValue, error) {
+	var v gpio.GPIOValue
+	for i, p := range g.pins {
+		if mask&(1<<uint(i)) != 0 && p.Read() == gpio.High {
+			v |= 1 << uint(i)
+		}
+	}
+	return v, nil
+}
+
+func (g *pinGroup) WaitForEdge(timeout time.Duration) (int, gpio.Edge, error) {
+	g.mu.Lock()
+	if g.w == nil {
+		in := make([]gpio.PinIn, len(g.pins))
+		for i, p := range g.pins {
+			in[i] = p
+		}
+		g.w = NewWatcher(in...)
+	}
+	w := g.w
+	g.mu.Unlock()
+	i, e, err := w.WaitForEdge(timeout)
+	if err != nil {
+		return -1, e, err
+	}
+	return g.pins[i].Number(), e, nil
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the Watcher, if any, then halts the pins.
+func (g *pinGroup) Halt() error {
+	g.mu.Lock()
+	w := g.w
+	g.w = nil
+	g.mu.Unlock()
+	if w != nil {
+		_ = w.Close()
+	}
+	var err error
+	for _, p := range g.pins {
+		if err2 := p.Halt(); err == nil {
+			err = err2
+		}
+	}
+	return err
+}
+
+var _ gpio.Group = &pinGroup{}
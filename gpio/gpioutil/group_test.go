@@ -0,0 +1,65 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+)
+
+func TestNewGroup(t *testing.T) {
+	pins := newWatchedPins(t, 3)
+	pins[0].Num, pins[1].Num, pins[2].Num = 5, 7, 9
+	pins[0].N, pins[1].N, pins[2].N = "A", "B", "C"
+	g, err := NewGroup(pins[0], pins[1], pins[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := g.String(); s != "group(A,B,C)" {
+		t.Fatal(s)
+	}
+	if len(g.Pins()) != 3 || g.ByOffset(1) != pins[1] || g.ByOffset(3) != nil {
+		t.Fatal("unexpected pins")
+	}
+	if g.ByName("C") != pins[2] || g.ByName("D") != nil {
+		t.Fatal("unexpected ByName")
+	}
+	if g.ByNumber(7) != pins[1] || g.ByNumber(1) != nil {
+		t.Fatal("unexpected ByNumber")
+	}
+	if err := g.Out(0x5, 0x3); err != nil {
+		t.Fatal(err)
+	}
+	if pins[0].L != gpio.High || pins[1].L != gpio.Low || pins[2].L != gpio.Low {
+		t.Fatal(pins[0].L, pins[1].L, pins[2].L)
+	}
+	pins[2].L = gpio.High
+	if v, err := g.Read(0x6); v != 0x4 || err != nil {
+		t.Fatal(v, err)
+	}
+	// The GPIO number is returned, not the offset. The Watcher is started by
+	// the first call.
+	go func() {
+		pins[1].EdgesChan <- gpio.High
+	}()
+	if n, e, err := g.WaitForEdge(-1); n != 7 || e != gpio.RisingEdge || err != nil {
+		t.Fatal(n, e, err)
+	}
+	if err := g.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewGroup_tooLarge(t *testing.T) {
+	pins := make([]gpio.PinIO, 65)
+	for i := range pins {
+		pins[i] = &gpiotest.Pin{}
+	}
+	if _, err := NewGroup(pins...); err == nil {
+		t.Fatal("expected error")
+	}
+}
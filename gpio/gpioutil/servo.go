@@ -0,0 +1,261 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Servo drives a hobby servo with the PWM of a pin.
+//
+// The position of the servo is set by the width of a pulse repeated at 50Hz,
+// usually between 1ms and 2ms for a 180° range. Use SoftPWM() for a pin
+// without hardware PWM.
+//
+// The configuration fields must not be modified after the first call to a
+// method. The zero values select the common defaults.
+type Servo struct {
+	// Pin is the PWM pin connected to the signal wire of the servo.
+	Pin gpio.PinOut
+	// MinPulse is the pulse width at MinAngle. It defaults to 1ms.
+	MinPulse time.Duration
+	// MaxPulse is the pulse width at MaxAngle. It defaults to 2ms.
+	MaxPulse time.Duration
+	// MinAngle and MaxAngle are the range of the servo. They default to 0 and
+	// 180°.
+	MinAngle physic.Angle
+	MaxAngle physic.Angle
+	// Frequency is the PWM frequency. It defaults to 50Hz.
+	Frequency physic.Frequency
+	// Speed limits the angular speed, as the angle travelled per second. The
+	// servo then slews to the target position by a step at each PWM period.
+	// 0 means that the servo moves as fast as it can.
+	Speed physic.Angle
+
+	// clock is used for slewing. If nil, the real clock is used; tests set a
+	// fake one.
+	clock clockwork.Clock
+
+	// ctl serializes starting and stopping the slewing goroutine.
+	ctl  sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+
+	// mu protects the position, updated by the goroutine.
+	mu      sync.Mutex
+	pulse   time.Duration
+	target  time.Duration
+	slewing bool
+}
+
+func (s *Servo) String() string {
+	return "servo(" + s.Pin.String() + ")"
+}
+
+// SetAngle moves the servo to angle a, between MinAngle and MaxAngle.
+//
+// With Speed set, it returns immediately and the servo slews to a in the
+// background, unless the servo was detached.
+func (s *Servo) SetAngle(a physic.Angle) error {
+	minP, maxP, minA, maxA, _, err := s.config()
+	if err != nil {
+		return err
+	}
+	if (a < minA || a > maxA) && (a < maxA || a > minA) {
+		return errors.New("gpioutil: angle " + a.String() + " out of range")
+	}
+	return s.SetPulse(minP + time.Duration(int64(maxP-minP)*int64(a-minA)/int64(maxA-minA)))
+}
+
+// SetPulse moves the servo to the position for a pulse of width d, between
+// MinPulse and MaxPulse.
+//
+// With Speed set, it returns immediately and the servo slews to d in the
+// background, unless the servo was detached.
+func (s *Servo) SetPulse(d time.Duration) error {
+	minP, maxP, _, _, _, err := s.config()
+	if err != nil {
+		return err
+	}
+	if d < minP || d > maxP {
+		return errors.New("gpioutil: pulse " + d.String() + " out of range")
+	}
+	s.ctl.Lock()
+	defer s.ctl.Unlock()
+	s.mu.Lock()
+	if s.Speed == 0 || s.pulse == 0 {
+		s.mu.Unlock()
+		// Jump to the position.
+		s.halt()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.slewing = false
+		s.target = d
+		return s.apply(d)
+	}
+	s.target = d
+	running := s.slewing
+	s.slewing = true
+	s.mu.Unlock()
+	if !running {
+		// Reap the goroutine that reached the previous target, if any.
+		s.halt()
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.slew(s.stop, s.done)
+	}
+	return nil
+}
+
+// Pulse returns the current pulse width, or 0 if the servo is detached.
+//
+// While slewing, it is the position reached so far.
+func (s *Servo) Pulse() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pulse
+}
+
+// Angle returns the current angle.
+//
+// While slewing, it is the position reached so far. It is 0 when the servo is
+// detached, as reported by Pulse(), or when the configuration is invalid.
+func (s *Servo) Angle() physic.Angle {
+	minP, maxP, minA, maxA, _, err := s.config()
+	if err != nil {
+		return 0
+	}
+	d := s.Pulse()
+	if d == 0 {
+		return 0
+	}
+	return minA + physic.Angle(int64(d-minP)*int64(maxA-minA)/int64(maxP-minP))
+}
+
+// Halt implements conn.Resource.
+//
+// It stops slewing and detaches the servo by stopping the pulses, so it can
+// be moved by hand and doesn't draw current.
+func (s *Servo) Halt() error {
+	s.ctl.Lock()
+	defer s.ctl.Unlock()
+	s.halt()
+	s.mu.Lock()
+	s.slewing = false
+	s.pulse = 0
+	s.target = 0
+	s.mu.Unlock()
+	return s.Pin.Out(gpio.Low)
+}
+
+//
+
+// config returns the configuration with the defaults applied.
+//
+// It returns an error if a range is empty, as the conversions between angle
+// and pulse would divide by zero.
+func (s *Servo) config() (minP, maxP time.Duration, minA, maxA physic.Angle, f physic.Frequency, err error) {
+	minP, maxP = s.MinPulse, s.MaxPulse
+	if minP == 0 && maxP == 0 {
+		minP, maxP = time.Millisecond, 2*time.Millisecond
+	}
+	minA, maxA = s.MinAngle, s.MaxAngle
+	if minA == 0 && maxA == 0 {
+		maxA = 180 * physic.Degree
+	}
+	f = s.Frequency
+	if f == 0 {
+		f = 50 * physic.Hertz
+	}
+	if minP == maxP {
+		err = errors.New("gpioutil: MinPulse and MaxPulse must differ")
+	} else if minA == maxA {
+		err = errors.New("gpioutil: MinAngle and MaxAngle must differ")
+	}
+	return
+}
+
+// apply sets the PWM for pulse d.
+//
+// s.mu must be held.
+func (s *Servo) apply(d time.Duration) error {
+	_, _, _, _, f, _ := s.config()
+	duty, err := gpio.DutyFromPulse(d, f)
+	if err != nil {
+		return err
+	}
+	if err := s.Pin.PWM(duty, f); err != nil {
+		return err
+	}
+	s.pulse = d
+	return nil
+}
+
+// halt stops the slewing goroutine, if running.
+//
+// s.ctl must be held.
+func (s *Servo) halt() {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+		s.stop = nil
+		s.done = nil
+	}
+}
+
+// slew moves the servo toward the target by a step per PWM period, until it
+// is reached or stop is closed.
+func (s *Servo) slew(stop, done chan struct{}) {
+	defer close(done)
+	// The configuration was validated by SetPulse().
+	minP, maxP, minA, maxA, f, _ := s.config()
+	period := f.Period()
+	// The pulse width travelled per period; Speed is per second.
+	step := time.Duration(int64(maxP-minP) * int64(s.Speed) / int64(maxA-minA) * int64(period) / int64(time.Second))
+	if step < 0 {
+		step = -step
+	}
+	if step == 0 {
+		step = 1
+	}
+	clock := s.clock
+	if clock == nil {
+		clock = clockwork.NewRealClock()
+	}
+	for {
+		t := clock.NewTimer(period)
+		select {
+		case <-t.Chan():
+		case <-stop:
+			t.Stop()
+			return
+		}
+		s.mu.Lock()
+		d := s.target
+		switch {
+		case d > s.pulse+step:
+			d = s.pulse + step
+		case d < s.pulse-step:
+			d = s.pulse - step
+		}
+		// Errors can't be reported; try again at the next period.
+		_ = s.apply(d)
+		if s.pulse == s.target {
+			s.slewing = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+	}
+}
+
+var _ conn.Resource = &Servo{}
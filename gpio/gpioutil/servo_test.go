@@ -0,0 +1,194 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestServo(t *testing.T) {
+	p := &gpiotest.Pin{N: "GPIO1"}
+	s := Servo{Pin: p}
+	if s.String() != "servo(GPIO1(0))" {
+		t.Fatal(s.String())
+	}
+	if err := s.SetAngle(90 * physic.Degree); err != nil {
+		t.Fatal(err)
+	}
+	checkServo(t, &s, p, 1500*time.Microsecond)
+	if a := s.Angle(); a != 90*physic.Degree {
+		t.Fatal(a)
+	}
+	if err := s.SetAngle(0); err != nil {
+		t.Fatal(err)
+	}
+	checkServo(t, &s, p, time.Millisecond)
+	if err := s.SetPulse(2 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	checkServo(t, &s, p, 2*time.Millisecond)
+	if a := s.Angle(); a != 180*physic.Degree {
+		t.Fatal(a)
+	}
+	if err := s.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Pulse() != 0 || p.L != gpio.Low {
+		t.Fatal("expected detached")
+	}
+	if a := s.Angle(); a != 0 {
+		t.Fatal(a)
+	}
+}
+
+func TestServo_Config(t *testing.T) {
+	p := &gpiotest.Pin{N: "GPIO1"}
+	s := Servo{
+		Pin:       p,
+		MinPulse:  500 * time.Microsecond,
+		MaxPulse:  2500 * time.Microsecond,
+		MinAngle:  -135 * physic.Degree,
+		MaxAngle:  135 * physic.Degree,
+		Frequency: 330 * physic.Hertz,
+	}
+	if err := s.SetAngle(-135 * physic.Degree); err != nil {
+		t.Fatal(err)
+	}
+	if p.F != 330*physic.Hertz {
+		t.Fatal(p.F)
+	}
+	checkServo(t, &s, p, 500*time.Microsecond)
+	if err := s.SetAngle(0); err != nil {
+		t.Fatal(err)
+	}
+	checkServo(t, &s, p, 1500*time.Microsecond)
+}
+
+func TestServo_Err(t *testing.T) {
+	p := &gpiotest.Pin{N: "GPIO1"}
+	s := Servo{Pin: p}
+	for _, a := range []physic.Angle{-physic.Degree, 181 * physic.Degree} {
+		if s.SetAngle(a) == nil {
+			t.Fatalf("SetAngle(%s) should fail", a)
+		}
+	}
+	for _, d := range []time.Duration{999 * time.Microsecond, 2001 * time.Microsecond} {
+		if s.SetPulse(d) == nil {
+			t.Fatalf("SetPulse(%s) should fail", d)
+		}
+	}
+	s = Servo{Pin: &outPin{PinOut: gpio.INVALID}}
+	if s.SetAngle(0) == nil {
+		t.Fatal("PWM should fail")
+	}
+	// Empty ranges.
+	s = Servo{Pin: p, MinPulse: time.Millisecond, MaxPulse: time.Millisecond}
+	if s.SetAngle(0) == nil || s.SetPulse(time.Millisecond) == nil || s.Angle() != 0 {
+		t.Fatal("empty pulse range should fail")
+	}
+	s = Servo{Pin: p, MinAngle: 90 * physic.Degree, MaxAngle: 90 * physic.Degree}
+	if s.SetAngle(90*physic.Degree) == nil || s.SetPulse(time.Millisecond) == nil || s.Angle() != 0 {
+		t.Fatal("empty angle range should fail")
+	}
+}
+
+func TestServo_Speed(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	p := &gpiotest.Pin{N: "GPIO1"}
+	// 18° per 20ms period, so 100µs per period.
+	s := Servo{Pin: p, Speed: 900 * physic.Degree, clock: clock}
+	// The servo position is unknown so it jumps to it.
+	if err := s.SetAngle(90 * physic.Degree); err != nil {
+		t.Fatal(err)
+	}
+	checkServo(t, &s, p, 1500*time.Microsecond)
+	if err := s.SetAngle(135 * physic.Degree); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []time.Duration{1600 * time.Microsecond, 1700 * time.Microsecond} {
+		clock.BlockUntil(1)
+		clock.Advance(20 * time.Millisecond)
+		clock.BlockUntil(1)
+		checkServo(t, &s, p, expected)
+	}
+	// Change the target while slewing.
+	if err := s.SetAngle(180 * physic.Degree); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []time.Duration{1800 * time.Microsecond, 1900 * time.Microsecond} {
+		clock.Advance(20 * time.Millisecond)
+		clock.BlockUntil(1)
+		checkServo(t, &s, p, expected)
+	}
+	clock.Advance(20 * time.Millisecond)
+	waitSlewed(t, &s)
+	checkServo(t, &s, p, 2*time.Millisecond)
+
+	// Move back after the goroutine stopped; the last step is shorter.
+	if err := s.SetPulse(1850 * time.Microsecond); err != nil {
+		t.Fatal(err)
+	}
+	clock.BlockUntil(1)
+	clock.Advance(20 * time.Millisecond)
+	clock.BlockUntil(1)
+	checkServo(t, &s, p, 1900*time.Microsecond)
+	clock.Advance(20 * time.Millisecond)
+	waitSlewed(t, &s)
+	checkServo(t, &s, p, 1850*time.Microsecond)
+
+	// Halt while slewing.
+	if err := s.SetPulse(time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	clock.BlockUntil(1)
+	if err := s.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Pulse() != 0 || p.L != gpio.Low {
+		t.Fatal("expected detached")
+	}
+	// Once detached, it jumps to the position.
+	if err := s.SetPulse(time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	checkServo(t, &s, p, time.Millisecond)
+}
+
+//
+
+func checkServo(t *testing.T, s *Servo, p *gpiotest.Pin, expected time.Duration) {
+	t.Helper()
+	if d := s.Pulse(); d != expected {
+		t.Fatalf("Pulse() = %s, expected %s", d, expected)
+	}
+	f := s.Frequency
+	if f == 0 {
+		f = 50 * physic.Hertz
+	}
+	p.Lock()
+	duty := p.D
+	p.Unlock()
+	if d, _ := gpio.DutyFromPulse(expected, f); d != duty {
+		t.Fatalf("PWM duty = %d, expected %d", duty, d)
+	}
+}
+
+// waitSlewed waits for the slewing goroutine to exit.
+func waitSlewed(t *testing.T, s *Servo) {
+	s.ctl.Lock()
+	done := s.done
+	s.ctl.Unlock()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("slewing didn't stop")
+	}
+}
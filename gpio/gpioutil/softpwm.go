@@ -0,0 +1,217 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+// SoftPWM returns a gpio.PinIO that implements PWM() by toggling p with Out()
+// from a goroutine.
+//
+// It is meant for pins without hardware PWM, to dim a LED or drive a servo.
+// The timing is subject to the scheduling latency of the OS, so the frequency
+// should stay below a few kHz. The edges are scheduled on absolute deadlines
+// so the jitter doesn't accumulate; when the goroutine is late by more than a
+// period, the missed periods are skipped.
+//
+// PWM() can be called while the signal is generated; the new duty cycle and
+// frequency take effect at the start of the next period. Out(), In() and
+// Halt() stop the signal.
+//
+// If p doesn't implement gpio.PinIn, In() fails.
+func SoftPWM(p gpio.PinOut) gpio.PinIO {
+	return softPWMWithClock(p, clockwork.NewRealClock())
+}
+
+// softPWM is a gpio.PinIO where PWM is done in software.
+type softPWM struct {
+	// Immutable.
+	gpio.PinOut
+	clock clockwork.Clock
+
+	// ctl serializes starting and stopping the goroutine.
+	ctl  sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+
+	// mu protects the signal parameters, read by the goroutine.
+	mu     sync.Mutex
+	high   time.Duration
+	period time.Duration
+}
+
+func softPWMWithClock(p gpio.PinOut, clock clockwork.Clock) *softPWM {
+	return &softPWM{PinOut: p, clock: clock}
+}
+
+// Halt implements gpio.PinIO.
+//
+// It stops the signal.
+func (s *softPWM) Halt() error {
+	s.ctl.Lock()
+	defer s.ctl.Unlock()
+	s.halt()
+	return s.PinOut.Halt()
+}
+
+// In implements gpio.PinIO.
+func (s *softPWM) In(pull gpio.Pull, edge gpio.Edge) error {
+	p, ok := s.PinOut.(gpio.PinIn)
+	if !ok {
+		return errSoftPWMNoIn
+	}
+	s.ctl.Lock()
+	defer s.ctl.Unlock()
+	s.halt()
+	return p.In(pull, edge)
+}
+
+// Read implements gpio.PinIO.
+func (s *softPWM) Read() gpio.Level {
+	if p, ok := s.PinOut.(gpio.PinIn); ok {
+		return p.Read()
+	}
+	return gpio.Low
+}
+
+// WaitForEdge implements gpio.PinIO.
+func (s *softPWM) WaitForEdge(timeout time.Duration) bool {
+	if p, ok := s.PinOut.(gpio.PinIn); ok {
+		return p.WaitForEdge(timeout)
+	}
+	return false
+}
+
+// Pull implements gpio.PinIO.
+func (s *softPWM) Pull() gpio.Pull {
+	if p, ok := s.PinOut.(gpio.PinIn); ok {
+		return p.Pull()
+	}
+	return gpio.PullNoChange
+}
+
+// DefaultPull implements gpio.PinIO.
+func (s *softPWM) DefaultPull() gpio.Pull {
+	if p, ok := s.PinOut.(gpio.PinIn); ok {
+		return p.DefaultPull()
+	}
+	return gpio.PullNoChange
+}
+
+// Out implements gpio.PinIO.
+//
+// It stops the signal.
+func (s *softPWM) Out(l gpio.Level) error {
+	s.ctl.Lock()
+	defer s.ctl.Unlock()
+	s.halt()
+	return s.PinOut.Out(l)
+}
+
+// PWM implements gpio.PinIO.
+//
+// A frequency of 0 selects 100Hz. DutyMax and 0 stop the signal and set the
+// pin to a steady level.
+func (s *softPWM) PWM(duty gpio.Duty, f physic.Frequency) error {
+	if !duty.Valid() {
+		return errors.New("gpioutil: invalid duty " + duty.String())
+	}
+	if f < 0 {
+		return errors.New("gpioutil: invalid frequency " + f.String())
+	}
+	if f == 0 {
+		f = 100 * physic.Hertz
+	}
+	period := f.Period()
+	if period <= 0 {
+		return errors.New("gpioutil: frequency " + f.String() + " is too high")
+	}
+	s.ctl.Lock()
+	defer s.ctl.Unlock()
+	if duty == 0 || duty == gpio.DutyMax {
+		s.halt()
+		return s.PinOut.Out(duty == gpio.DutyMax)
+	}
+	s.mu.Lock()
+	s.high = duty.PulseWidth(f)
+	s.period = period
+	s.mu.Unlock()
+	if s.stop == nil {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.run(s.stop, s.done)
+	}
+	return nil
+}
+
+//
+
+var errSoftPWMNoIn = errors.New("gpioutil: pin doesn't implement gpio.PinIn")
+
+// halt stops the goroutine, if running.
+//
+// s.ctl must be held.
+func (s *softPWM) halt() {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+		s.stop = nil
+		s.done = nil
+	}
+}
+
+// run generates the signal until stop is closed.
+func (s *softPWM) run(stop, done chan struct{}) {
+	defer close(done)
+	next := s.clock.Now()
+	for {
+		s.mu.Lock()
+		high, period := s.high, s.period
+		s.mu.Unlock()
+		_ = s.PinOut.Out(gpio.High)
+		if !s.sleepUntil(next.Add(high), stop) {
+			return
+		}
+		_ = s.PinOut.Out(gpio.Low)
+		next = next.Add(period)
+		if !s.sleepUntil(next, stop) {
+			return
+		}
+		if now := s.clock.Now(); now.Sub(next) >= period {
+			// Skip the missed periods instead of generating a burst.
+			next = now
+		}
+	}
+}
+
+// sleepUntil sleeps until t. It returns false if stop was closed.
+func (s *softPWM) sleepUntil(t time.Time, stop chan struct{}) bool {
+	d := t.Sub(s.clock.Now())
+	if d <= 0 {
+		select {
+		case <-stop:
+			return false
+		default:
+			return true
+		}
+	}
+	timer := s.clock.NewTimer(d)
+	select {
+	case <-timer.Chan():
+		return true
+	case <-stop:
+		timer.Stop()
+		return false
+	}
+}
+
+var _ gpio.PinIO = &softPWM{}
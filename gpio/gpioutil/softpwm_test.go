@@ -0,0 +1,175 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestSoftPWM(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	p := &recordPin{clock: clock, start: clock.Now()}
+	s := softPWMWithClock(p, clock)
+	if err := s.PWM(gpio.DutyMax/4, 100*physic.Hertz); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []time.Duration{
+		2500 * time.Microsecond,
+		7500 * time.Microsecond,
+		// The goroutine wakes up late; the low phase is shortened.
+		3 * time.Millisecond,
+		7 * time.Millisecond,
+		// Late by more than a period; the missed periods are skipped.
+		2500 * time.Microsecond,
+		25 * time.Millisecond,
+		2500 * time.Microsecond,
+	} {
+		clock.BlockUntil(1)
+		clock.Advance(d)
+	}
+	clock.BlockUntil(1)
+	if err := s.Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	expected := []pwmEdge{
+		{0, gpio.High},
+		{2500 * time.Microsecond, gpio.Low},
+		{10 * time.Millisecond, gpio.High},
+		{13 * time.Millisecond, gpio.Low},
+		{20 * time.Millisecond, gpio.High},
+		{22500 * time.Microsecond, gpio.Low},
+		{47500 * time.Microsecond, gpio.High},
+		{50 * time.Millisecond, gpio.Low},
+		{50 * time.Millisecond, gpio.Low},
+	}
+	if got := p.get(); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("%v != %v", expected, got)
+	}
+}
+
+func TestSoftPWM_Update(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	p := &recordPin{clock: clock, start: clock.Now()}
+	s := softPWMWithClock(p, clock)
+	if err := s.PWM(gpio.DutyHalf, 0); err != nil {
+		t.Fatal(err)
+	}
+	clock.BlockUntil(1)
+	// Takes effect at the next period.
+	if err := s.PWM(gpio.DutyMax/10, 50*physic.Hertz); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []time.Duration{5 * time.Millisecond, 5 * time.Millisecond, 2 * time.Millisecond, 18 * time.Millisecond} {
+		clock.Advance(d)
+		clock.BlockUntil(1)
+	}
+	if err := s.PWM(gpio.DutyMax, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PWM(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	expected := []pwmEdge{
+		{0, gpio.High},
+		{5 * time.Millisecond, gpio.Low},
+		{10 * time.Millisecond, gpio.High},
+		{12 * time.Millisecond, gpio.Low},
+		{30 * time.Millisecond, gpio.High},
+		{30 * time.Millisecond, gpio.High},
+		{30 * time.Millisecond, gpio.Low},
+	}
+	if got := p.get(); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("%v != %v", expected, got)
+	}
+}
+
+func TestSoftPWM_Halt(t *testing.T) {
+	p := &gpiotest.Pin{N: "GPIO1"}
+	s := SoftPWM(p)
+	if err := s.PWM(gpio.DutyHalf, physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := s.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.In(gpio.PullDown, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if s.Read() != gpio.Low || s.Pull() != gpio.PullDown || s.DefaultPull() != gpio.PullDown {
+		t.Fatal("unexpected state")
+	}
+	if err := s.PWM(gpio.DutyHalf, physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if s.Read() != gpio.High {
+		t.Fatal("unexpected level")
+	}
+}
+
+func TestSoftPWM_Err(t *testing.T) {
+	s := SoftPWM(&outPin{})
+	if s.PWM(-1, 0) == nil {
+		t.Fatal("expected error")
+	}
+	if s.PWM(gpio.DutyHalf, -physic.Hertz) == nil {
+		t.Fatal("expected error")
+	}
+	if s.PWM(gpio.DutyHalf, 10*physic.GigaHertz) == nil {
+		t.Fatal("expected error")
+	}
+	if s.In(gpio.PullNoChange, gpio.NoEdge) == nil {
+		t.Fatal("expected error")
+	}
+	if s.Read() != gpio.Low || s.WaitForEdge(0) || s.Pull() != gpio.PullNoChange || s.DefaultPull() != gpio.PullNoChange {
+		t.Fatal("unexpected input")
+	}
+}
+
+//
+
+type pwmEdge struct {
+	t time.Duration
+	l gpio.Level
+}
+
+// recordPin records the levels set with Out() with their timestamp.
+type recordPin struct {
+	gpiotest.Pin
+	clock clockwork.Clock
+	start time.Time
+
+	mu    sync.Mutex
+	edges []pwmEdge
+}
+
+func (r *recordPin) Out(l gpio.Level) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.edges = append(r.edges, pwmEdge{r.clock.Since(r.start), l})
+	return nil
+}
+
+func (r *recordPin) get() []pwmEdge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]pwmEdge(nil), r.edges...)
+}
+
+// outPin implements gpio.PinOut only.
+type outPin struct {
+	gpio.PinOut
+}
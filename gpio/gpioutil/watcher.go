@@ -0,0 +1,191 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+)
+
+// Watcher waits for edges on multiple input pins at once.
+//
+// The edge detection must have been enabled on each pin with In() before
+// calling NewWatcher(). The edges of a pin that were not yet returned by
+// Wait() are coalesced, like with gpio.PinIn.WaitForEdge().
+type Watcher struct {
+	// Immutable.
+	pins []gpio.PinIn
+	// notify is signaled when an edge is queued.
+	notify chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	// Mutable.
+	mu sync.Mutex
+	// queue are the indexes of the pins with a pending edge, in the order of
+	// detection.
+	queue []int
+	// edges are the pending edges by pin index, NoEdge if none.
+	edges  []gpio.Edge
+	closed bool
+}
+
+// NewWatcher returns a Watcher monitoring pins.
+//
+// A goroutine is started per pin; they are stopped by Close().
+func NewWatcher(pins ...gpio.PinIn) *Watcher {
+	w := &Watcher{
+		pins:   pins,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		edges:  make([]gpio.Edge, len(pins)),
+	}
+	w.wg.Add(len(pins))
+	for i := range pins {
+		go w.watch(i)
+	}
+	return w
+}
+
+// Pins returns the pins monitored.
+func (w *Watcher) Pins() []gpio.PinIn {
+	return w.pins
+}
+
+// Wait waits for an edge on any of the pins and returns the index of the pin
+// in Pins() and the edge, gpio.RisingEdge or gpio.FallingEdge, as deduced from
+// the level read right after the edge.
+//
+// When edges are pending on multiple pins, they are returned in the order
+// they were detected.
+//
+// Returns ctx.Err() if ctx is done first.
+func (w *Watcher) Wait(ctx context.Context) (int, gpio.Edge, error) {
+	for {
+		w.mu.Lock()
+		if w.closed {
+			w.mu.Unlock()
+			return -1, gpio.NoEdge, errWatcherClosed
+		}
+		if len(w.queue) != 0 {
+			i := w.queue[0]
+			w.queue = w.queue[1:]
+			e := w.edges[i]
+			w.edges[i] = gpio.NoEdge
+			w.mu.Unlock()
+			return i, e, nil
+		}
+		w.mu.Unlock()
+		select {
+		case <-w.notify:
+		case <-w.done:
+		case <-ctx.Done():
+			return -1, gpio.NoEdge, ctx.Err()
+		}
+	}
+}
+
+// WaitForEdge is like Wait() with a timeout instead of a context.
+//
+// It returns conn.ErrTimeout when the timeout occurs. Like Wait(), it returns
+// an index in Pins(); NewGroup() maps it to the GPIO number that
+// gpio.Group.WaitForEdge() returns.
+//
+// Specify -1 to effectively disable timeout.
+func (w *Watcher) WaitForEdge(timeout time.Duration) (int, gpio.Edge, error) {
+	ctx := context.Background()
+	if timeout >= 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	i, e, err := w.Wait(ctx)
+	if err == context.DeadlineExceeded {
+		err = conn.ErrTimeout
+	}
+	return i, e, err
+}
+
+// Close stops monitoring the pins.
+//
+// It unblocks Wait() and waits for the goroutines to exit, which takes up to
+// 100ms. The pins are left as is. Calling it again is a no-op.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	w.mu.Unlock()
+	w.wg.Wait()
+	return nil
+}
+
+//
+
+// pollTimeout is the timeout of each call to WaitForEdge(), which bounds how
+// long Close() takes.
+const pollTimeout = 100 * time.Millisecond
+
+var errWatcherClosed = errors.New("gpioutil: watcher closed")
+
+// backOff waits for the rest of pollTimeout since start, so a pin whose
+// WaitForEdge() returns false immediately, e.g. because edge detection is not
+// enabled or because it was halted, doesn't spin.
+//
+// It returns false if done is closed first.
+func backOff(start time.Time, done <-chan struct{}) bool {
+	d := pollTimeout - time.Since(start)
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// watch loops on the edges of the pin at index i.
+func (w *Watcher) watch(i int) {
+	defer w.wg.Done()
+	p := w.pins[i]
+	for {
+		select {
+		case <-w.done:
+			return
+		default:
+		}
+		start := time.Now()
+		if !p.WaitForEdge(pollTimeout) {
+			if !backOff(start, w.done) {
+				return
+			}
+			continue
+		}
+		e := gpio.FallingEdge
+		if p.Read() == gpio.High {
+			e = gpio.RisingEdge
+		}
+		w.mu.Lock()
+		if w.edges[i] == gpio.NoEdge {
+			w.queue = append(w.queue, i)
+		}
+		w.edges[i] = e
+		w.mu.Unlock()
+		select {
+		case w.notify <- struct{}{}:
+		default:
+		}
+	}
+}
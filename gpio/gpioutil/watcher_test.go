@@ -0,0 +1,130 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gpioutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+)
+
+func TestWatcher(t *testing.T) {
+	pins := newWatchedPins(t, 3)
+	w := NewWatcher(pins[0], pins[1], pins[2])
+	defer w.Close()
+	if len(w.Pins()) != 3 {
+		t.Fatal(w.Pins())
+	}
+	pins[1].EdgesChan <- gpio.High
+	if i, e, err := w.Wait(context.Background()); i != 1 || e != gpio.RisingEdge || err != nil {
+		t.Fatal(i, e, err)
+	}
+	pins[2].EdgesChan <- gpio.High
+	waitPending(t, w, 2, gpio.RisingEdge)
+	pins[0].EdgesChan <- gpio.High
+	// Coalesced with the previous edge.
+	pins[2].EdgesChan <- gpio.Low
+	waitPending(t, w, 0, gpio.RisingEdge)
+	waitPending(t, w, 2, gpio.FallingEdge)
+	if i, e, err := w.Wait(context.Background()); i != 2 || e != gpio.FallingEdge || err != nil {
+		t.Fatal(i, e, err)
+	}
+	if i, e, err := w.WaitForEdge(-1); i != 0 || e != gpio.RisingEdge || err != nil {
+		t.Fatal(i, e, err)
+	}
+}
+
+func TestWatcher_Context(t *testing.T) {
+	pins := newWatchedPins(t, 1)
+	w := NewWatcher(pins[0])
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if i, _, err := w.Wait(ctx); i != -1 || err != context.Canceled {
+		t.Fatal(i, err)
+	}
+	if i, _, err := w.WaitForEdge(time.Millisecond); i != -1 || !errors.Is(err, conn.ErrTimeout) {
+		t.Fatal(i, err)
+	}
+}
+
+func TestWatcher_Close(t *testing.T) {
+	pins := newWatchedPins(t, 2)
+	w := NewWatcher(pins[0], pins[1])
+	done := make(chan error)
+	go func() {
+		_, _, err := w.Wait(context.Background())
+		done <- err
+	}()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != errWatcherClosed {
+		t.Fatal(err)
+	}
+	if _, _, err := w.Wait(context.Background()); err != errWatcherClosed {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_noEdges(t *testing.T) {
+	// Edge detection is not enabled so WaitForEdge() returns immediately.
+	p := &countingPin{PinIn: &gpiotest.Pin{N: "GPIO"}}
+	w := NewWatcher(p)
+	time.Sleep(pollTimeout + pollTimeout/2)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := p.n.Load(); n > 3 {
+		t.Fatalf("WaitForEdge() called %d times", n)
+	}
+}
+
+//
+
+// countingPin counts the calls to WaitForEdge().
+type countingPin struct {
+	gpio.PinIn
+	n atomic.Int32
+}
+
+func (c *countingPin) WaitForEdge(timeout time.Duration) bool {
+	c.n.Add(1)
+	return c.PinIn.WaitForEdge(0)
+}
+
+func newWatchedPins(t *testing.T, n int) []*gpiotest.Pin {
+	var pins []*gpiotest.Pin
+	for i := 0; i < n; i++ {
+		p := &gpiotest.Pin{N: "GPIO", Num: i, EdgesChan: make(chan gpio.Level)}
+		if err := p.In(gpio.PullNoChange, gpio.BothEdges); err != nil {
+			t.Fatal(err)
+		}
+		pins = append(pins, p)
+	}
+	return pins
+}
+
+// waitPending waits for the pin at index i to have the edge e pending.
+func waitPending(t *testing.T, w *Watcher, i int, e gpio.Edge) {
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(time.Millisecond) {
+		w.mu.Lock()
+		got := w.edges[i]
+		w.mu.Unlock()
+		if got == e {
+			return
+		}
+	}
+	t.Fatalf("expected %s pending on pin #%d", e, i)
+}
@@ -19,6 +19,7 @@
 package i2c
 
 import (
+	"context"
 	"errors"
 	"io"
 	"strconv"
@@ -49,6 +50,17 @@ type Bus interface {
 	SetSpeed(f physic.Frequency) error
 }
 
+// BusContext is an I²C bus that supports cancellation and deadlines.
+//
+// It is expected but not required that an implementer of Bus also implement
+// BusContext. Dev.TxContext() falls back to conn.DoContext() otherwise.
+type BusContext interface {
+	Bus
+	// TxContext does a transaction at the specified device address like Tx,
+	// except that it returns ctx.Err() as soon as ctx is done.
+	TxContext(ctx context.Context, addr uint16, w, r []byte) error
+}
+
 // BusCloser is an I²C bus that can be closed.
 //
 // This interface is meant to be handled by the application and not the device
@@ -72,9 +84,11 @@ type Pins interface {
 
 // Dev is a device on a I²C bus.
 //
-// It implements conn.Conn.
+// It implements conn.Conn and conn.ConnContext.
 //
 // It saves from repeatedly specifying the device address.
+//
+// All the methods return the errors of the bus as-is.
 type Dev struct {
 	Bus  Bus
 	Addr uint16
@@ -95,6 +109,18 @@ func (d *Dev) Tx(w, r []byte) error {
 	return d.Bus.Tx(d.Addr, w, r)
 }
 
+// TxContext does a transaction by adding the device's address to each
+// command, returning ctx.Err() as soon as ctx is done.
+//
+// It's a wrapper for BusContext.TxContext() if Bus implements it. Otherwise
+// Bus.Tx() is run via conn.DoContext().
+func (d *Dev) TxContext(ctx context.Context, w, r []byte) error {
+	if b, ok := d.Bus.(BusContext); ok {
+		return b.TxContext(ctx, d.Addr, w, r)
+	}
+	return conn.DoContext(ctx, w, r, d.Tx)
+}
+
 // Write writes to the I²C bus without reading, implementing io.Writer.
 //
 // It's a wrapper for Tx()
@@ -133,3 +159,4 @@ func (a Addr) String() string {
 var errI2CSetError = errors.New("invalid i2c address")
 
 var _ conn.Conn = &Dev{}
+var _ conn.ConnContext = &Dev{}
@@ -6,6 +6,7 @@ package i2c
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -59,6 +60,18 @@ func TestDevWrite(t *testing.T) {
 	}
 }
 
+func TestDevTxContext_Classified(t *testing.T) {
+	b := &fakeBus{err: conn.ErrAddrNACK}
+	d := Dev{b, 12}
+	// Tx() and TxContext() both return the error as-is.
+	if err := d.TxContext(context.Background(), []byte{1}, nil); err != conn.ErrAddrNACK {
+		t.Fatal(err)
+	}
+	if err := d.Tx([]byte{1}, nil); err != conn.ErrAddrNACK {
+		t.Fatal(err)
+	}
+}
+
 func TestDevWriteErr(t *testing.T) {
 	exErr := errors.New("yes")
 	b := &fakeBus{err: exErr}
@@ -145,3 +158,49 @@ func TestAddr_String(t *testing.T) {
 		}
 	}
 }
+
+func TestDevTxContext(t *testing.T) {
+	b := &fakeBus{r: []byte{1, 2}}
+	d := Dev{b, 12}
+	r := make([]byte, 2)
+	if err := d.TxContext(context.Background(), []byte{3}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{1, 2}) || !bytes.Equal(b.w, []byte{3}) || b.addr != 12 {
+		t.Fatal(r, b.w, b.addr)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.TxContext(ctx, []byte{4}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.w, []byte{3}) {
+		t.Fatal(b.w)
+	}
+}
+
+func TestDevTxContext_BusContext(t *testing.T) {
+	b := &fakeBusContext{}
+	d := Dev{b, 12}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.TxContext(ctx, []byte{4}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if !b.called {
+		t.Fatal("expected BusContext.TxContext to be used")
+	}
+}
+
+type fakeBusContext struct {
+	fakeBus
+	called bool
+}
+
+func (f *fakeBusContext) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	f.called = true
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.Tx(addr, w, r)
+}
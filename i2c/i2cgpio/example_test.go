@@ -0,0 +1,53 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2cgpio_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/driver/driverreg"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2cgpio"
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/physic"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	// TODO: Use host.Init(). It is not used in this example to prevent circular
+	// go package import.
+	if _, err := driverreg.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Make the bus available by name, like the hardware ones.
+	opener := func() (i2c.BusCloser, error) {
+		scl := gpioreg.ByName("GPIO5")
+		sda := gpioreg.ByName("GPIO6")
+		if scl == nil || sda == nil {
+			return nil, fmt.Errorf("failed to find the pins")
+		}
+		return i2cgpio.New(scl, sda, 100*physic.KiloHertz)
+	}
+	if err := i2creg.Register("I2CGPIO", nil, -1, opener); err != nil {
+		log.Fatal(err)
+	}
+
+	b, err := i2creg.Open("I2CGPIO")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer b.Close()
+
+	// Read the chip ID of a BME280.
+	d := &i2c.Dev{Addr: 0x76, Bus: b}
+	id := make([]byte, 1)
+	if err := d.Tx([]byte{0xD0}, id); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("0x%02x\n", id[0])
+}
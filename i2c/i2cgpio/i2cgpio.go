@@ -0,0 +1,391 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package i2cgpio implements an I²C bus master in software over two GPIO
+// pins.
+//
+// It is useful to attach devices to arbitrary pins when the hardware I²C
+// controllers are all in use. SetSpeed() sets the half period waited between
+// the changes of SCL; the GPIO accesses and the devices stretching the clock
+// make the actual bus slower.
+//
+// SCL and SDA are never driven High: a line is pulled Low with Out(Low) and
+// left to the pull-up with In(PullUp). Fit pull-up resistors sized for the
+// bus capacitance, usually a few kΩ.
+//
+// Register a Bus in i2creg to make it available by name; see the example.
+package i2cgpio
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/internal/bitbang"
+	"periph.io/x/conn/v3/physic"
+)
+
+// New returns an I²C bus over the pins scl and sda.
+//
+// f is the bus speed; 0 selects 100kHz.
+func New(scl, sda gpio.PinIO, f physic.Frequency) (*Bus, error) {
+	if f == 0 {
+		f = 100 * physic.KiloHertz
+	}
+	b := &Bus{scl: scl, sda: sda}
+	if err := b.SetSpeed(f); err != nil {
+		return nil, err
+	}
+	if err := b.release(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Bus is an I²C bus master bit-banged over two GPIO pins.
+//
+// It supports 7 and 10 bit addresses, repeated start and clock stretching.
+// Addresses above 0x7F are sent as 10 bit addresses.
+//
+// The failures are reported with the errors of package conn:
+//   - conn.ErrAddrNACK when the address is not acknowledged;
+//   - conn.ErrDataNACK when a byte written is not acknowledged;
+//   - conn.ErrArbitrationLost when another master drives SDA;
+//   - conn.ErrTimeout when a device stretches the clock for more than 25ms;
+//   - conn.ErrBusBusy when a line is held Low before the transaction.
+type Bus struct {
+	// Immutable.
+	scl gpio.PinIO
+	sda gpio.PinIO
+
+	mu     sync.Mutex
+	half   time.Duration
+	closed bool
+}
+
+func (b *Bus) String() string {
+	return "i2cgpio(" + b.scl.String() + ", " + b.sda.String() + ")"
+}
+
+// Tx implements i2c.Bus.
+func (b *Bus) Tx(addr uint16, w, r []byte) error {
+	return b.TxContext(context.Background(), addr, w, r)
+}
+
+// TxContext implements i2c.BusContext.
+//
+// ctx is checked between each byte. When it is done, the transaction is
+// terminated with a stop condition and ctx.Err() is returned. While reading,
+// one more byte is read without acknowledging it first, so the device
+// releases SDA.
+func (b *Bus) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	if addr > 0x3FF {
+		return errors.New("i2cgpio: invalid address 0x" + strconv.FormatUint(uint64(addr), 16))
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := b.start(); err != nil {
+		_ = b.release()
+		return err
+	}
+	err := b.tx(ctx, addr, w, r)
+	if err == conn.ErrArbitrationLost {
+		// The bus belongs to the other master now.
+		_ = b.release()
+		return err
+	}
+	if err2 := b.stop(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// SetSpeed implements i2c.Bus.
+func (b *Bus) SetSpeed(f physic.Frequency) error {
+	if f <= 0 {
+		return errors.New("i2cgpio: invalid speed " + f.String())
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.half = f.Period() / 2
+	return nil
+}
+
+// Close implements i2c.BusCloser.
+//
+// It releases both lines.
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errClosed
+	}
+	b.closed = true
+	return b.release()
+}
+
+// SCL implements i2c.Pins.
+func (b *Bus) SCL() gpio.PinIO {
+	return b.scl
+}
+
+// SDA implements i2c.Pins.
+func (b *Bus) SDA() gpio.PinIO {
+	return b.sda
+}
+
+//
+
+// maxStretch is the longest a device can stretch the clock, as the SMBus
+// timeout.
+const maxStretch = 25 * time.Millisecond
+
+var errClosed = errors.New("i2cgpio: bus closed")
+
+// tx runs the transaction between the start and stop conditions.
+func (b *Bus) tx(ctx context.Context, addr uint16, w, r []byte) error {
+	ten := addr > 0x7F
+	// A 10 bit read starts with a write of the full address.
+	if len(w) != 0 || len(r) == 0 || ten {
+		if ten {
+			if err := b.writeAddr(0xF0 | byte(addr>>7)&6); err != nil {
+				return err
+			}
+			if err := b.writeAddr(byte(addr)); err != nil {
+				return err
+			}
+		} else if err := b.writeAddr(byte(addr << 1)); err != nil {
+			return err
+		}
+		for _, c := range w {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if ack, err := b.writeByte(c); err != nil {
+				return err
+			} else if !ack {
+				return conn.ErrDataNACK
+			}
+		}
+		if len(r) == 0 {
+			return nil
+		}
+		if err := b.restart(); err != nil {
+			return err
+		}
+	}
+	// Only the high bits are resent for a 10 bit read.
+	a := byte(addr<<1) | 1
+	if ten {
+		a = 0xF1 | byte(addr>>7)&6
+	}
+	if err := b.writeAddr(a); err != nil {
+		return err
+	}
+	for i := range r {
+		if err := ctx.Err(); err != nil {
+			// The address or the previous byte was acknowledged, so the device
+			// is sending a byte. Read it without acknowledging it so the device
+			// releases SDA for the stop condition.
+			if _, err2 := b.readByte(false); err2 != nil {
+				return err2
+			}
+			return err
+		}
+		// The last byte is not acknowledged, to tell the device to stop.
+		c, err := b.readByte(i != len(r)-1)
+		if err != nil {
+			return err
+		}
+		r[i] = c
+	}
+	return nil
+}
+
+// writeAddr writes an address byte.
+func (b *Bus) writeAddr(c byte) error {
+	ack, err := b.writeByte(c)
+	if err == nil && !ack {
+		err = conn.ErrAddrNACK
+	}
+	return err
+}
+
+// writeByte writes c MSB first and returns true if it was acknowledged.
+func (b *Bus) writeByte(c byte) (bool, error) {
+	for i := 7; i >= 0; i-- {
+		if err := b.writeBit(c&(1<<uint(i)) != 0); err != nil {
+			return false, err
+		}
+	}
+	nack, err := b.readBit()
+	return !nack, err
+}
+
+// readByte reads a byte MSB first and acknowledges it if ack is true.
+func (b *Bus) readByte(ack bool) (byte, error) {
+	var c byte
+	for i := 0; i < 8; i++ {
+		bit, err := b.readBit()
+		if err != nil {
+			return 0, err
+		}
+		c <<= 1
+		if bit {
+			c |= 1
+		}
+	}
+	return c, b.writeBit(!ack)
+}
+
+// writeBit sends a bit. SCL is Low before and after.
+func (b *Bus) writeBit(bit bool) error {
+	if err := b.setSDA(bit); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	if err := b.releaseSCL(); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	if bit && b.sda.Read() == gpio.Low {
+		return conn.ErrArbitrationLost
+	}
+	return b.scl.Out(gpio.Low)
+}
+
+// readBit reads a bit. SCL is Low before and after.
+func (b *Bus) readBit() (bool, error) {
+	if err := b.setSDA(true); err != nil {
+		return false, err
+	}
+	bitbang.Delay(b.half)
+	if err := b.releaseSCL(); err != nil {
+		return false, err
+	}
+	bitbang.Delay(b.half)
+	bit := b.sda.Read() == gpio.High
+	return bit, b.scl.Out(gpio.Low)
+}
+
+// start sends a start condition. Both lines must be released before; SCL is
+// Low after.
+//
+// If a device holds SDA Low, for example because a previous transaction was
+// interrupted, up to 9 clock pulses are sent to let it complete.
+func (b *Bus) start() error {
+	if err := b.release(); err != nil {
+		return err
+	}
+	if err := b.releaseSCL(); err != nil {
+		if err == conn.ErrTimeout {
+			err = conn.ErrBusBusy
+		}
+		return err
+	}
+	for i := 0; b.sda.Read() == gpio.Low; i++ {
+		if i == 9 {
+			return conn.ErrBusBusy
+		}
+		if err := b.scl.Out(gpio.Low); err != nil {
+			return err
+		}
+		bitbang.Delay(b.half)
+		if err := b.releaseSCL(); err != nil {
+			return err
+		}
+		bitbang.Delay(b.half)
+	}
+	if err := b.sda.Out(gpio.Low); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	return b.scl.Out(gpio.Low)
+}
+
+// restart sends a repeated start condition. SCL is Low before and after.
+func (b *Bus) restart() error {
+	if err := b.setSDA(true); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	if err := b.releaseSCL(); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	if err := b.sda.Out(gpio.Low); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	return b.scl.Out(gpio.Low)
+}
+
+// stop sends a stop condition. SCL is Low before; both lines are released
+// after.
+func (b *Bus) stop() error {
+	if err := b.sda.Out(gpio.Low); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	if err := b.releaseSCL(); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	if err := b.setSDA(true); err != nil {
+		return err
+	}
+	bitbang.Delay(b.half)
+	return nil
+}
+
+// release releases both lines.
+func (b *Bus) release() error {
+	if err := b.scl.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		return err
+	}
+	return b.sda.In(gpio.PullUp, gpio.NoEdge)
+}
+
+// setSDA releases SDA if high is true, otherwise pulls it Low.
+func (b *Bus) setSDA(high bool) error {
+	if high {
+		return b.sda.In(gpio.PullUp, gpio.NoEdge)
+	}
+	return b.sda.Out(gpio.Low)
+}
+
+// releaseSCL releases SCL and waits for devices stretching the clock.
+func (b *Bus) releaseSCL() error {
+	if err := b.scl.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		return err
+	}
+	if b.scl.Read() == gpio.High {
+		return nil
+	}
+	for start := time.Now(); b.scl.Read() == gpio.Low; {
+		if time.Since(start) > maxStretch {
+			return conn.ErrTimeout
+		}
+		// Stretching can last milliseconds; let other goroutines run.
+		runtime.Gosched()
+	}
+	return nil
+}
+
+var _ i2c.Bus = &Bus{}
+var _ i2c.BusCloser = &Bus{}
+var _ i2c.BusContext = &Bus{}
+var _ i2c.Pins = &Bus{}
@@ -0,0 +1,439 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2cgpio
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestBus(t *testing.T) {
+	b, s := newBus(t, 0x76)
+	s.dev.Set(0xD0, 0x60)
+	r := make([]byte, 1)
+	if err := b.Tx(0x76, []byte{0xD0}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 0x60 {
+		t.Fatalf("0x%02x", r[0])
+	}
+	if err := b.Tx(0x76, []byte{0x10, 1, 2, 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.dev.Get(0x11); v != 2 {
+		t.Fatal(v)
+	}
+	// Reading continues from the last register written.
+	if err := b.Tx(0x76, []byte{0x10}, nil); err != nil {
+		t.Fatal(err)
+	}
+	r = make([]byte, 3)
+	if err := b.Tx(0x76, nil, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 1 || r[1] != 2 || r[2] != 3 {
+		t.Fatal(r)
+	}
+	if s.starts != 5 || s.stops != 4 {
+		t.Fatalf("%d starts, %d stops", s.starts, s.stops)
+	}
+}
+
+func TestBus_10Bits(t *testing.T) {
+	b, s := newBus(t, 0x2A5)
+	if err := b.Tx(0x2A5, []byte{0x20, 0x42}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.dev.Get(0x20); v != 0x42 {
+		t.Fatal(v)
+	}
+	r := make([]byte, 1)
+	if err := b.Tx(0x2A5, []byte{0x20}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 0x42 {
+		t.Fatal(r)
+	}
+	if err := b.Tx(0x2A5, nil, r); err != nil {
+		t.Fatal(err)
+	}
+	// Only the two high bits match.
+	if err := b.Tx(0x2A4, nil, nil); !errors.Is(err, conn.ErrAddrNACK) {
+		t.Fatal(err)
+	}
+	if b.Tx(0x400, nil, nil) == nil {
+		t.Fatal("invalid address")
+	}
+}
+
+func TestBus_NACK(t *testing.T) {
+	b, s := newBus(t, 0x76)
+	if err := b.Tx(0x50, []byte{1}, nil); err != conn.ErrAddrNACK {
+		t.Fatal(err)
+	}
+	s.maxWrite = 2
+	if err := b.Tx(0x76, []byte{1, 2, 3}, nil); err != conn.ErrDataNACK {
+		t.Fatal(err)
+	}
+	// The bus is usable after a failure.
+	if err := b.Tx(0x76, []byte{1, 2}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBus_Stretch(t *testing.T) {
+	b, s := newBus(t, 0x76)
+	s.stretch = 10
+	if err := b.Tx(0x76, []byte{0x10, 0xAA}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if s.stretched == 0 {
+		t.Fatal("clock was not stretched")
+	}
+	s.stretch = -1
+	if err := b.Tx(0x76, []byte{0x10}, nil); err != conn.ErrTimeout {
+		t.Fatal(err)
+	}
+}
+
+func TestBus_BusBusy(t *testing.T) {
+	b, s := newBus(t, 0x76)
+	other := s.sdaWire.Pin("other", 3)
+	if err := other.Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx(0x76, nil, nil); err != conn.ErrBusBusy {
+		t.Fatal(err)
+	}
+	if err := other.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx(0x76, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBus_ArbitrationLost(t *testing.T) {
+	b, s := newBus(t, 0x76)
+	// Another master starts a transaction to a lower address.
+	s.grab = s.sdaWire.Pin("other", 3)
+	if err := b.Tx(0x76, nil, nil); err != conn.ErrArbitrationLost {
+		t.Fatal(err)
+	}
+	if err := s.grab.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	s.grab = nil
+	if err := b.Tx(0x76, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBus_Context(t *testing.T) {
+	b, _ := newBus(t, 0x76)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.TxContext(ctx, 0x76, []byte{1}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	i2ctest.TestBus(t, b, 0x76)
+}
+
+func TestBus_Context_read(t *testing.T) {
+	b, s := newBus(t, 0x76)
+	// Canceled after the first byte read.
+	ctx := &cancelAfter{Context: context.Background(), n: 2}
+	if err := b.TxContext(ctx, 0x76, nil, make([]byte, 3)); err != context.Canceled {
+		t.Fatal(err)
+	}
+	// The device released SDA so the stop condition was seen.
+	if s.stops != 1 || s.state != idle {
+		t.Fatal(s.stops, s.state)
+	}
+	if s.sda.Read() != gpio.High {
+		t.Fatal("SDA must be released")
+	}
+}
+
+func TestBus_Misc(t *testing.T) {
+	b, s := newBus(t, 0x76)
+	if s := b.String(); s != "i2cgpio(SCL(1), SDA(2))" {
+		t.Fatal(s)
+	}
+	if b.SCL() == nil || b.SDA() == nil {
+		t.Fatal("expected pins")
+	}
+	if b.SetSpeed(0) == nil {
+		t.Fatal("invalid speed")
+	}
+	if err := b.SetSpeed(400 * physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Close() == nil {
+		t.Fatal("second Close() must fail")
+	}
+	if b.Tx(0x76, nil, nil) == nil {
+		t.Fatal("closed")
+	}
+	if s.scl.Read() != gpio.High || s.sda.Read() != gpio.High {
+		t.Fatal("lines must be released")
+	}
+	if _, err := New(&gpiotest.Pin{N: "SCL"}, &gpiotest.Pin{N: "SDA"}, -1); err == nil {
+		t.Fatal("invalid speed")
+	}
+}
+
+//
+
+// newBus returns a bus with a simulated device at addr.
+func newBus(t *testing.T, addr uint16) (*Bus, *slave) {
+	s := &slave{addr: addr, dev: &conntest.RegisterDevice{}}
+	s.sclWire.OnChange = s.onSCL
+	s.sdaWire.OnChange = s.onSDA
+	s.scl = s.sclWire.Pin("SCL", 1)
+	s.sda = s.sdaWire.Pin("SDA", 2)
+	s.devSCL = s.sclWire.Pin("devSCL", 0)
+	s.devSDA = s.sdaWire.Pin("devSDA", 0)
+	b, err := New(&stretchPin{WirePin: s.scl, s: s}, s.sda, physic.GigaHertz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, s
+}
+
+const (
+	idle = iota
+	addrByte
+	addr10Byte
+	writing
+	reading
+)
+
+// slave simulates an I²C device, driven by the changes of the lines.
+type slave struct {
+	addr uint16
+	dev  *conntest.RegisterDevice
+	// maxWrite is the number of data bytes acknowledged per transaction, if
+	// not 0.
+	maxWrite int
+	// stretch is the number of reads of SCL by the master during which the
+	// clock is stretched after each byte, -1 for forever.
+	stretch int
+	// grab, if set, is pulled Low on the start condition, like another
+	// master.
+	grab *gpiotest.WirePin
+
+	sclWire, sdaWire gpiotest.Wire
+	scl, sda         *gpiotest.WirePin
+	devSCL, devSDA   *gpiotest.WirePin
+
+	mu        sync.Mutex
+	state     int
+	bits      int
+	cur       byte
+	nack      bool
+	matched10 bool
+	written   int
+	pending   []byte
+	hold      int
+	stretched int
+	starts    int
+	stops     int
+}
+
+func (s *slave) onSDA(l gpio.Level) {
+	if s.sclWire.Read() == gpio.Low {
+		// Data change.
+		return
+	}
+	s.flush()
+	if l == gpio.Low {
+		s.starts++
+		if s.grab != nil {
+			_ = s.grab.Out(gpio.Low)
+		}
+		s.state = addrByte
+		s.written = 0
+	} else {
+		s.stops++
+		s.state = idle
+		s.matched10 = false
+	}
+	s.bits = 0
+}
+
+func (s *slave) onSCL(l gpio.Level) {
+	if l == gpio.High {
+		s.bits++
+		switch {
+		case s.state == idle:
+		case s.state == reading && s.bits == 9:
+			s.nack = s.sdaWire.Read() == gpio.High
+		case s.state != reading && s.bits <= 8:
+			s.cur = s.cur<<1 | byte(boolToBit(s.sdaWire.Read() == gpio.High))
+		}
+		return
+	}
+	switch s.state {
+	case idle:
+		_ = s.devSDA.In(gpio.PullUp, gpio.NoEdge)
+	case reading:
+		if s.bits == 9 {
+			if s.nack {
+				s.state = idle
+				_ = s.devSDA.In(gpio.PullUp, gpio.NoEdge)
+				return
+			}
+			s.bits = 0
+		}
+		if s.bits == 0 {
+			s.flush()
+			b := make([]byte, 1)
+			_ = s.dev.Tx(nil, b)
+			s.cur = b[0]
+			s.doStretch()
+		}
+		if s.bits < 8 {
+			_ = s.devSDA.Out(gpio.Level(s.cur&(0x80>>uint(s.bits)) != 0))
+		} else {
+			_ = s.devSDA.In(gpio.PullUp, gpio.NoEdge)
+		}
+	default:
+		if s.bits == 8 {
+			if s.receive(s.cur) {
+				_ = s.devSDA.Out(gpio.Low)
+			} else {
+				s.state = idle
+			}
+		} else if s.bits == 9 {
+			// The address for a read switches to reading on the 9th bit.
+			_ = s.devSDA.In(gpio.PullUp, gpio.NoEdge)
+			s.bits = 0
+			s.doStretch()
+		}
+	}
+}
+
+// receive handles a byte written by the master and returns true to
+// acknowledge it.
+func (s *slave) receive(c byte) bool {
+	switch s.state {
+	case addrByte:
+		if c>>3 == 0x1E {
+			if s.addr <= 0x7F || byte(s.addr>>7)&6 != c&6 {
+				return false
+			}
+			if c&1 == 0 {
+				s.state = addr10Byte
+				return true
+			}
+			if !s.matched10 {
+				return false
+			}
+			s.state = reading
+			return true
+		}
+		if uint16(c>>1) != s.addr {
+			return false
+		}
+		if c&1 != 0 {
+			s.state = reading
+		} else {
+			s.state = writing
+		}
+		return true
+	case addr10Byte:
+		if c != byte(s.addr) {
+			return false
+		}
+		s.matched10 = true
+		s.state = writing
+		return true
+	default:
+		if s.maxWrite != 0 && s.written == s.maxWrite {
+			return false
+		}
+		s.written++
+		s.pending = append(s.pending, c)
+		return true
+	}
+}
+
+// flush writes the pending bytes to the device.
+func (s *slave) flush() {
+	if len(s.pending) != 0 {
+		_ = s.dev.Tx(s.pending, nil)
+		s.pending = nil
+	}
+}
+
+// doStretch holds SCL Low.
+func (s *slave) doStretch() {
+	if s.stretch != 0 {
+		s.mu.Lock()
+		s.hold = s.stretch
+		s.mu.Unlock()
+		_ = s.devSCL.Out(gpio.Low)
+	}
+}
+
+// tick is called on each read of SCL by the master.
+func (s *slave) tick() {
+	s.mu.Lock()
+	release := false
+	if s.hold > 0 {
+		s.stretched++
+		s.hold--
+		release = s.hold == 0
+	}
+	s.mu.Unlock()
+	if release {
+		_ = s.devSCL.In(gpio.PullUp, gpio.NoEdge)
+	}
+}
+
+// stretchPin lets the slave release SCL after a number of reads.
+type stretchPin struct {
+	*gpiotest.WirePin
+	s *slave
+}
+
+func (p *stretchPin) Read() gpio.Level {
+	p.s.tick()
+	return p.WirePin.Read()
+}
+
+// cancelAfter is a context canceled after n calls to Err().
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n == 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func boolToBit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
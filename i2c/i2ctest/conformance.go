@@ -0,0 +1,43 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2ctest
+
+import (
+	"context"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/i2c"
+)
+
+// TestBus verifies that b follows the contracts documented in package i2c.
+//
+// addr must be the address of a device that acknowledges an empty write. In
+// addition to the checks of conntest.TestConn on the device:
+//   - String() is not empty;
+//   - the device connection is half duplex;
+//   - i2c.BusContext.TxContext(), if implemented, returns exactly ctx.Err()
+//     when ctx is already done.
+//
+// It reports failures with t.Errorf().
+func TestBus(t testing.TB, b i2c.Bus, addr uint16) {
+	t.Helper()
+	if b.String() == "" {
+		t.Errorf("String() is empty")
+	}
+	d := &i2c.Dev{Bus: b, Addr: addr}
+	if x := d.Duplex(); x != conn.Half {
+		t.Errorf("Duplex() returned %s", x)
+	}
+	conntest.TestConn(t, d)
+	if bc, ok := b.(i2c.BusContext); ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := bc.TxContext(ctx, addr, nil, nil); err != context.Canceled {
+			t.Errorf("TxContext() with a canceled context returned %v; expected context.Canceled", err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2ctest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+)
+
+func TestTestBus(t *testing.T) {
+	TestBus(t, &DeviceBus{Devices: map[uint16]conn.Conn{0x76: &conntest.RegisterDevice{}}}, 0x76)
+
+	f := &fakeTB{}
+	TestBus(f, &DeviceBus{}, 0x76)
+	if !strings.Contains(f.String(), "conn: address not acknowledged") {
+		t.Fatal(f.String())
+	}
+}
+
+//
+
+// fakeTB records the errors reported by the conformance tests.
+type fakeTB struct {
+	testing.TB
+	errs []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errs = append(f.errs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) String() string {
+	return strings.Join(f.errs, "\n")
+}
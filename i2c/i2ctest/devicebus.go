@@ -0,0 +1,62 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2ctest
+
+import (
+	"context"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// DeviceBus implements i2c.Bus and dispatches the transactions to the
+// emulated device at each address, like a conntest.RegisterDevice.
+//
+// Transactions to an address without device fail with conn.ErrAddrNACK.
+type DeviceBus struct {
+	Devices map[uint16]conn.Conn
+}
+
+func (d *DeviceBus) String() string {
+	return "devicebus"
+}
+
+// Tx implements i2c.Bus.
+func (d *DeviceBus) Tx(addr uint16, w, r []byte) error {
+	return d.TxContext(context.Background(), addr, w, r)
+}
+
+// TxContext implements i2c.BusContext.
+func (d *DeviceBus) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	c := d.Devices[addr]
+	if c == nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return conn.ErrAddrNACK
+	}
+	return conn.WithContext(c).TxContext(ctx, w, r)
+}
+
+// SetSpeed implements i2c.Bus.
+func (d *DeviceBus) SetSpeed(f physic.Frequency) error {
+	return nil
+}
+
+// SCL implements i2c.Pins.
+func (d *DeviceBus) SCL() gpio.PinIO {
+	return gpio.INVALID
+}
+
+// SDA implements i2c.Pins.
+func (d *DeviceBus) SDA() gpio.PinIO {
+	return gpio.INVALID
+}
+
+var _ i2c.Bus = &DeviceBus{}
+var _ i2c.BusContext = &DeviceBus{}
+var _ i2c.Pins = &DeviceBus{}
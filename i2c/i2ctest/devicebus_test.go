@@ -0,0 +1,47 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2ctest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestDeviceBus(t *testing.T) {
+	dev := &conntest.RegisterDevice{Regs: map[uint16]*conntest.Register{0xD0: {Value: 0x60, ReadOnly: true}}}
+	b := &DeviceBus{Devices: map[uint16]conn.Conn{0x76: dev}}
+	if s := b.String(); s != "devicebus" {
+		t.Fatal(s)
+	}
+	if err := b.SetSpeed(400 * physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if b.SCL() != gpio.INVALID || b.SDA() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	d := i2c.Dev{Bus: b, Addr: 0x76}
+	r := make([]byte, 1)
+	if err := d.Tx([]byte{0xD0}, r); err != nil || r[0] != 0x60 {
+		t.Fatal(r, err)
+	}
+	if err := b.Tx(0x77, []byte{0xD0}, r); !errors.Is(err, conn.ErrAddrNACK) {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.TxContext(ctx, 0x77, nil, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := b.TxContext(ctx, 0x76, nil, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2ctest
+
+import (
+	"context"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Faulty implements i2c.Bus and injects faults in the transactions on Bus.
+//
+// See conntest.Faults for the supported faults.
+type Faulty struct {
+	Bus i2c.Bus
+	conntest.Faults
+}
+
+func (f *Faulty) String() string {
+	return "faulty(" + f.Bus.String() + ")"
+}
+
+// Tx implements i2c.Bus.
+func (f *Faulty) Tx(addr uint16, w, r []byte) error {
+	return f.TxContext(context.Background(), addr, w, r)
+}
+
+// TxContext implements i2c.BusContext.
+//
+// ctx is forwarded to Bus if it implements i2c.BusContext.
+func (f *Faulty) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	return f.Do(ctx, func() error {
+		if bc, ok := f.Bus.(i2c.BusContext); ok {
+			return bc.TxContext(ctx, addr, w, r)
+		}
+		return conn.DoContext(ctx, w, r, func(w, r []byte) error {
+			return f.Bus.Tx(addr, w, r)
+		})
+	}, r)
+}
+
+// SetSpeed implements i2c.Bus.
+func (f *Faulty) SetSpeed(freq physic.Frequency) error {
+	return f.Bus.SetSpeed(freq)
+}
+
+// SCL implements i2c.Pins.
+func (f *Faulty) SCL() gpio.PinIO {
+	if p, ok := f.Bus.(i2c.Pins); ok {
+		return p.SCL()
+	}
+	return gpio.INVALID
+}
+
+// SDA implements i2c.Pins.
+func (f *Faulty) SDA() gpio.PinIO {
+	if p, ok := f.Bus.(i2c.Pins); ok {
+		return p.SDA()
+	}
+	return gpio.INVALID
+}
+
+var _ i2c.Bus = &Faulty{}
+var _ i2c.BusContext = &Faulty{}
+var _ i2c.Pins = &Faulty{}
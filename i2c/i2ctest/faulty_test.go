@@ -0,0 +1,55 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2ctest
+
+import (
+	"errors"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestFaulty(t *testing.T) {
+	p := &Playback{Ops: []IO{{Addr: 0x23, W: []byte{1}, R: []byte{2}}}, DontPanic: true}
+	f := &Faulty{Bus: p, Faults: conntest.Faults{FailAt: []int{1}, Err: conn.ErrAddrNACK, BitFlipRate: 1}}
+	if s := f.String(); s != "faulty(playback)" {
+		t.Fatal(s)
+	}
+	if err := f.SetSpeed(100 * physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if f.SCL() != nil || f.SDA() != nil {
+		t.Fatal("expected the Playback pins")
+	}
+	if (&Faulty{Bus: &Record{}}).SCL() != gpio.INVALID || (&Faulty{Bus: &Record{}}).SDA() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	r := make([]byte, 1)
+	if err := f.Tx(0x23, []byte{1}, r); err != conn.ErrAddrNACK {
+		t.Fatal(err)
+	}
+	if err := f.Tx(0x23, []byte{1}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != ^byte(2) {
+		t.Fatal(r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFaulty_Err(t *testing.T) {
+	boom := errors.New("boom")
+	p := &Playback{Ops: []IO{{Addr: 0x76, W: []byte{1}, Err: boom}}}
+	f := &Faulty{Bus: p}
+	// The error of the bus is returned as-is.
+	if err := f.Tx(0x76, []byte{1}, nil); err != boom {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2ctest
+
+import (
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/i2c"
+)
+
+// Golden returns a Playback of the I²C transactions saved in the golden file
+// at path, with their device address.
+//
+// When conntest.UpdateGolden is true, it returns a Record over b instead. See
+// conntest.GoldenFile for the details and conntest.SaveGolden for the file
+// formats.
+func Golden(t testing.TB, path string, b i2c.Bus) i2c.Bus {
+	t.Helper()
+	return conntest.GoldenFile(t, path, func() (i2c.Bus, *[]IO) {
+		r := &Record{Bus: b}
+		return r, &r.Ops
+	}, func(ops []IO) (i2c.Bus, io.Closer) {
+		p := &Playback{Ops: ops, DontPanic: true}
+		return p, p
+	})
+}
+
+// MarshalText implements encoding.TextMarshaler for the golden text format.
+//
+// The fields are "addr", "w", "r" and "err".
+func (io IO) MarshalText() ([]byte, error) {
+	return conntest.FormatGoldenLine("addr", fmt.Sprintf("0x%02x", io.Addr), "w", hex.EncodeToString(io.W), "r", hex.EncodeToString(io.R), "err", conntest.GoldenErrorName(io.Err)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the golden text
+// format.
+func (io *IO) UnmarshalText(b []byte) error {
+	f, err := conntest.ParseGoldenLine(b)
+	if err != nil {
+		return err
+	}
+	var n IO
+	for k, v := range f {
+		switch k {
+		case "addr":
+			var a uint64
+			a, err = strconv.ParseUint(v, 0, 16)
+			n.Addr = uint16(a)
+		case "w":
+			n.W, err = conntest.ParseGoldenHex(v)
+		case "r":
+			n.R, err = conntest.ParseGoldenHex(v)
+		case "err":
+			n.Err = conntest.GoldenError(v)
+		default:
+			err = fmt.Errorf("unknown field %q", k)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	*io = n
+	return nil
+}
+
+var _ encoding.TextMarshaler = IO{}
+var _ encoding.TextUnmarshaler = &IO{}
@@ -0,0 +1,70 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package i2ctest
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+)
+
+func TestIO_MarshalText(t *testing.T) {
+	io := IO{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}, Err: conn.ErrDataNACK}
+	b, err := io.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); s != "addr=0x76 w=d0 r=60 err=data_nack" {
+		t.Fatal(s)
+	}
+	var got IO
+	if err := got.UnmarshalText(b); err != nil {
+		t.Fatal(err)
+	}
+	if got.Addr != 0x76 || string(got.W) != "\xd0" || string(got.R) != "\x60" || got.Err != conn.ErrDataNACK {
+		t.Fatalf("%#v", got)
+	}
+	for _, line := range []string{"addr=0x10000", "w=0", "r=0", "x=1", "addr"} {
+		if err := got.UnmarshalText([]byte(line)); err == nil {
+			t.Fatalf("%q: expected error", line)
+		}
+	}
+}
+
+func TestGolden_Playback(t *testing.T) {
+	b := Golden(t, filepath.Join("testdata", "bmx280.txt"), nil)
+	r := make([]byte, 1)
+	if err := b.Tx(0x76, []byte{0xd0}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 0x60 {
+		t.Fatal(r)
+	}
+	if err := b.Tx(0x77, []byte{0xd0}, r); !errors.Is(err, conn.ErrAddrNACK) {
+		t.Fatal(err)
+	}
+}
+
+func TestGolden_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	defer func(u bool) { conntest.UpdateGolden = u }(conntest.UpdateGolden)
+	conntest.UpdateGolden = true
+	t.Run("update", func(t *testing.T) {
+		b := Golden(t, path, &Playback{Ops: []IO{{Addr: 0x23, W: []byte{1}, R: []byte{2}}}})
+		if err := b.Tx(0x23, []byte{1}, make([]byte, 1)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	ops, err := conntest.LoadGolden[IO](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 1 || ops[0].Addr != 0x23 || string(ops[0].R) != "\x02" {
+		t.Fatalf("%#v", ops)
+	}
+}
@@ -7,8 +7,10 @@ package i2ctest
 
 import (
 	"bytes"
+	"context"
 	"sync"
 
+	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/conntest"
 	"periph.io/x/conn/v3/gpio"
 	"periph.io/x/conn/v3/i2c"
@@ -20,11 +22,15 @@ type IO struct {
 	Addr uint16
 	W    []byte
 	R    []byte
+	// Err is returned by Playback once W and R are processed. Use one of the
+	// well known conn errors like conn.ErrAddrNACK to simulate a failure.
+	Err error
 }
 
 // Record implements i2c.Bus that records everything written to it.
 //
 // This can then be used to feed to Playback to do "replay" based unit tests.
+// Use Golden() to keep the transactions in a file along with their address.
 //
 // Record doesn't implement i2c.BusCloser on purpose.
 type Record struct {
@@ -39,6 +45,16 @@ func (r *Record) String() string {
 
 // Tx implements i2c.Bus
 func (r *Record) Tx(addr uint16, w, read []byte) error {
+	return r.TxContext(context.Background(), addr, w, read)
+}
+
+// TxContext implements i2c.BusContext.
+//
+// ctx is forwarded to Bus if it implements i2c.BusContext.
+func (r *Record) TxContext(ctx context.Context, addr uint16, w, read []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	io := IO{Addr: addr}
 	if len(w) != 0 {
 		io.W = make([]byte, len(w))
@@ -50,10 +66,14 @@ func (r *Record) Tx(addr uint16, w, read []byte) error {
 		if len(read) != 0 {
 			return conntest.Errorf("i2ctest: read unsupported when no bus is connected")
 		}
-	} else {
-		if err := r.Bus.Tx(addr, w, read); err != nil {
+	} else if bc, ok := r.Bus.(i2c.BusContext); ok {
+		if err := bc.TxContext(ctx, addr, w, read); err != nil {
 			return err
 		}
+	} else if err := conn.DoContext(ctx, w, read, func(w, read []byte) error {
+		return r.Bus.Tx(addr, w, read)
+	}); err != nil {
+		return err
 	}
 	if len(read) != 0 {
 		io.R = make([]byte, len(read))
@@ -92,6 +112,8 @@ func (r *Record) SDA() gpio.PinIO {
 // While "replay" type of unit tests are of limited value, they still present
 // an easy way to do basic code coverage.
 //
+// Set IO.Err to simulate a failing transaction.
+//
 // Set DontPanic to true to return an error instead of panicking, which is the
 // default.
 type Playback struct {
@@ -137,7 +159,17 @@ func (p *Playback) Tx(addr uint16, w, r []byte) error {
 	}
 	copy(r, p.Ops[p.Count].R)
 	p.Count++
-	return nil
+	return p.Ops[p.Count-1].Err
+}
+
+// TxContext implements i2c.BusContext.
+//
+// If ctx is done, ctx.Err() is returned and the current op is not consumed.
+func (p *Playback) TxContext(ctx context.Context, addr uint16, w, r []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.Tx(addr, w, r)
 }
 
 // SetSpeed implements i2c.Bus.
@@ -169,6 +201,8 @@ func errorf(dontPanic bool, format string, a ...interface{}) error {
 }
 
 var _ i2c.Bus = &Record{}
+var _ i2c.BusContext = &Record{}
 var _ i2c.Pins = &Record{}
 var _ i2c.Bus = &Playback{}
+var _ i2c.BusContext = &Playback{}
 var _ i2c.Pins = &Playback{}
@@ -5,8 +5,11 @@
 package i2ctest
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/conntest"
 	"periph.io/x/conn/v3/gpio"
 	"periph.io/x/conn/v3/gpio/gpiotest"
@@ -165,3 +168,52 @@ func TestRecord_Playback(t *testing.T) {
 		t.Fatal("Playback.Ops is empty")
 	}
 }
+
+func TestRecord_Playback_TxContext(t *testing.T) {
+	p := &Playback{Ops: []IO{{Addr: 23, W: []byte{10}, R: []byte{12}}}, DontPanic: true}
+	r := Record{Bus: p}
+	v := [1]byte{}
+	if err := r.TxContext(context.Background(), 23, []byte{10}, v[:]); err != nil {
+		t.Fatal(err)
+	}
+	if v[0] != 12 || len(r.Ops) != 1 {
+		t.Fatal(v, r.Ops)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.TxContext(ctx, 23, []byte{10}, v[:]); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := p.TxContext(ctx, 23, []byte{10}, v[:]); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayback_Err(t *testing.T) {
+	p := Playback{Ops: []IO{{Addr: 1, W: []byte{1}, R: []byte{2}, Err: conn.ErrAddrNACK}}, DontPanic: true}
+	r := make([]byte, 1)
+	if err := p.Tx(1, []byte{1}, r); !errors.Is(err, conn.ErrAddrNACK) {
+		t.Fatal(err)
+	}
+	if r[0] != 2 {
+		t.Fatal(r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecord_Err(t *testing.T) {
+	boom := errors.New("boom")
+	r := Record{Bus: &Playback{Ops: []IO{{Addr: 0x76, W: []byte{1}, Err: boom}}}}
+	// The error of the bus is returned as-is, as it would be without Record.
+	if err := r.Tx(0x76, []byte{1}, nil); err != boom {
+		t.Fatal(err)
+	}
+	if len(r.Ops) != 0 {
+		t.Fatal(r.Ops)
+	}
+}
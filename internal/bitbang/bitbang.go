@@ -0,0 +1,38 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package bitbang contains the timing helpers shared by the protocols
+// implemented in software over GPIO pins.
+//
+// The waits are at least as long as requested; the latency of the GPIO
+// accesses between them and the scheduling of the OS only make the signals
+// slower than the configured rate.
+package bitbang
+
+import "time"
+
+// Delay waits for d.
+//
+// See Until() for the precision.
+func Delay(d time.Duration) {
+	Until(time.Now().Add(d))
+}
+
+// Until waits until t.
+//
+// Only the last part of the wait is a busy loop, since time.Sleep() is not
+// precise enough; the rest sleeps so the CPU is not held for long waits.
+func Until(t time.Time) {
+	if d := time.Until(t) - maxSpin; d > 0 {
+		time.Sleep(d)
+	}
+	for time.Now().Before(t) {
+	}
+}
+
+//
+
+// maxSpin is the longest busy loop, which covers the usual oversleep of
+// time.Sleep().
+const maxSpin = 200 * time.Microsecond
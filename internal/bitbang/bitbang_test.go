@@ -0,0 +1,29 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bitbang
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	for _, d := range []time.Duration{0, 10 * time.Microsecond, maxSpin, 2 * time.Millisecond} {
+		start := time.Now()
+		Delay(d)
+		if e := time.Since(start); e < d {
+			t.Fatalf("Delay(%s) returned after %s", d, e)
+		}
+	}
+}
+
+func TestUntil(t *testing.T) {
+	Until(time.Now().Add(-time.Second))
+	end := time.Now().Add(time.Millisecond)
+	Until(end)
+	if time.Now().Before(end) {
+		t.Fatal("returned early")
+	}
+}
@@ -12,6 +12,7 @@ package mmr
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -46,6 +47,13 @@ func (d *Dev8) Tx(w, r []byte) error {
 	return d.Conn.Tx(w, r)
 }
 
+// TxContext implements conn.ConnContext.
+//
+// It uses conn.WithContext() on Conn.
+func (d *Dev8) TxContext(ctx context.Context, w, r []byte) error {
+	return conn.WithContext(d.Conn).TxContext(ctx, w, r)
+}
+
 // ReadUint8 reads a 8 bit register.
 func (d *Dev8) ReadUint8(reg uint8) (uint8, error) {
 	if err := d.check(); err != nil {
@@ -186,6 +194,13 @@ func (d *Dev16) Tx(w, r []byte) error {
 	return d.Conn.Tx(w, r)
 }
 
+// TxContext implements conn.ConnContext.
+//
+// It uses conn.WithContext() on Conn.
+func (d *Dev16) TxContext(ctx context.Context, w, r []byte) error {
+	return conn.WithContext(d.Conn).TxContext(ctx, w, r)
+}
+
 // ReadUint8 reads a 8 bit register.
 func (d *Dev16) ReadUint8(reg uint16) (uint8, error) {
 	if err := d.check(); err != nil {
@@ -413,3 +428,5 @@ func isAcceptableInner(t reflect.Type) bool {
 
 var _ conn.Conn = &Dev8{}
 var _ conn.Conn = &Dev16{}
+var _ conn.ConnContext = &Dev8{}
+var _ conn.ConnContext = &Dev16{}
@@ -6,10 +6,12 @@ package mmr
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/conntest"
@@ -36,6 +38,23 @@ func TestDev8_Tx(t *testing.T) {
 	}
 }
 
+func TestDev8_TxContext(t *testing.T) {
+	c := &conntest.Playback{Ops: []conntest.IO{{W: []byte{34}, R: []byte{1}}}, D: conn.Half}
+	d := Dev8{Conn: c, Order: binary.BigEndian}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.TxContext(ctx, []byte{34}, make([]byte, 1)); err != context.Canceled {
+		t.Fatal(err)
+	}
+	r := make([]byte, 1)
+	if err := d.TxContext(context.Background(), []byte{34}, r); err != nil || r[0] != 1 {
+		t.Fatal(r, err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestDev8_ReadUint_Full(t *testing.T) {
 	d := Dev8{Conn: &conntest.Discard{D: conn.Full}, Order: binary.BigEndian}
 	if v, err := d.ReadUint8(34); err == nil || v != 0 {
@@ -285,6 +304,17 @@ func TestDev16_Tx(t *testing.T) {
 	}
 }
 
+func TestDev16_TxContext(t *testing.T) {
+	c := &blockingConn{unblock: make(chan struct{})}
+	defer close(c.unblock)
+	d := Dev16{Conn: c, Order: binary.BigEndian}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := d.TxContext(ctx, []byte{0, 34}, make([]byte, 1)); err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+}
+
 func TestDev16_ReadUint_Full(t *testing.T) {
 	d := Dev16{Conn: &conntest.Discard{D: conn.Full}, Order: binary.BigEndian}
 	if v, err := d.ReadUint8(34); err == nil || v != 0 {
@@ -547,3 +577,22 @@ type writeFail struct{}
 func (w writeFail) Write(p []byte) (int, error) {
 	return 0, errors.New("simulating failure")
 }
+
+// blockingConn implements conn.Conn and blocks transactions until unblock is
+// closed.
+type blockingConn struct {
+	unblock chan struct{}
+}
+
+func (b *blockingConn) String() string {
+	return "blocking"
+}
+
+func (b *blockingConn) Tx(w, r []byte) error {
+	<-b.unblock
+	return nil
+}
+
+func (b *blockingConn) Duplex() conn.Duplex {
+	return conn.Half
+}
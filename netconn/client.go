@@ -0,0 +1,687 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package netconn
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewirereg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/conn/v3/uart"
+	"periph.io/x/conn/v3/uart/uartreg"
+)
+
+// Client opens the objects served by a Server.
+type Client struct {
+	// Dial opens a new stream to the server. It is called once per remote
+	// object.
+	Dial func() (net.Conn, error)
+}
+
+// NewClient returns a Client that connects to address on network, for
+// example "tcp" and "raspberrypi.local:7000" or "unix" and
+// "/run/periph.sock".
+//
+// No connection is made until an object is opened.
+func NewClient(network, address string) *Client {
+	return &Client{Dial: func() (net.Conn, error) {
+		return net.Dial(network, address)
+	}}
+}
+
+// Inventory lists the objects served by a Server.
+type Inventory struct {
+	I2C     []string
+	SPI     []string
+	OneWire []string
+	UART    []string
+	GPIO    []PinRef
+}
+
+// PinRef identifies a remote GPIO pin.
+type PinRef struct {
+	Name   string
+	Number int
+}
+
+// List returns the objects served by the server.
+func (c *Client) List() (*Inventory, error) {
+	s := &stream{c: c}
+	defer s.close()
+	d, err := s.call(newEncoder(uint8(opList)))
+	if err != nil {
+		return nil, err
+	}
+	inv := &Inventory{}
+	for _, l := range []*[]string{&inv.I2C, &inv.SPI, &inv.OneWire, &inv.UART} {
+		n := d.length()
+		for i := 0; i < n && d.err == nil; i++ {
+			*l = append(*l, d.string())
+		}
+	}
+	n := d.length()
+	for i := 0; i < n && d.err == nil; i++ {
+		inv.GPIO = append(inv.GPIO, PinRef{Name: d.string(), Number: int(int32(d.u32()))})
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return inv, nil
+}
+
+// OpenI2C opens the remote I²C bus name.
+func (c *Client) OpenI2C(name string) (i2c.BusCloser, error) {
+	s, err := c.open(kindI2C, name)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteI2C{s}, nil
+}
+
+// OpenSPI opens the remote SPI port name.
+func (c *Client) OpenSPI(name string) (spi.PortCloser, error) {
+	s, err := c.open(kindSPI, name)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteSPIPort{s}, nil
+}
+
+// OpenOneWire opens the remote 1-wire bus name.
+func (c *Client) OpenOneWire(name string) (onewire.BusCloser, error) {
+	s, err := c.open(kindOneWire, name)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteOneWire{s}, nil
+}
+
+// OpenUART opens the remote UART port name.
+func (c *Client) OpenUART(name string) (uart.PortCloser, error) {
+	s, err := c.open(kindUART, name)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteUARTPort{s}, nil
+}
+
+// OpenPin opens the remote GPIO pin name.
+//
+// The returned pin also implements io.Closer to release the connection.
+func (c *Client) OpenPin(name string) (gpio.PinIO, error) {
+	s, err := c.open(kindGPIO, name)
+	if err != nil {
+		return nil, err
+	}
+	return &remotePin{s: s, name: name, number: -1}, nil
+}
+
+// Register registers all the objects served by the server in the local
+// registries, with their name prefixed with prefix.
+//
+// prefix must not contain ':'. Buses and ports are registered without a
+// number and are opened on demand. Pins keep their number, and open their
+// connection on first use; errors are then reported the same way as by a
+// pin that fails locally, e.g. Read() returns Low.
+func (c *Client) Register(prefix string) error {
+	inv, err := c.List()
+	if err != nil {
+		return err
+	}
+	for _, name := range inv.I2C {
+		name := name
+		if err := i2creg.Register(prefix+name, nil, -1, func() (i2c.BusCloser, error) { return c.OpenI2C(name) }); err != nil {
+			return err
+		}
+	}
+	for _, name := range inv.SPI {
+		name := name
+		if err := spireg.Register(prefix+name, nil, -1, func() (spi.PortCloser, error) { return c.OpenSPI(name) }); err != nil {
+			return err
+		}
+	}
+	for _, name := range inv.OneWire {
+		name := name
+		if err := onewirereg.Register(prefix+name, nil, -1, func() (onewire.BusCloser, error) { return c.OpenOneWire(name) }); err != nil {
+			return err
+		}
+	}
+	for _, name := range inv.UART {
+		name := name
+		if err := uartreg.Register(prefix+name, nil, -1, func() (uart.PortCloser, error) { return c.OpenUART(name) }); err != nil {
+			return err
+		}
+	}
+	for _, p := range inv.GPIO {
+		s := &stream{c: c, k: kindGPIO, name: p.Name}
+		if err := gpioreg.Register(&remotePin{s: s, name: prefix + p.Name, number: p.Number}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//
+
+var errClosed = errors.New("netconn: object closed")
+
+// open returns a stream bound to the remote object name.
+func (c *Client) open(k kind, name string) (*stream, error) {
+	s := &stream{c: c, k: k, name: name}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.openLocked(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// stream is the client side of a connection to the server.
+//
+// Calls are serialized. A transport error is sticky.
+type stream struct {
+	c    *Client
+	k    kind
+	name string
+
+	mu  sync.Mutex
+	str string
+	err error
+
+	// cmu protects closed, and conn when it is set, so close() doesn't wait
+	// for a pending call. conn is also read with mu held.
+	cmu    sync.Mutex
+	conn   net.Conn
+	closed bool
+}
+
+// openLocked connects and binds the stream to its object, if not already
+// done.
+func (s *stream) openLocked() error {
+	s.cmu.Lock()
+	closed := s.closed
+	s.cmu.Unlock()
+	if closed {
+		return errClosed
+	}
+	if s.err != nil || s.conn != nil {
+		return s.err
+	}
+	c, err := s.c.Dial()
+	if err != nil {
+		s.err = err
+		return err
+	}
+	s.cmu.Lock()
+	if s.closed {
+		s.cmu.Unlock()
+		_ = c.Close()
+		return errClosed
+	}
+	s.conn = c
+	s.cmu.Unlock()
+	if s.k == 0 {
+		return nil
+	}
+	e := newEncoder(uint8(opOpen))
+	e.u8(version)
+	e.u8(uint8(s.k))
+	e.string(s.name)
+	d, err := s.roundTripLocked(e)
+	if err == nil {
+		s.str = d.string()
+		err = d.err
+	}
+	if err != nil {
+		s.err = err
+		_ = s.conn.Close()
+	}
+	return err
+}
+
+// call sends a request and returns the response.
+func (s *stream) call(e *encoder) (*decoder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.openLocked(); err != nil {
+		return nil, err
+	}
+	return s.roundTripLocked(e)
+}
+
+func (s *stream) roundTripLocked(e *encoder) (*decoder, error) {
+	d, err := s.transportLocked(e)
+	if err != nil {
+		s.cmu.Lock()
+		if s.closed {
+			// The connection was closed by close() during the call.
+			err = errClosed
+		}
+		s.cmu.Unlock()
+		s.err = err
+		_ = s.conn.Close()
+		return nil, err
+	}
+	if d.u8() == statusOK {
+		return d, nil
+	}
+	class := d.u8()
+	msg := d.string()
+	if d.err != nil {
+		return nil, d.err
+	}
+	r := &remoteError{msg: msg}
+	if int(class) < len(classes) {
+		r.class = classes[class]
+	}
+	return nil, r
+}
+
+func (s *stream) transportLocked(e *encoder) (*decoder, error) {
+	if err := e.send(s.conn); err != nil {
+		return nil, err
+	}
+	d, err := readFrame(s.conn)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return d, err
+}
+
+// String returns the String() of the remote object.
+func (s *stream) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.str == "" {
+		return s.name
+	}
+	return s.str
+}
+
+// close closes the connection. A pending call fails with errClosed.
+func (s *stream) close() error {
+	s.cmu.Lock()
+	defer s.cmu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	if err := s.conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+// tx sends a request that ends with the length of r, and copies the
+// returned data into r.
+func (s *stream) tx(e *encoder, r []byte) error {
+	d, err := s.call(e)
+	if err != nil {
+		return err
+	}
+	return readInto(d, r)
+}
+
+func readInto(d *decoder, r []byte) error {
+	b := d.bytes()
+	if d.err == nil && len(b) != len(r) {
+		return errShortFrame
+	}
+	copy(r, b)
+	return d.err
+}
+
+type remoteI2C struct {
+	s *stream
+}
+
+func (r *remoteI2C) String() string {
+	return r.s.String()
+}
+
+func (r *remoteI2C) Close() error {
+	return r.s.close()
+}
+
+func (r *remoteI2C) Tx(addr uint16, w, read []byte) error {
+	e := newEncoder(uint8(opI2CTx))
+	e.u16(addr)
+	e.bytes(w)
+	e.u32(uint32(len(read)))
+	return r.s.tx(e, read)
+}
+
+func (r *remoteI2C) SetSpeed(f physic.Frequency) error {
+	return setSpeed(r.s, f)
+}
+
+type remoteSPIPort struct {
+	s *stream
+}
+
+func (r *remoteSPIPort) String() string {
+	return r.s.String()
+}
+
+func (r *remoteSPIPort) Close() error {
+	return r.s.close()
+}
+
+func (r *remoteSPIPort) LimitSpeed(f physic.Frequency) error {
+	return setSpeed(r.s, f)
+}
+
+func (r *remoteSPIPort) Connect(f physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	e := newEncoder(uint8(opSPIConnect))
+	e.u64(uint64(f))
+	e.u32(uint32(mode))
+	e.u32(uint32(bits))
+	d, err := r.s.call(e)
+	if err != nil {
+		return nil, err
+	}
+	duplex := conn.Duplex(d.u8())
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &remoteSPIConn{remoteConn{r.s, duplex}}, nil
+}
+
+type remoteConn struct {
+	s      *stream
+	duplex conn.Duplex
+}
+
+func (r *remoteConn) String() string {
+	return r.s.String()
+}
+
+func (r *remoteConn) Tx(w, read []byte) error {
+	e := newEncoder(uint8(opConnTx))
+	e.bytes(w)
+	e.u32(uint32(len(read)))
+	return r.s.tx(e, read)
+}
+
+func (r *remoteConn) Duplex() conn.Duplex {
+	return r.duplex
+}
+
+type remoteSPIConn struct {
+	remoteConn
+}
+
+func (r *remoteSPIConn) TxPackets(p []spi.Packet) error {
+	e := newEncoder(uint8(opSPITxPackets))
+	e.u32(uint32(len(p)))
+	for i := range p {
+		e.bytes(p[i].W)
+		e.u32(uint32(len(p[i].R)))
+		e.u8(p[i].BitsPerWord)
+		e.bool(p[i].KeepCS)
+	}
+	d, err := r.s.call(e)
+	if err != nil {
+		return err
+	}
+	for i := range p {
+		if err := readInto(d, p[i].R); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type remoteOneWire struct {
+	s *stream
+}
+
+func (r *remoteOneWire) String() string {
+	return r.s.String()
+}
+
+func (r *remoteOneWire) Close() error {
+	return r.s.close()
+}
+
+func (r *remoteOneWire) Tx(w, read []byte, power onewire.Pullup) error {
+	e := newEncoder(uint8(opOneWireTx))
+	e.bytes(w)
+	e.u32(uint32(len(read)))
+	e.bool(bool(power))
+	return r.s.tx(e, read)
+}
+
+func (r *remoteOneWire) Search(alarmOnly bool) ([]onewire.Address, error) {
+	e := newEncoder(uint8(opOneWireSearch))
+	e.bool(alarmOnly)
+	d, err := r.s.call(e)
+	if err != nil {
+		return nil, err
+	}
+	n := d.length()
+	var addrs []onewire.Address
+	for i := 0; i < n && d.err == nil; i++ {
+		addrs = append(addrs, onewire.Address(d.u64()))
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return addrs, nil
+}
+
+type remoteUARTPort struct {
+	s *stream
+}
+
+func (r *remoteUARTPort) String() string {
+	return r.s.String()
+}
+
+func (r *remoteUARTPort) Close() error {
+	return r.s.close()
+}
+
+func (r *remoteUARTPort) LimitSpeed(f physic.Frequency) error {
+	return setSpeed(r.s, f)
+}
+
+func (r *remoteUARTPort) Connect(f physic.Frequency, stopBit uart.Stop, parity uart.Parity, flow uart.Flow, bits int) (conn.Conn, error) {
+	e := newEncoder(uint8(opUARTConnect))
+	e.u64(uint64(f))
+	e.u8(uint8(stopBit))
+	e.u8(uint8(parity))
+	e.u32(uint32(flow))
+	e.u32(uint32(bits))
+	d, err := r.s.call(e)
+	if err != nil {
+		return nil, err
+	}
+	duplex := conn.Duplex(d.u8())
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &remoteConn{r.s, duplex}, nil
+}
+
+func setSpeed(s *stream, f physic.Frequency) error {
+	e := newEncoder(uint8(opSetSpeed))
+	e.u64(uint64(f))
+	_, err := s.call(e)
+	return err
+}
+
+// remotePin is a remote GPIO pin.
+//
+// The methods that cannot return an error return the zero value on failure.
+type remotePin struct {
+	s      *stream
+	name   string
+	number int
+
+	mu sync.Mutex
+	// wait is the stream used by WaitForEdge(), so a pending wait doesn't
+	// hold up the other calls. It is closed by In(), Halt() and Close() to
+	// make the wait return false.
+	wait    *stream
+	waiting int
+	closed  bool
+}
+
+func (r *remotePin) String() string {
+	return r.name
+}
+
+func (r *remotePin) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	w := r.wait
+	r.wait = nil
+	r.mu.Unlock()
+	if w != nil {
+		_ = w.close()
+	}
+	return r.s.close()
+}
+
+func (r *remotePin) Halt() error {
+	r.interrupt()
+	_, err := r.s.call(newEncoder(uint8(opHalt)))
+	return err
+}
+
+func (r *remotePin) Name() string {
+	return r.name
+}
+
+func (r *remotePin) Number() int {
+	return r.number
+}
+
+func (r *remotePin) Function() string {
+	d, err := r.s.call(newEncoder(uint8(opPinFunction)))
+	if err != nil {
+		return ""
+	}
+	return d.string()
+}
+
+func (r *remotePin) In(pull gpio.Pull, edge gpio.Edge) error {
+	r.interrupt()
+	e := newEncoder(uint8(opPinIn))
+	e.u8(uint8(pull))
+	e.u8(uint8(edge))
+	_, err := r.s.call(e)
+	return err
+}
+
+func (r *remotePin) Read() gpio.Level {
+	d, err := r.s.call(newEncoder(uint8(opPinRead)))
+	if err != nil {
+		return gpio.Low
+	}
+	return gpio.Level(d.bool())
+}
+
+func (r *remotePin) WaitForEdge(timeout time.Duration) bool {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return false
+	}
+	if r.wait == nil {
+		r.wait = &stream{c: r.s.c, k: r.s.k, name: r.s.name}
+	}
+	w := r.wait
+	r.waiting++
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.waiting--
+		r.mu.Unlock()
+	}()
+	e := newEncoder(uint8(opPinWaitForEdge))
+	e.u64(uint64(timeout))
+	d, err := w.call(e)
+	if err != nil {
+		r.mu.Lock()
+		if r.wait == w {
+			// Reconnect on the next call.
+			r.wait = nil
+		}
+		r.mu.Unlock()
+		return false
+	}
+	return d.bool()
+}
+
+// interrupt makes the pending WaitForEdge() calls return false.
+func (r *remotePin) interrupt() {
+	r.mu.Lock()
+	var w *stream
+	if r.waiting != 0 {
+		w = r.wait
+		r.wait = nil
+	}
+	r.mu.Unlock()
+	if w != nil {
+		_ = w.close()
+	}
+}
+
+func (r *remotePin) Pull() gpio.Pull {
+	d, err := r.s.call(newEncoder(uint8(opPinPull)))
+	if err != nil {
+		return gpio.PullNoChange
+	}
+	return gpio.Pull(d.u8())
+}
+
+func (r *remotePin) DefaultPull() gpio.Pull {
+	d, err := r.s.call(newEncoder(uint8(opPinDefaultPull)))
+	if err != nil {
+		return gpio.PullNoChange
+	}
+	return gpio.Pull(d.u8())
+}
+
+func (r *remotePin) Out(l gpio.Level) error {
+	e := newEncoder(uint8(opPinOut))
+	e.bool(bool(l))
+	_, err := r.s.call(e)
+	return err
+}
+
+func (r *remotePin) PWM(duty gpio.Duty, f physic.Frequency) error {
+	e := newEncoder(uint8(opPinPWM))
+	e.u32(uint32(duty))
+	e.u64(uint64(f))
+	_, err := r.s.call(e)
+	return err
+}
+
+var _ i2c.BusCloser = &remoteI2C{}
+var _ spi.PortCloser = &remoteSPIPort{}
+var _ spi.Conn = &remoteSPIConn{}
+var _ onewire.BusCloser = &remoteOneWire{}
+var _ uart.PortCloser = &remoteUARTPort{}
+var _ conn.Conn = &remoteConn{}
+var _ gpio.PinIO = &remotePin{}
+var _ io.Closer = &remotePin{}
@@ -0,0 +1,605 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package netconn
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewirereg"
+	"periph.io/x/conn/v3/onewire/onewiretest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/conn/v3/spi/spitest"
+	"periph.io/x/conn/v3/uart"
+	"periph.io/x/conn/v3/uart/uartreg"
+)
+
+func TestClient_I2C(t *testing.T) {
+	f := registerFakes(t)
+	f.i2c.Ops = []i2ctest.IO{
+		{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}},
+		{Addr: 0x77, W: []byte{0xd0}, Err: conn.ErrAddrNACK},
+	}
+	c := newPipeClient(t)
+	b, err := c.OpenI2C("NETI2C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := b.String(); s != "playback" {
+		t.Fatal(s)
+	}
+	if err := b.SetSpeed(100 * physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	d := i2c.Dev{Bus: b, Addr: 0x76}
+	r := make([]byte, 1)
+	if err := d.Tx([]byte{0xd0}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 0x60 {
+		t.Fatal(r)
+	}
+	if err := b.Tx(0x77, []byte{0xd0}, nil); !errors.Is(err, conn.ErrAddrNACK) || !conn.IsTemporary(err) {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx(0x76, nil, nil); err != errClosed {
+		t.Fatal(err)
+	}
+	if _, err := c.OpenI2C("unknown"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClient_SPI(t *testing.T) {
+	f := registerFakes(t)
+	f.spi.Ops = []conntest.IO{
+		{W: []byte{1, 2}, R: []byte{3, 4}},
+		{W: []byte{5}, R: []byte{6}},
+	}
+	c := newPipeClient(t)
+	p, err := c.OpenSPI("NETSPI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.LimitSpeed(physic.MegaHertz); err != nil {
+		t.Fatal(err)
+	}
+	s, err := p.Connect(physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := s.Duplex(); d != conn.DuplexUnknown {
+		t.Fatal(d)
+	}
+	if str := s.String(); str != "playback" {
+		t.Fatal(str)
+	}
+	r := make([]byte, 2)
+	if err := s.Tx([]byte{1, 2}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{3, 4}) {
+		t.Fatal(r)
+	}
+	// spitest.Playback doesn't support TxPackets, but the error makes it back.
+	pkts := []spi.Packet{{W: []byte{5}, R: make([]byte, 1), KeepCS: true}}
+	if err := s.TxPackets(pkts); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := s.Tx([]byte{5}, r[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Connect(physic.MegaHertz, spi.Mode0, 8); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_SPI_TxPackets(t *testing.T) {
+	registerFakes(t)
+	c := newPipeClient(t)
+	p, err := c.OpenSPI("NETSPI2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	s, err := p.Connect(physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkts := []spi.Packet{
+		{W: []byte{1}, BitsPerWord: 8, KeepCS: true},
+		{R: make([]byte, 2)},
+	}
+	if err := s.TxPackets(pkts); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pkts[1].R, []byte{0xaa, 0xaa}) {
+		t.Fatal(pkts[1].R)
+	}
+}
+
+func TestClient_OneWire(t *testing.T) {
+	f := registerFakes(t)
+	f.ow.Ops = []onewiretest.IO{
+		{W: []byte{0xcc, 0x44}, Pull: onewire.StrongPullup},
+		{W: []byte{0xf0}},
+	}
+	f.ow.Devices = []onewire.Address{0x7a00000131825228}
+	c := newPipeClient(t)
+	b, err := c.OpenOneWire("NET1W")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx([]byte{0xcc, 0x44}, nil, onewire.StrongPullup); err != nil {
+		t.Fatal(err)
+	}
+	a, err := b.Search(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 1 || a[0] != 0x7a00000131825228 {
+		t.Fatal(a)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_UART(t *testing.T) {
+	f := registerFakes(t)
+	f.uart.c.Ops = []conntest.IO{{W: []byte("AT\r"), R: []byte("OK")}}
+	c := newPipeClient(t)
+	p, err := c.OpenUART("NETUART")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := p.String(); s != "fakeUART" {
+		t.Fatal(s)
+	}
+	if err := p.LimitSpeed(9600 * physic.Hertz); err != nil {
+		t.Fatal(err)
+	}
+	u, err := p.Connect(9600*physic.Hertz, uart.One, uart.NoParity, uart.NoFlow, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := u.Duplex(); d != conn.Full {
+		t.Fatal(d)
+	}
+	if f.uart.parity != uart.NoParity || f.uart.stop != uart.One || f.uart.bits != 8 {
+		t.Fatal(f.uart)
+	}
+	r := make([]byte, 2)
+	if err := u.Tx([]byte("AT\r"), r); err != nil {
+		t.Fatal(err)
+	}
+	if string(r) != "OK" {
+		t.Fatal(string(r))
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_Pin(t *testing.T) {
+	f := registerFakes(t)
+	c := newPipeClient(t)
+	p, err := c.OpenPin("NETGPIO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := p.Name(); n != "NETGPIO" {
+		t.Fatal(n)
+	}
+	if s := p.String(); s != "NETGPIO" {
+		t.Fatal(s)
+	}
+	if n := p.Number(); n != -1 {
+		t.Fatal(n)
+	}
+	if fn := p.Function(); fn != "In/Low" {
+		t.Fatal(fn)
+	}
+	if err := p.In(gpio.PullUp, gpio.BothEdges); err != nil {
+		t.Fatal(err)
+	}
+	if l := p.Read(); l != gpio.High {
+		t.Fatal(l)
+	}
+	if v := p.Pull(); v != gpio.PullUp {
+		t.Fatal(v)
+	}
+	if v := p.DefaultPull(); v != gpio.PullUp {
+		t.Fatal(v)
+	}
+	f.pin.EdgesChan <- gpio.Low
+	if !p.WaitForEdge(-1) {
+		t.Fatal("expected edge")
+	}
+	if p.WaitForEdge(0) {
+		t.Fatal("unexpected edge")
+	}
+	if err := p.Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if f.pin.Read() != gpio.High {
+		t.Fatal("expected High")
+	}
+	if err := p.PWM(gpio.DutyHalf, physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if f.pin.D != gpio.DutyHalf || f.pin.F != physic.KiloHertz {
+		t.Fatal(f.pin.D, f.pin.F)
+	}
+	if err := p.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Failures are reported as zero values.
+	if p.Read() != gpio.Low || p.WaitForEdge(0) || p.Pull() != gpio.PullNoChange || p.DefaultPull() != gpio.PullNoChange || p.Function() != "" {
+		t.Fatal("expected zero values")
+	}
+	if _, err := c.OpenPin("unknown"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClient_Pin_WaitForEdge_interrupted(t *testing.T) {
+	registerFakes(t)
+	c := newPipeClient(t)
+	p, err := c.OpenPin("NETGPIO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := p.(*remotePin)
+	// wait starts WaitForEdge(-1) and returns once it is pending.
+	wait := func() <-chan bool {
+		done := make(chan bool)
+		go func() {
+			done <- p.WaitForEdge(-1)
+		}()
+		for {
+			r.mu.Lock()
+			n := r.waiting
+			r.mu.Unlock()
+			if n != 0 {
+				return done
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	done := wait()
+	if err := p.In(gpio.PullUp, gpio.BothEdges); err != nil {
+		t.Fatal(err)
+	}
+	if <-done {
+		t.Fatal("unexpected edge")
+	}
+	done = wait()
+	if err := p.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if <-done {
+		t.Fatal("unexpected edge")
+	}
+	done = wait()
+	if err := p.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if <-done {
+		t.Fatal("unexpected edge")
+	}
+	if p.WaitForEdge(-1) {
+		t.Fatal("unexpected edge")
+	}
+}
+
+func TestClient_Register(t *testing.T) {
+	f := registerFakes(t)
+	f.i2c.Ops = []i2ctest.IO{{Addr: 0x76, W: []byte{0xd0}}}
+	c := newPipeClient(t)
+	inv, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(inv.I2C, "NETI2C") || !contains(inv.SPI, "NETSPI") || !contains(inv.OneWire, "NET1W") || !contains(inv.UART, "NETUART") {
+		t.Fatal(inv)
+	}
+	found := false
+	for _, p := range inv.GPIO {
+		if p.Name == "NETGPIO" && p.Number == 1000 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal(inv.GPIO)
+	}
+	// The prefix avoids colliding with the objects registered for the server.
+	if err := c.Register("remote-"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = i2creg.Unregister("remote-NETI2C")
+		_ = spireg.Unregister("remote-NETSPI")
+		_ = spireg.Unregister("remote-NETSPI2")
+		_ = onewirereg.Unregister("remote-NET1W")
+		_ = uartreg.Unregister("remote-NETUART")
+		_ = gpioreg.Unregister("remote-NETGPIO")
+	})
+	b, err := i2creg.Open("remote-NETI2C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx(0x76, []byte{0xd0}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sp, err := spireg.Open("remote-NETSPI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = sp.Close()
+	ow, err := onewirereg.Open("remote-NET1W")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = ow.Close()
+	u, err := uartreg.Open("remote-NETUART")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = u.Close()
+	p := gpioreg.ByName("remote-NETGPIO")
+	if p == nil {
+		t.Fatal("pin not registered")
+	}
+	if p.Number() != 1000 || p.Name() != "remote-NETGPIO" {
+		t.Fatal(p)
+	}
+	if err := p.Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if f.pin.Read() != gpio.High {
+		t.Fatal("expected High")
+	}
+	// Registering twice fails.
+	if err := c.Register("remote-"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClient_Dial_err(t *testing.T) {
+	c := &Client{Dial: func() (net.Conn, error) { return nil, errors.New("unreachable") }}
+	if _, err := c.List(); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := c.OpenI2C("a"); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := c.OpenSPI("a"); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := c.OpenOneWire("a"); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := c.OpenUART("a"); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := c.Register("a"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestClient_ServerGone(t *testing.T) {
+	registerFakes(t)
+	var s Server
+	var server net.Conn
+	c := &Client{Dial: func() (net.Conn, error) {
+		a, b := net.Pipe()
+		server = b
+		go s.ServeConn(b)
+		return a, nil
+	}}
+	b, err := c.OpenI2C("NETI2C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = server.Close()
+	if err := b.Tx(0x76, nil, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	// Sticky.
+	if err := b.SetSpeed(physic.KiloHertz); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	registerFakes(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	s := &Server{}
+	done := make(chan error)
+	go func() {
+		done <- s.Serve(l)
+	}()
+	c := NewClient("tcp", l.Addr().String())
+	p, err := c.OpenPin("NETGPIO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Out(gpio.Low); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+//
+
+type fakes struct {
+	i2c  *i2ctest.Playback
+	spi  *spitest.Playback
+	ow   *onewiretest.Playback
+	uart *fakeUART
+	pin  *gpiotest.Pin
+}
+
+// registerFakes registers fakes in the registries for the duration of the
+// test.
+func registerFakes(t *testing.T) *fakes {
+	f := &fakes{
+		i2c:  &i2ctest.Playback{DontPanic: true},
+		spi:  &spitest.Playback{Playback: conntest.Playback{DontPanic: true}},
+		ow:   &onewiretest.Playback{DontPanic: true},
+		uart: &fakeUART{c: &conntest.Playback{DontPanic: true, D: conn.Full}},
+		pin:  &gpiotest.Pin{N: "NETGPIO", Num: 1000, Fn: "In/Low", EdgesChan: make(chan gpio.Level, 1)},
+	}
+	// A second SPI port that supports TxPackets.
+	spi2 := &fakeSPIPort{}
+	mustOK(t, i2creg.Register("NETI2C", nil, -1, func() (i2c.BusCloser, error) { return f.i2c, nil }))
+	mustOK(t, spireg.Register("NETSPI", nil, -1, func() (spi.PortCloser, error) { return f.spi, nil }))
+	mustOK(t, spireg.Register("NETSPI2", nil, -1, func() (spi.PortCloser, error) { return spi2, nil }))
+	mustOK(t, onewirereg.Register("NET1W", nil, -1, func() (onewire.BusCloser, error) { return f.ow, nil }))
+	mustOK(t, uartreg.Register("NETUART", nil, -1, func() (uart.PortCloser, error) { return f.uart, nil }))
+	mustOK(t, gpioreg.Register(f.pin))
+	t.Cleanup(func() {
+		_ = i2creg.Unregister("NETI2C")
+		_ = spireg.Unregister("NETSPI")
+		_ = spireg.Unregister("NETSPI2")
+		_ = onewirereg.Unregister("NET1W")
+		_ = uartreg.Unregister("NETUART")
+		_ = gpioreg.Unregister("NETGPIO")
+	})
+	return f
+}
+
+// newPipeClient returns a Client connected to a Server over in-memory pipes.
+func newPipeClient(t *testing.T) *Client {
+	s := &Server{}
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	return &Client{Dial: func() (net.Conn, error) {
+		a, b := net.Pipe()
+		go s.ServeConn(b)
+		return a, nil
+	}}
+}
+
+func mustOK(t *testing.T, err error) {
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeUART struct {
+	c      *conntest.Playback
+	stop   uart.Stop
+	parity uart.Parity
+	bits   int
+}
+
+func (f *fakeUART) String() string {
+	return "fakeUART"
+}
+
+func (f *fakeUART) Close() error {
+	return nil
+}
+
+func (f *fakeUART) LimitSpeed(freq physic.Frequency) error {
+	return nil
+}
+
+func (f *fakeUART) Connect(freq physic.Frequency, stopBit uart.Stop, parity uart.Parity, flow uart.Flow, bits int) (conn.Conn, error) {
+	f.stop = stopBit
+	f.parity = parity
+	f.bits = bits
+	return f.c, nil
+}
+
+// fakeSPIPort returns 0xAA on every read.
+type fakeSPIPort struct{}
+
+func (f *fakeSPIPort) String() string {
+	return "fakeSPIPort"
+}
+
+func (f *fakeSPIPort) Close() error {
+	return nil
+}
+
+func (f *fakeSPIPort) LimitSpeed(freq physic.Frequency) error {
+	return nil
+}
+
+func (f *fakeSPIPort) Connect(freq physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	return f, nil
+}
+
+func (f *fakeSPIPort) Tx(w, r []byte) error {
+	for i := range r {
+		r[i] = 0xaa
+	}
+	return nil
+}
+
+func (f *fakeSPIPort) TxPackets(p []spi.Packet) error {
+	for i := range p {
+		_ = f.Tx(p[i].W, p[i].R)
+	}
+	return nil
+}
+
+func (f *fakeSPIPort) Duplex() conn.Duplex {
+	return conn.Full
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package netconn exposes the buses and pins of the registries over a network
+// socket.
+//
+// A Server runs on the host with the hardware attached. It serves the I²C,
+// SPI and 1-wire buses, the UART ports and the GPIO pins found in i2creg,
+// spireg, onewirereg, uartreg and gpioreg. A Client runs on the other end
+// and returns objects that implement the usual interfaces, so device drivers
+// can be used unmodified. Client.Register() adds all the remote objects to
+// the local registries.
+//
+// Each remote object uses its own stream connection, so a blocking call like
+// gpio.PinIn.WaitForEdge() on one pin doesn't hold up the others. Calls on a
+// single object are serialized, except WaitForEdge() which uses a second
+// stream so In(), Halt() and Close() can interrupt it. The underlying bus or
+// port is closed on the server when the client closes the object or
+// disconnects.
+//
+// # Protocol
+//
+// Every message is a frame made of a big endian uint32 length followed by
+// that many bytes. The first byte of a request is the operation, the first
+// byte of a response is 0 on success or 1 on failure. A failure carries the
+// conn well known error class as a byte and the error message, so
+// errors.Is(err, conn.ErrAddrNACK) works across the network.
+//
+// A stream starts with either a list request, to enumerate the objects, or
+// an open request that binds the stream to an object for its whole
+// lifetime. Integers are big endian, byte slices and strings are prefixed
+// with their uint32 length.
+//
+// There is no authentication nor encryption; only listen on trusted
+// networks or on a Unix socket.
+package netconn
@@ -0,0 +1,266 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package netconn
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+
+	"periph.io/x/conn/v3"
+)
+
+// version is the protocol version sent in every open request.
+const version = 1
+
+// maxFrame is the largest frame accepted, to bound the memory allocated for
+// a corrupted or hostile stream.
+const maxFrame = 16 << 20
+
+// kind is the type of a remote object.
+type kind uint8
+
+const (
+	kindI2C kind = iota + 1
+	kindSPI
+	kindOneWire
+	kindUART
+	kindGPIO
+)
+
+// op is a request operation.
+type op uint8
+
+const (
+	// Valid before an object is bound to the stream.
+	opList op = iota + 1 // → I2C, SPI, OneWire, UART names; GPIO (name, number)
+	opOpen               // version, kind, name → String()
+
+	// Valid once an object is bound.
+	opHalt           // (gpio)
+	opSetSpeed       // f (i2c SetSpeed, spi and uart LimitSpeed)
+	opI2CTx          // addr, w, len(r) → r
+	opSPIConnect     // f, mode, bits → duplex
+	opSPITxPackets   // n, n*(w, len(r), bits, keepCS) → n*r
+	opUARTConnect    // f, stop, parity, flow, bits → duplex
+	opConnTx         // w, len(r) → r (spi and uart)
+	opOneWireTx      // w, len(r), power → r
+	opOneWireSearch  // alarmOnly → n*address
+	opPinIn          // pull, edge
+	opPinRead        // → level
+	opPinWaitForEdge // timeout → bool
+	opPinPull        // → pull
+	opPinDefaultPull // → pull
+	opPinOut         // level
+	opPinPWM         // duty, f
+	opPinFunction    // → Function()
+)
+
+const (
+	statusOK  = 0
+	statusErr = 1
+)
+
+// classes are the well known errors that survive the trip across the
+// network. The index is the wire value; 0 means none.
+var classes = [...]error{
+	nil,
+	conn.ErrAddrNACK,
+	conn.ErrDataNACK,
+	conn.ErrArbitrationLost,
+	conn.ErrTimeout,
+	conn.ErrBusBusy,
+	conn.ErrCRC,
+	conn.ErrParity,
+}
+
+// remoteError is an error returned by the server.
+type remoteError struct {
+	msg   string
+	class error
+}
+
+func (r *remoteError) Error() string {
+	return r.msg
+}
+
+// Unwrap returns the well known error the server reported, if any.
+func (r *remoteError) Unwrap() error {
+	return r.class
+}
+
+// errorClass returns the wire value for err.
+func errorClass(err error) uint8 {
+	for i := 1; i < len(classes); i++ {
+		if errors.Is(err, classes[i]) {
+			return uint8(i)
+		}
+	}
+	return 0
+}
+
+// encoder serializes a frame.
+//
+// The first 4 bytes are reserved for the frame length.
+type encoder struct {
+	b []byte
+}
+
+func newEncoder(first uint8) *encoder {
+	return &encoder{b: append(make([]byte, 4, 64), first)}
+}
+
+func (e *encoder) u8(v uint8) {
+	e.b = append(e.b, v)
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.u8(1)
+	} else {
+		e.u8(0)
+	}
+}
+
+func (e *encoder) u16(v uint16) {
+	e.b = binary.BigEndian.AppendUint16(e.b, v)
+}
+
+func (e *encoder) u32(v uint32) {
+	e.b = binary.BigEndian.AppendUint32(e.b, v)
+}
+
+func (e *encoder) u64(v uint64) {
+	e.b = binary.BigEndian.AppendUint64(e.b, v)
+}
+
+func (e *encoder) bytes(v []byte) {
+	e.u32(uint32(len(v)))
+	e.b = append(e.b, v...)
+}
+
+func (e *encoder) string(v string) {
+	e.u32(uint32(len(v)))
+	e.b = append(e.b, v...)
+}
+
+// send writes the frame to w.
+func (e *encoder) send(w io.Writer) error {
+	binary.BigEndian.PutUint32(e.b, uint32(len(e.b)-4))
+	_, err := w.Write(e.b)
+	return err
+}
+
+// decoder deserializes a frame.
+//
+// The first decoding error is sticky, so a sequence of reads can be checked
+// once at the end.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.b) {
+		d.err = errShortFrame
+		d.b = nil
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) u8() uint8 {
+	if b := d.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *decoder) bool() bool {
+	return d.u8() != 0
+}
+
+func (d *decoder) u16() uint16 {
+	if b := d.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (d *decoder) u32() uint32 {
+	if b := d.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *decoder) u64() uint64 {
+	if b := d.next(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// bytes returns a slice aliasing the frame, or nil if empty.
+func (d *decoder) bytes() []byte {
+	if b := d.next(int(d.u32())); len(b) != 0 {
+		return b
+	}
+	return nil
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+// buffer decodes a length and returns a buffer of this size, or nil if
+// empty.
+func (d *decoder) buffer() []byte {
+	if n := d.length(); n != 0 {
+		return make([]byte, n)
+	}
+	return nil
+}
+
+// length decodes a count, bounded by maxFrame.
+func (d *decoder) length() int {
+	n := d.u32()
+	if n > maxFrame && d.err == nil {
+		d.err = errFrameSize
+		return 0
+	}
+	return int(n)
+}
+
+// readFrame reads a single frame from r.
+func readFrame(r io.Reader) (*decoder, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:])
+	if n > maxFrame {
+		return nil, errFrameSize
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return &decoder{b: b}, nil
+}
+
+var (
+	errShortFrame = errors.New("netconn: truncated frame")
+	errFrameSize  = errors.New("netconn: frame too large (limit " + strconv.Itoa(maxFrame) + " bytes)")
+)
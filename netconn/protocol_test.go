@@ -0,0 +1,123 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package netconn
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"periph.io/x/conn/v3"
+)
+
+func TestEncoderDecoder(t *testing.T) {
+	e := newEncoder(42)
+	e.u8(1)
+	e.bool(true)
+	e.bool(false)
+	e.u16(0x1234)
+	e.u32(0x12345678)
+	e.u64(0x123456789abcdef0)
+	e.bytes([]byte{1, 2})
+	e.bytes(nil)
+	e.string("hi")
+	var buf bytes.Buffer
+	if err := e.send(&buf); err != nil {
+		t.Fatal(err)
+	}
+	d, err := readFrame(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := d.u8(); v != 42 {
+		t.Fatal(v)
+	}
+	if v := d.u8(); v != 1 {
+		t.Fatal(v)
+	}
+	if !d.bool() || d.bool() {
+		t.Fatal("bool")
+	}
+	if v := d.u16(); v != 0x1234 {
+		t.Fatal(v)
+	}
+	if v := d.u32(); v != 0x12345678 {
+		t.Fatal(v)
+	}
+	if v := d.u64(); v != 0x123456789abcdef0 {
+		t.Fatal(v)
+	}
+	if v := d.bytes(); !bytes.Equal(v, []byte{1, 2}) {
+		t.Fatal(v)
+	}
+	if v := d.bytes(); v != nil {
+		t.Fatal(v)
+	}
+	if v := d.string(); v != "hi" {
+		t.Fatal(v)
+	}
+	if d.err != nil || len(d.b) != 0 {
+		t.Fatal(d.err, d.b)
+	}
+	// Sticky error.
+	if v := d.u32(); v != 0 || d.err != errShortFrame {
+		t.Fatal(v, d.err)
+	}
+	if v := d.u8(); v != 0 {
+		t.Fatal(v)
+	}
+	if v := d.u16(); v != 0 {
+		t.Fatal(v)
+	}
+	if v := d.u64(); v != 0 {
+		t.Fatal(v)
+	}
+}
+
+func TestDecoder_length(t *testing.T) {
+	e := newEncoder(0)
+	e.u32(maxFrame + 1)
+	e.u32(0)
+	d := &decoder{b: e.b[5:]}
+	if n := d.length(); n != 0 || d.err != errFrameSize {
+		t.Fatal(n, d.err)
+	}
+	d = &decoder{b: e.b[9:]}
+	if b := d.buffer(); b != nil || d.err != nil {
+		t.Fatal(b, d.err)
+	}
+}
+
+func TestReadFrame_err(t *testing.T) {
+	if _, err := readFrame(bytes.NewReader(nil)); err != io.EOF {
+		t.Fatal(err)
+	}
+	if _, err := readFrame(bytes.NewReader([]byte{0, 0, 0, 2, 1})); err != io.ErrUnexpectedEOF {
+		t.Fatal(err)
+	}
+	if _, err := readFrame(bytes.NewReader([]byte{0, 0, 0, 2})); err != io.ErrUnexpectedEOF {
+		t.Fatal(err)
+	}
+	if _, err := readFrame(bytes.NewReader([]byte{0xff, 0, 0, 0})); err != errFrameSize {
+		t.Fatal(err)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	for i := 1; i < len(classes); i++ {
+		if c := errorClass(fmt.Errorf("wrapped: %w", classes[i])); c != uint8(i) {
+			t.Fatal(i, c)
+		}
+	}
+	if c := errorClass(errors.New("other")); c != 0 {
+		t.Fatal(c)
+	}
+	r := &remoteError{msg: "foo", class: conn.ErrCRC}
+	if r.Error() != "foo" || !errors.Is(r, conn.ErrCRC) {
+		t.Fatal(r)
+	}
+}
@@ -0,0 +1,564 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package netconn
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewirereg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/conn/v3/uart"
+	"periph.io/x/conn/v3/uart/uartreg"
+)
+
+// Server serves the objects of the registries.
+//
+// The zero value is ready to use.
+type Server struct {
+	mu        sync.Mutex
+	closed    bool
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	wg        sync.WaitGroup
+}
+
+// Serve accepts connections on l and serves each of them on its own
+// goroutine.
+//
+// It returns nil after Close() is called, otherwise the error returned by
+// l.Accept().
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errServerClosed
+	}
+	if s.listeners == nil {
+		s.listeners = map[net.Listener]struct{}{}
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			delete(s.listeners, l)
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			_ = s.ServeConn(c)
+		}()
+	}
+}
+
+// ServeConn serves a single stream connection and closes it when done.
+//
+// It returns nil when the client disconnects cleanly.
+func (s *Server) ServeConn(c net.Conn) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = c.Close()
+		return errServerClosed
+	}
+	if s.conns == nil {
+		s.conns = map[net.Conn]struct{}{}
+	}
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+	// Frames are read on their own goroutine, so a blocking operation stops
+	// when the client disconnects.
+	frames := make(chan *decoder)
+	gone := make(chan struct{})
+	stop := make(chan struct{})
+	var rerr error
+	go func() {
+		defer close(gone)
+		for {
+			d, err := readFrame(c)
+			if err != nil {
+				rerr = err
+				return
+			}
+			select {
+			case frames <- d:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	defer func() {
+		close(stop)
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		_ = c.Close()
+		<-gone
+	}()
+	ss := session{gone: gone}
+	defer ss.close()
+	for {
+		var d *decoder
+		select {
+		case d = <-frames:
+		case <-gone:
+			if rerr == io.EOF {
+				return nil
+			}
+			return rerr
+		}
+		e, err := ss.handle(d)
+		if err != nil {
+			e = newEncoder(statusErr)
+			e.u8(errorClass(err))
+			e.string(err.Error())
+		}
+		if err := e.send(c); err != nil {
+			return err
+		}
+	}
+}
+
+// Close stops all the listeners and closes all the connections, then waits
+// for the connections served by Serve() to terminate.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	for l := range s.listeners {
+		if err2 := l.Close(); err == nil {
+			err = err2
+		}
+	}
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+//
+
+var errServerClosed = errors.New("netconn: server closed")
+
+// session is the server side state of a stream.
+//
+// At most one of the object fields is set, once an open request succeeded.
+type session struct {
+	// gone is closed when the connection is closed.
+	gone <-chan struct{}
+
+	i2c     i2c.BusCloser
+	spi     spi.PortCloser
+	spiConn spi.Conn
+	ow      onewire.BusCloser
+	uart    uart.PortCloser
+	conn    conn.Conn
+	pin     gpio.PinIO
+}
+
+// waitSlice is the longest a pin is waited on before checking if the
+// connection is still alive.
+const waitSlice = 100 * time.Millisecond
+
+func (s *session) close() {
+	switch {
+	case s.i2c != nil:
+		_ = s.i2c.Close()
+	case s.spi != nil:
+		_ = s.spi.Close()
+	case s.ow != nil:
+		_ = s.ow.Close()
+	case s.uart != nil:
+		_ = s.uart.Close()
+	}
+}
+
+func (s *session) bound() bool {
+	return s.i2c != nil || s.spi != nil || s.ow != nil || s.uart != nil || s.pin != nil
+}
+
+// handle processes a request and returns the response to send.
+func (s *session) handle(d *decoder) (*encoder, error) {
+	o := op(d.u8())
+	e := newEncoder(statusOK)
+	var err error
+	if !s.bound() {
+		switch o {
+		case opList:
+			s.list(e)
+		case opOpen:
+			err = s.open(d, e)
+		default:
+			err = errors.New("netconn: operation " + strconv.Itoa(int(o)) + " requires an open object")
+		}
+	} else {
+		err = s.call(o, d, e)
+	}
+	if err == nil && d.err != nil {
+		err = d.err
+	}
+	return e, err
+}
+
+func (s *session) list(e *encoder) {
+	refs := i2creg.All()
+	e.u32(uint32(len(refs)))
+	for _, r := range refs {
+		e.string(r.Name)
+	}
+	spiRefs := spireg.All()
+	e.u32(uint32(len(spiRefs)))
+	for _, r := range spiRefs {
+		e.string(r.Name)
+	}
+	owRefs := onewirereg.All()
+	e.u32(uint32(len(owRefs)))
+	for _, r := range owRefs {
+		e.string(r.Name)
+	}
+	uartRefs := uartreg.All()
+	e.u32(uint32(len(uartRefs)))
+	for _, r := range uartRefs {
+		e.string(r.Name)
+	}
+	pins := gpioreg.All()
+	e.u32(uint32(len(pins)))
+	for _, p := range pins {
+		e.string(p.Name())
+		e.u32(uint32(int32(p.Number())))
+	}
+}
+
+func (s *session) open(d *decoder, e *encoder) error {
+	v := d.u8()
+	k := kind(d.u8())
+	name := d.string()
+	if d.err != nil {
+		return d.err
+	}
+	if v != version {
+		return errors.New("netconn: unsupported protocol version " + strconv.Itoa(int(v)))
+	}
+	var err error
+	var str string
+	switch k {
+	case kindI2C:
+		if s.i2c, err = i2creg.Open(name); err == nil {
+			str = s.i2c.String()
+		}
+	case kindSPI:
+		if s.spi, err = spireg.Open(name); err == nil {
+			str = s.spi.String()
+		}
+	case kindOneWire:
+		if s.ow, err = onewirereg.Open(name); err == nil {
+			str = s.ow.String()
+		}
+	case kindUART:
+		if s.uart, err = uartreg.Open(name); err == nil {
+			str = s.uart.String()
+		}
+	case kindGPIO:
+		if s.pin = gpioreg.ByName(name); s.pin == nil {
+			err = errors.New("netconn: unknown pin " + strconv.Quote(name))
+		} else {
+			str = s.pin.String()
+		}
+	default:
+		err = errors.New("netconn: unknown object kind " + strconv.Itoa(int(k)))
+	}
+	if err != nil {
+		return err
+	}
+	e.string(str)
+	return nil
+}
+
+// call processes a request on the bound object.
+func (s *session) call(o op, d *decoder, e *encoder) error {
+	switch {
+	case s.i2c != nil:
+		return s.callI2C(o, d, e)
+	case s.spi != nil:
+		return s.callSPI(o, d, e)
+	case s.ow != nil:
+		return s.callOneWire(o, d, e)
+	case s.uart != nil:
+		return s.callUART(o, d, e)
+	default:
+		return s.callPin(o, d, e)
+	}
+}
+
+func (s *session) callI2C(o op, d *decoder, e *encoder) error {
+	switch o {
+	case opI2CTx:
+		addr := d.u16()
+		w := d.bytes()
+		r := d.buffer()
+		if d.err != nil {
+			return d.err
+		}
+		if err := s.i2c.Tx(addr, w, r); err != nil {
+			return err
+		}
+		e.bytes(r)
+		return nil
+	case opSetSpeed:
+		f := physic.Frequency(d.u64())
+		if d.err != nil {
+			return d.err
+		}
+		return s.i2c.SetSpeed(f)
+	default:
+		return errUnsupported(o)
+	}
+}
+
+func (s *session) callSPI(o op, d *decoder, e *encoder) error {
+	switch o {
+	case opSetSpeed:
+		f := physic.Frequency(d.u64())
+		if d.err != nil {
+			return d.err
+		}
+		return s.spi.LimitSpeed(f)
+	case opSPIConnect:
+		f := physic.Frequency(d.u64())
+		mode := spi.Mode(d.u32())
+		bits := int(d.u32())
+		if d.err != nil {
+			return d.err
+		}
+		c, err := s.spi.Connect(f, mode, bits)
+		if err != nil {
+			return err
+		}
+		s.spiConn = c
+		e.u8(uint8(c.Duplex()))
+		return nil
+	case opConnTx:
+		if s.spiConn == nil {
+			return errNotConnected
+		}
+		return connTx(s.spiConn, d, e)
+	case opSPITxPackets:
+		if s.spiConn == nil {
+			return errNotConnected
+		}
+		n := d.length()
+		p := make([]spi.Packet, 0, min(n, 1024))
+		// The read buffers are bounded as a whole, like a single frame.
+		total := 0
+		for i := 0; i < n && d.err == nil; i++ {
+			pkt := spi.Packet{W: d.bytes()}
+			l := d.length()
+			if total += l; total > maxFrame {
+				return errFrameSize
+			}
+			if l != 0 {
+				pkt.R = make([]byte, l)
+			}
+			pkt.BitsPerWord = d.u8()
+			pkt.KeepCS = d.bool()
+			p = append(p, pkt)
+		}
+		if d.err != nil {
+			return d.err
+		}
+		if err := s.spiConn.TxPackets(p); err != nil {
+			return err
+		}
+		for i := range p {
+			e.bytes(p[i].R)
+		}
+		return nil
+	default:
+		return errUnsupported(o)
+	}
+}
+
+func (s *session) callOneWire(o op, d *decoder, e *encoder) error {
+	switch o {
+	case opOneWireTx:
+		w := d.bytes()
+		r := d.buffer()
+		power := onewire.Pullup(d.bool())
+		if d.err != nil {
+			return d.err
+		}
+		if err := s.ow.Tx(w, r, power); err != nil {
+			return err
+		}
+		e.bytes(r)
+		return nil
+	case opOneWireSearch:
+		alarmOnly := d.bool()
+		if d.err != nil {
+			return d.err
+		}
+		addrs, err := s.ow.Search(alarmOnly)
+		if err != nil {
+			return err
+		}
+		e.u32(uint32(len(addrs)))
+		for _, a := range addrs {
+			e.u64(uint64(a))
+		}
+		return nil
+	default:
+		return errUnsupported(o)
+	}
+}
+
+func (s *session) callUART(o op, d *decoder, e *encoder) error {
+	switch o {
+	case opSetSpeed:
+		f := physic.Frequency(d.u64())
+		if d.err != nil {
+			return d.err
+		}
+		return s.uart.LimitSpeed(f)
+	case opUARTConnect:
+		f := physic.Frequency(d.u64())
+		stop := uart.Stop(int8(d.u8()))
+		parity := uart.Parity(d.u8())
+		flow := uart.Flow(d.u32())
+		bits := int(d.u32())
+		if d.err != nil {
+			return d.err
+		}
+		c, err := s.uart.Connect(f, stop, parity, flow, bits)
+		if err != nil {
+			return err
+		}
+		s.conn = c
+		e.u8(uint8(c.Duplex()))
+		return nil
+	case opConnTx:
+		if s.conn == nil {
+			return errNotConnected
+		}
+		return connTx(s.conn, d, e)
+	default:
+		return errUnsupported(o)
+	}
+}
+
+func (s *session) callPin(o op, d *decoder, e *encoder) error {
+	switch o {
+	case opHalt:
+		return s.pin.Halt()
+	case opPinIn:
+		pull := gpio.Pull(d.u8())
+		edge := gpio.Edge(d.u8())
+		if d.err != nil {
+			return d.err
+		}
+		return s.pin.In(pull, edge)
+	case opPinRead:
+		e.bool(bool(s.pin.Read()))
+	case opPinWaitForEdge:
+		timeout := time.Duration(d.u64())
+		if d.err != nil {
+			return d.err
+		}
+		e.bool(s.waitForEdge(timeout))
+	case opPinPull:
+		e.u8(uint8(s.pin.Pull()))
+	case opPinDefaultPull:
+		e.u8(uint8(s.pin.DefaultPull()))
+	case opPinOut:
+		l := gpio.Level(d.bool())
+		if d.err != nil {
+			return d.err
+		}
+		return s.pin.Out(l)
+	case opPinPWM:
+		duty := gpio.Duty(int32(d.u32()))
+		f := physic.Frequency(d.u64())
+		if d.err != nil {
+			return d.err
+		}
+		return s.pin.PWM(duty, f)
+	case opPinFunction:
+		e.string(s.pin.Function())
+	default:
+		return errUnsupported(o)
+	}
+	return nil
+}
+
+// waitForEdge calls WaitForEdge() in slices of at most waitSlice, to stop
+// waiting when the connection is closed, including by Server.Close().
+//
+// A slice that ends early without an edge means In() was called, so the
+// wait ends.
+func (s *session) waitForEdge(timeout time.Duration) bool {
+	for {
+		t := waitSlice
+		if timeout >= 0 && timeout < t {
+			t = timeout
+		}
+		start := time.Now()
+		if s.pin.WaitForEdge(t) {
+			return true
+		}
+		if time.Since(start) < t {
+			return false
+		}
+		if timeout >= 0 {
+			if timeout -= t; timeout <= 0 {
+				return false
+			}
+		}
+		select {
+		case <-s.gone:
+			return false
+		default:
+		}
+	}
+}
+
+func connTx(c conn.Conn, d *decoder, e *encoder) error {
+	w := d.bytes()
+	r := d.buffer()
+	if d.err != nil {
+		return d.err
+	}
+	if err := c.Tx(w, r); err != nil {
+		return err
+	}
+	e.bytes(r)
+	return nil
+}
+
+var errNotConnected = errors.New("netconn: Connect() must be called first")
+
+func errUnsupported(o op) error {
+	return errors.New("netconn: unsupported operation " + strconv.Itoa(int(o)) + " for this object")
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package netconn
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+func TestServer_errors(t *testing.T) {
+	registerFakes(t)
+	data := []struct {
+		name     string
+		reqs     []*encoder
+		expected string
+	}{
+		{
+			"not open",
+			[]*encoder{newEncoder(uint8(opI2CTx))},
+			"netconn: operation 5 requires an open object",
+		},
+		{
+			"version",
+			[]*encoder{openReq(version+1, kindI2C, "NETI2C")},
+			"netconn: unsupported protocol version 2",
+		},
+		{
+			"kind",
+			[]*encoder{openReq(version, 42, "NETI2C")},
+			"netconn: unknown object kind 42",
+		},
+		{
+			"truncated open",
+			[]*encoder{newEncoder(uint8(opOpen))},
+			"netconn: truncated frame",
+		},
+		{
+			"truncated tx",
+			[]*encoder{openReq(version, kindI2C, "NETI2C"), newEncoder(uint8(opI2CTx))},
+			"netconn: truncated frame",
+		},
+		{
+			"truncated speed",
+			[]*encoder{openReq(version, kindI2C, "NETI2C"), newEncoder(uint8(opSetSpeed))},
+			"netconn: truncated frame",
+		},
+		{
+			"unsupported",
+			[]*encoder{openReq(version, kindI2C, "NETI2C"), newEncoder(uint8(opPinRead))},
+			"netconn: unsupported operation 13 for this object",
+		},
+		{
+			"spi not connected",
+			[]*encoder{openReq(version, kindSPI, "NETSPI2"), newEncoder(uint8(opConnTx))},
+			"netconn: Connect() must be called first",
+		},
+		{
+			"spi packets not connected",
+			[]*encoder{openReq(version, kindSPI, "NETSPI2"), newEncoder(uint8(opSPITxPackets))},
+			"netconn: Connect() must be called first",
+		},
+		{
+			"uart not connected",
+			[]*encoder{openReq(version, kindUART, "NETUART"), newEncoder(uint8(opConnTx))},
+			"netconn: Connect() must be called first",
+		},
+		{
+			"onewire unsupported",
+			[]*encoder{openReq(version, kindOneWire, "NET1W"), newEncoder(uint8(opSetSpeed))},
+			"netconn: unsupported operation 4 for this object",
+		},
+		{
+			"pin unsupported",
+			[]*encoder{openReq(version, kindGPIO, "NETGPIO"), newEncoder(uint8(opI2CTx))},
+			"netconn: unsupported operation 5 for this object",
+		},
+		{
+			"spi packets too large",
+			[]*encoder{openReq(version, kindSPI, "NETSPI2"), connectReq(), packetsReq(2, maxFrame/2+1)},
+			errFrameSize.Error(),
+		},
+		{
+			"pin truncated",
+			[]*encoder{openReq(version, kindGPIO, "NETGPIO"), newEncoder(uint8(opPinPWM))},
+			"netconn: truncated frame",
+		},
+	}
+	for _, line := range data {
+		t.Run(line.name, func(t *testing.T) {
+			var s Server
+			a, b := net.Pipe()
+			done := make(chan error)
+			go func() {
+				done <- s.ServeConn(b)
+			}()
+			st := &stream{conn: a}
+			var err error
+			for _, e := range line.reqs {
+				if _, err = st.roundTripLocked(e); err != nil {
+					break
+				}
+			}
+			if err == nil || err.Error() != line.expected {
+				t.Fatalf("got %v; expected %q", err, line.expected)
+			}
+			_ = a.Close()
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestServer_frame_err(t *testing.T) {
+	var s Server
+	a, b := net.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- s.ServeConn(b)
+	}()
+	if _, err := a.Write([]byte{0xff, 0xff, 0xff, 0xff}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != errFrameSize {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_Close(t *testing.T) {
+	var s Server
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Serve(nil); err != errServerClosed {
+		t.Fatal(err)
+	}
+	a, b := net.Pipe()
+	defer a.Close()
+	if err := s.ServeConn(b); err != errServerClosed {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_Close_WaitForEdge(t *testing.T) {
+	registerFakes(t)
+	var s Server
+	a, b := net.Pipe()
+	defer a.Close()
+	done := make(chan error)
+	go func() {
+		done <- s.ServeConn(b)
+	}()
+	st := &stream{conn: a}
+	if _, err := st.roundTripLocked(openReq(version, kindGPIO, "NETGPIO")); err != nil {
+		t.Fatal(err)
+	}
+	timeout := -time.Nanosecond
+	e := newEncoder(uint8(opPinWaitForEdge))
+	e.u64(uint64(timeout))
+	if err := e.send(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestServer_Serve_err(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	_ = l.Close()
+	var s Server
+	if err := s.Serve(l); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Fatal(err)
+	}
+}
+
+//
+
+func openReq(v uint8, k kind, name string) *encoder {
+	e := newEncoder(uint8(opOpen))
+	e.u8(v)
+	e.u8(uint8(k))
+	e.string(name)
+	return e
+}
+
+func connectReq() *encoder {
+	e := newEncoder(uint8(opSPIConnect))
+	e.u64(uint64(physic.MegaHertz))
+	e.u32(uint32(spi.Mode0))
+	e.u32(8)
+	return e
+}
+
+// packetsReq returns a TxPackets request of n packets reading l bytes each.
+func packetsReq(n, l int) *encoder {
+	e := newEncoder(uint8(opSPITxPackets))
+	e.u32(uint32(n))
+	for i := 0; i < n; i++ {
+		e.bytes(nil)
+		e.u32(uint32(l))
+		e.u8(8)
+		e.bool(false)
+	}
+	return e
+}
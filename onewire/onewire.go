@@ -25,6 +25,7 @@
 package onewire
 
 import (
+	"context"
 	"strconv"
 
 	"periph.io/x/conn/v3"
@@ -60,6 +61,17 @@ type Bus interface {
 	Search(alarmOnly bool) ([]Address, error)
 }
 
+// BusContext is a 1-wire bus that supports cancellation and deadlines.
+//
+// It is expected but not required that an implementer of Bus also implement
+// BusContext. Dev.TxContext() falls back to conn.DoContext() otherwise.
+type BusContext interface {
+	Bus
+	// TxContext performs a bus transaction like Tx, except that it returns
+	// ctx.Err() as soon as ctx is done.
+	TxContext(ctx context.Context, w, r []byte, power Pullup) error
+}
+
 // Address represents a 1-wire device address in little-endian format.
 //
 // This means that the family code ends up in the lower byte, the CRC in the
@@ -105,30 +117,56 @@ type Pins interface {
 
 // NoDevicesError is an interface that should be implemented by errors that
 // indicate that no devices responded with a presence pulse after a reset.
+//
+// Errors that implement NoDevicesError should also match conn.ErrAddrNACK
+// with errors.Is() and, like it, be temporary.
 type NoDevicesError interface {
 	NoDevices() bool // true if no presence pulse from any device has been detected
 }
 
-// noDevicesError implements error and NoDevicesError.
+// noDevicesError implements error, NoDevicesError and conn.TemporaryError.
 type noDevicesError string
 
-func (e noDevicesError) Error() string   { return string(e) }
-func (e noDevicesError) NoDevices() bool { return true }
+func (e noDevicesError) Error() string        { return string(e) }
+func (e noDevicesError) NoDevices() bool      { return true }
+func (e noDevicesError) Temporary() bool      { return true }
+func (e noDevicesError) Is(target error) bool { return target == conn.ErrAddrNACK }
+
+// NewNoDevicesError returns an error with the message msg that implements
+// NoDevicesError.
+//
+// It is meant to be used by Bus implementations.
+func NewNoDevicesError(msg string) error {
+	return noDevicesError(msg)
+}
 
 // ShortedBusError is an interface that should be implemented by errors that
 // indicate that the bus is electrically shorted (Q connected to GND).
 //
-// Errors that implement ShortedBusError should also implement BusError.
+// Errors that implement ShortedBusError should also implement BusError and
+// match conn.ErrBusBusy with errors.Is(). Unlike conn.ErrBusBusy, they should
+// not be temporary: a short is a wiring fault that retrying won't fix.
 type ShortedBusError interface {
 	IsShorted() bool // true if the bus is electrically shorted
 }
 
-// shortedBusError implements error and ShortedBusError.
+// shortedBusError implements error, ShortedBusError, BusError and
+// conn.TemporaryError.
 type shortedBusError string
 
-func (e shortedBusError) Error() string   { return string(e) }
-func (e shortedBusError) IsShorted() bool { return true }
-func (e shortedBusError) BusError() bool  { return true }
+func (e shortedBusError) Error() string        { return string(e) }
+func (e shortedBusError) IsShorted() bool      { return true }
+func (e shortedBusError) BusError() bool       { return true }
+func (e shortedBusError) Temporary() bool      { return false }
+func (e shortedBusError) Is(target error) bool { return target == conn.ErrBusBusy }
+
+// NewShortedBusError returns an error with the message msg that implements
+// ShortedBusError and BusError.
+//
+// It is meant to be used by Bus implementations.
+func NewShortedBusError(msg string) error {
+	return shortedBusError(msg)
+}
 
 // BusError is an interface that should be implemented by errors that
 // indicate that an error occurred on the bus, for example a CRC error
@@ -142,15 +180,26 @@ type BusError interface {
 	BusError() bool // true if a bus error was detected
 }
 
-// busError implements error and BusError.
+// busError implements error, BusError and conn.TemporaryError.
 type busError string
 
-func (e busError) Error() string  { return string(e) }
-func (e busError) BusError() bool { return true }
+func (e busError) Error() string   { return string(e) }
+func (e busError) BusError() bool  { return true }
+func (e busError) Temporary() bool { return true }
+
+// crcError implements error, BusError and conn.TemporaryError.
+//
+// It matches conn.ErrCRC with errors.Is().
+type crcError string
+
+func (e crcError) Error() string        { return string(e) }
+func (e crcError) BusError() bool       { return true }
+func (e crcError) Temporary() bool      { return true }
+func (e crcError) Is(target error) bool { return target == conn.ErrCRC }
 
 // Dev is a device on a 1-wire bus.
 //
-// It implements conn.Conn.
+// It implements conn.Conn and conn.ConnContext.
 //
 // Compared to Bus it saves from repeatedly specifying the device address and
 // implements utility functions.
@@ -180,13 +229,15 @@ func (d *Dev) String() string {
 //
 // It's a wrapper for Dev.Bus.Tx().
 func (d *Dev) Tx(w, r []byte) error {
-	// Issue ROM match command to select the device followed by the
-	// bytes being written.
-	ww := make([]byte, 9, len(w)+9)
-	ww[0] = 0x55 // Match ROM
-	putUint64(ww[1:], d.Addr)
-	ww = append(ww, w...)
-	return d.Bus.Tx(ww, r, WeakPullup)
+	return d.Bus.Tx(d.matchROM(w), r, WeakPullup)
+}
+
+// TxContext is the same as Tx but returns ctx.Err() as soon as ctx is done.
+//
+// It's a wrapper for BusContext.TxContext() if Dev.Bus implements it.
+// Otherwise Dev.Bus.Tx() is run via conn.DoContext().
+func (d *Dev) TxContext(ctx context.Context, w, r []byte) error {
+	return d.txContext(ctx, w, r, WeakPullup)
 }
 
 // Duplex always return conn.Half for 1-wire.
@@ -201,16 +252,35 @@ func (d *Dev) Duplex() conn.Duplex {
 //
 // It's a wrapper for Dev.Bus.Tx().
 func (d *Dev) TxPower(w, r []byte) error {
-	// Issue ROM match command to select the device followed by the
-	// bytes being written.
+	return d.Bus.Tx(d.matchROM(w), r, StrongPullup)
+}
+
+// TxPowerContext is the same as TxPower but returns ctx.Err() as soon as ctx
+// is done.
+func (d *Dev) TxPowerContext(ctx context.Context, w, r []byte) error {
+	return d.txContext(ctx, w, r, StrongPullup)
+}
+
+//
+
+// matchROM returns the ROM match command to select the device followed by
+// the bytes being written.
+func (d *Dev) matchROM(w []byte) []byte {
 	ww := make([]byte, 9, len(w)+9)
 	ww[0] = 0x55 // Match ROM
 	putUint64(ww[1:], d.Addr)
-	ww = append(ww, w...)
-	return d.Bus.Tx(ww, r, StrongPullup)
+	return append(ww, w...)
 }
 
-//
+func (d *Dev) txContext(ctx context.Context, w, r []byte, power Pullup) error {
+	ww := d.matchROM(w)
+	if b, ok := d.Bus.(BusContext); ok {
+		return b.TxContext(ctx, ww, r, power)
+	}
+	return conn.DoContext(ctx, ww, r, func(w, r []byte) error {
+		return d.Bus.Tx(w, r, power)
+	})
+}
 
 // putUint64 is littleEndian.PutUint64().
 //
@@ -230,6 +300,12 @@ func putUint64(b []byte, v Address) {
 
 // Ensure that the appropriate interfaces are implemented.
 var _ conn.Conn = &Dev{}
+var _ conn.ConnContext = &Dev{}
 var _ NoDevicesError = noDevicesError("")
 var _ ShortedBusError = shortedBusError("")
 var _ BusError = busError("")
+var _ BusError = crcError("")
+var _ conn.TemporaryError = noDevicesError("")
+var _ conn.TemporaryError = shortedBusError("")
+var _ conn.TemporaryError = busError("")
+var _ conn.TemporaryError = crcError("")
@@ -6,6 +6,7 @@ package onewire
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -26,6 +27,9 @@ func TestNoDevicesError(t *testing.T) {
 	if !e.NoDevices() {
 		t.Fatal("expected NoDevices")
 	}
+	if !errors.Is(e, conn.ErrAddrNACK) || conn.IsTemporary(e) != conn.IsTemporary(conn.ErrAddrNACK) {
+		t.Fatal("expected temporary conn.ErrAddrNACK")
+	}
 	if s := e.Error(); s != "no" {
 		t.Fatal(s)
 	}
@@ -39,21 +43,52 @@ func TestShortedBusError(t *testing.T) {
 	if !e.BusError() {
 		t.Fatal("expected BusError")
 	}
+	if !errors.Is(e, conn.ErrBusBusy) || conn.IsTemporary(e) {
+		t.Fatal("expected non temporary conn.ErrBusBusy")
+	}
 	if s := e.Error(); s != "no" {
 		t.Fatal(s)
 	}
 }
 
+func TestNewErrors(t *testing.T) {
+	if e, ok := NewNoDevicesError("no").(NoDevicesError); !ok || !e.NoDevices() {
+		t.Fatal("expected NoDevicesError")
+	}
+	if e, ok := NewShortedBusError("no").(ShortedBusError); !ok || !e.IsShorted() {
+		t.Fatal("expected ShortedBusError")
+	}
+	if s := NewShortedBusError("no").Error(); s != "no" {
+		t.Fatal(s)
+	}
+}
+
 func TestBusError(t *testing.T) {
 	e := busError("no")
 	if !e.BusError() {
 		t.Fatal("expected BusError")
 	}
+	if !conn.IsTemporary(e) {
+		t.Fatal("expected temporary")
+	}
 	if s := e.Error(); s != "no" {
 		t.Fatal(s)
 	}
 }
 
+func TestCRCError(t *testing.T) {
+	var err error = crcError("no")
+	if b, ok := err.(BusError); !ok || !b.BusError() {
+		t.Fatal("expected BusError")
+	}
+	if !errors.Is(err, conn.ErrCRC) || !conn.IsTemporary(err) {
+		t.Fatal("expected temporary conn.ErrCRC")
+	}
+	if s := err.Error(); s != "no" {
+		t.Fatal(s)
+	}
+}
+
 func TestDevString(t *testing.T) {
 	d := Dev{&fakeBus{}, 12}
 	if s := d.String(); s != "fake(0x000000000000000c)" {
@@ -101,6 +136,40 @@ func TestDevTxPower(t *testing.T) {
 	}
 }
 
+func TestDevTxContext(t *testing.T) {
+	b := &fakeBus{r: []byte{1, 2, 3}}
+	d := Dev{b, 12}
+	r := make([]byte, 3)
+	if err := d.TxContext(context.Background(), []byte{3}, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{85, 12, 0, 0, 0, 0, 0, 0, 0, 3}
+	if !bytes.Equal(b.w, expected) {
+		t.Fatal(b.w)
+	}
+	if !bytes.Equal(r, []byte{1, 2, 3}) {
+		t.Fatal(r)
+	}
+	if b.power != WeakPullup {
+		t.Fatal(b.power)
+	}
+	b.r = []byte{4}
+	if err := d.TxPowerContext(context.Background(), nil, r[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if b.power != StrongPullup {
+		t.Fatal(b.power)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.TxContext(ctx, []byte{3}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := d.TxPowerContext(ctx, []byte{3}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+}
+
 //
 
 type fakeBus struct {
@@ -0,0 +1,60 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package onewiregpio_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/driver/driverreg"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewiregpio"
+	"periph.io/x/conn/v3/onewire/onewirereg"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	// TODO: Use host.Init(). It is not used in this example to prevent circular
+	// go package import.
+	if _, err := driverreg.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Make the bus available by name, like the hardware ones.
+	opener := func() (onewire.BusCloser, error) {
+		q := gpioreg.ByName("GPIO4")
+		if q == nil {
+			return nil, fmt.Errorf("failed to find the pin")
+		}
+		return onewiregpio.New(q)
+	}
+	if err := onewirereg.Register("ONEWIREGPIO", nil, -1, opener); err != nil {
+		log.Fatal(err)
+	}
+
+	b, err := onewirereg.Open("ONEWIREGPIO")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer b.Close()
+
+	addrs, err := b.Search(false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, a := range addrs {
+		// Start a temperature conversion on each DS18B20, powering it through
+		// the pin.
+		if a&0xFF != 0x28 {
+			continue
+		}
+		d := onewire.Dev{Bus: b, Addr: a}
+		if err := d.TxPower([]byte{0x44}, nil); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s\n", &d)
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package onewiregpio implements a 1-wire bus master in software over a
+// single GPIO pin.
+//
+// It is useful to attach devices like the DS18B20 to any pin, without a
+// DS2482 bridge or a kernel driver.
+//
+// The line is idle High. The devices, and parasite powered ones in
+// particular, need a 4.7kΩ resistor to the supply; the pin only pulls the
+// line Low with Out(Low) and releases it with In(PullUp).
+//
+// The time slots are a few µs long; they are generated with busy loops. A
+// preemption of the process during a slot may corrupt the transaction, which
+// is usually caught by the CRC of the devices.
+package onewiregpio
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/internal/bitbang"
+	"periph.io/x/conn/v3/onewire"
+)
+
+// New returns a 1-wire bus over the pin q.
+func New(q gpio.PinIO) (*Bus, error) {
+	b := &Bus{q: q, delay: bitbang.Delay}
+	if err := b.release(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Bus is a 1-wire bus master bit-banged over a GPIO pin at standard speed.
+//
+// A transaction that ends with onewire.StrongPullup drives the pin High,
+// which powers parasite powered devices during a temperature conversion or
+// an EEPROM write. The line is released at the start of the next transaction
+// or by Close().
+type Bus struct {
+	// Immutable.
+	q     gpio.PinIO
+	delay func(d time.Duration)
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (b *Bus) String() string {
+	return "onewiregpio(" + b.q.String() + ")"
+}
+
+// Tx implements onewire.Bus.
+//
+// It sends a reset pulse, writes w, reads r and leaves the line released or
+// driven High, depending on power.
+//
+// It returns an error implementing onewire.NoDevicesError if no device
+// answered the reset and one implementing onewire.ShortedBusError if the line
+// is held Low.
+func (b *Bus) Tx(w, r []byte, power onewire.Pullup) error {
+	return b.TxContext(context.Background(), w, r, power)
+}
+
+// TxContext implements onewire.BusContext.
+//
+// ctx is checked between each byte. When it is done, the line is released and
+// ctx.Err() is returned.
+func (b *Bus) TxContext(ctx context.Context, w, r []byte, power onewire.Pullup) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := b.tx(ctx, w, r)
+	if err == nil && power == onewire.StrongPullup {
+		err = b.q.Out(gpio.High)
+	}
+	if err != nil {
+		_ = b.release()
+	}
+	return err
+}
+
+// Search implements onewire.Bus.
+func (b *Bus) Search(alarmOnly bool) ([]onewire.Address, error) {
+	return onewire.Search(b, alarmOnly)
+}
+
+// SearchTriplet implements onewire.BusSearcher.
+//
+// It reads the bit of the devices and its complement, then writes the
+// direction taken. direction is used only if devices answered both 0 and 1.
+func (b *Bus) SearchTriplet(direction byte) (onewire.TripletResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var res onewire.TripletResult
+	if b.closed {
+		return res, errClosed
+	}
+	bit, err := b.readBit()
+	if err != nil {
+		return res, err
+	}
+	comp, err := b.readBit()
+	if err != nil {
+		return res, err
+	}
+	// A device pulls the line Low to answer.
+	res.GotZero = !bit
+	res.GotOne = !comp
+	switch {
+	case res.GotZero && res.GotOne:
+		res.Taken = direction & 1
+	case res.GotZero:
+		res.Taken = 0
+	default:
+		res.Taken = 1
+	}
+	return res, b.writeBit(res.Taken == 1)
+}
+
+// Close implements onewire.BusCloser.
+//
+// It releases the line.
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errClosed
+	}
+	b.closed = true
+	return b.release()
+}
+
+// Q implements onewire.Pins.
+func (b *Bus) Q() gpio.PinIO {
+	return b.q
+}
+
+//
+
+// Timings at standard speed, as recommended by Maxim's AppNote 126.
+const (
+	tWrite1Low  = 6 * time.Microsecond   // A
+	tWrite1High = 64 * time.Microsecond  // B
+	tWrite0Low  = 60 * time.Microsecond  // C
+	tWrite0High = 10 * time.Microsecond  // D
+	tReadSample = 9 * time.Microsecond   // E
+	tReadEnd    = 55 * time.Microsecond  // F
+	tResetLow   = 480 * time.Microsecond // H
+	tPresence   = 70 * time.Microsecond  // I
+	tResetEnd   = 410 * time.Microsecond // J
+)
+
+var errClosed = errors.New("onewiregpio: bus closed")
+
+// tx runs the transaction.
+func (b *Bus) tx(ctx context.Context, w, r []byte) error {
+	if err := b.reset(); err != nil {
+		return err
+	}
+	for _, c := range w {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := b.writeByte(c); err != nil {
+			return err
+		}
+	}
+	for i := range r {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		c, err := b.readByte()
+		if err != nil {
+			return err
+		}
+		r[i] = c
+	}
+	return nil
+}
+
+// reset sends a reset pulse and detects the presence pulse of the devices.
+func (b *Bus) reset() error {
+	if err := b.release(); err != nil {
+		return err
+	}
+	b.delay(tWrite0High)
+	if b.q.Read() == gpio.Low {
+		return onewire.NewShortedBusError("onewiregpio: bus is shorted")
+	}
+	if err := b.q.Out(gpio.Low); err != nil {
+		return err
+	}
+	b.delay(tResetLow)
+	if err := b.release(); err != nil {
+		return err
+	}
+	b.delay(tPresence)
+	present := b.q.Read() == gpio.Low
+	b.delay(tResetEnd)
+	if !present {
+		return onewire.NewNoDevicesError("onewiregpio: no devices present")
+	}
+	return nil
+}
+
+// writeByte writes c LSB first.
+func (b *Bus) writeByte(c byte) error {
+	for i := 0; i < 8; i++ {
+		if err := b.writeBit(c&(1<<uint(i)) != 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readByte reads a byte LSB first.
+func (b *Bus) readByte() (byte, error) {
+	var c byte
+	for i := 0; i < 8; i++ {
+		bit, err := b.readBit()
+		if err != nil {
+			return 0, err
+		}
+		if bit {
+			c |= 1 << uint(i)
+		}
+	}
+	return c, nil
+}
+
+// writeBit sends a write time slot.
+func (b *Bus) writeBit(bit bool) error {
+	low, high := tWrite0Low, tWrite0High
+	if bit {
+		low, high = tWrite1Low, tWrite1High
+	}
+	if err := b.q.Out(gpio.Low); err != nil {
+		return err
+	}
+	b.delay(low)
+	if err := b.release(); err != nil {
+		return err
+	}
+	b.delay(high)
+	return nil
+}
+
+// readBit sends a read time slot and returns the level sampled.
+func (b *Bus) readBit() (bool, error) {
+	if err := b.q.Out(gpio.Low); err != nil {
+		return false, err
+	}
+	b.delay(tWrite1Low)
+	if err := b.release(); err != nil {
+		return false, err
+	}
+	b.delay(tReadSample)
+	bit := b.q.Read() == gpio.High
+	b.delay(tReadEnd)
+	return bit, nil
+}
+
+// release releases the line.
+func (b *Bus) release() error {
+	return b.q.In(gpio.PullUp, gpio.NoEdge)
+}
+
+var _ onewire.Bus = &Bus{}
+var _ onewire.BusCloser = &Bus{}
+var _ onewire.BusContext = &Bus{}
+var _ onewire.BusSearcher = &Bus{}
+var _ onewire.Pins = &Bus{}
@@ -0,0 +1,438 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package onewiregpio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/onewire"
+)
+
+func TestBus_Tx(t *testing.T) {
+	b, n := newBus(t, 0x1234)
+	d := n.devs[0]
+	d.scratch = []byte{0x50, 0x05, 0x4B}
+	dev := onewire.Dev{Bus: b, Addr: d.addr}
+	r := make([]byte, 3)
+	if err := dev.Tx([]byte{0xBE}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, d.scratch) {
+		t.Fatalf("%#v", r)
+	}
+	if err := dev.Tx([]byte{0x4E, 0x01, 0x80}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.rx, []byte{0xBE, 0x4E, 0x01, 0x80}) {
+		t.Fatalf("%#v", d.rx)
+	}
+	if n.q.strong {
+		t.Fatal("unexpected strong pull-up")
+	}
+}
+
+func TestBus_TxPower(t *testing.T) {
+	b, n := newBus(t, 0x1234)
+	dev := onewire.Dev{Bus: b, Addr: n.devs[0].addr}
+	if err := dev.TxPower([]byte{0x44}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !n.q.strong {
+		t.Fatal("expected strong pull-up")
+	}
+	if !bytes.Equal(n.devs[0].rx, []byte{0x44}) {
+		t.Fatalf("%#v", n.devs[0].rx)
+	}
+	// The next transaction releases the line.
+	if err := dev.Tx(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n.q.strong {
+		t.Fatal("the line must be released")
+	}
+}
+
+func TestBus_MatchROM(t *testing.T) {
+	b, n := newBus(t, 0x1234, 0x5678)
+	dev := onewire.Dev{Bus: b, Addr: n.devs[1].addr}
+	if err := dev.Tx([]byte{0x12}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n.devs[0].rx != nil {
+		t.Fatalf("%#v", n.devs[0].rx)
+	}
+	if !bytes.Equal(n.devs[1].rx, []byte{0x12}) {
+		t.Fatalf("%#v", n.devs[1].rx)
+	}
+}
+
+func TestBus_Search(t *testing.T) {
+	b, n := newBus(t, 0x1234, 0x5678, 0x1235, 0xABCDEF)
+	n.devs[2].alarm = true
+	got, err := b.Search(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []onewire.Address
+	for _, d := range n.devs {
+		want = append(want, d.addr)
+	}
+	sortAddr(got)
+	sortAddr(want)
+	if len(got) != len(want) {
+		t.Fatalf("%#v", got)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%#v != %#v", got, want)
+		}
+	}
+	got, err = b.Search(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != n.devs[2].addr {
+		t.Fatalf("%#v", got)
+	}
+}
+
+func TestBus_SearchTriplet(t *testing.T) {
+	b, n := newBus(t, 0x01, 0x02)
+	if err := b.Tx([]byte{0xF0}, nil, onewire.WeakPullup); err != nil {
+		t.Fatal(err)
+	}
+	// The family code 0x28 is shared, the first bit is 0.
+	res, err := b.SearchTriplet(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.GotZero || res.GotOne || res.Taken != 0 {
+		t.Fatalf("%#v", res)
+	}
+	for i := 1; i < 8; i++ {
+		if _, err := b.SearchTriplet(0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The serials differ on the first bit.
+	res, err = b.SearchTriplet(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.GotZero || !res.GotOne || res.Taken != 1 {
+		t.Fatalf("%#v", res)
+	}
+	if n.devs[0].state != searching || n.devs[1].state != idle {
+		t.Fatal("the device with a 0 must be deselected")
+	}
+}
+
+func TestBus_NoDevices(t *testing.T) {
+	b, _ := newBus(t)
+	err := b.Tx([]byte{0xCC}, nil, onewire.WeakPullup)
+	if e, ok := err.(onewire.NoDevicesError); !ok || !e.NoDevices() {
+		t.Fatal(err)
+	}
+	if !errors.Is(err, conn.ErrAddrNACK) {
+		t.Fatal(err)
+	}
+	if _, err := b.Search(false); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestBus_Shorted(t *testing.T) {
+	b, n := newBus(t, 0x1234)
+	other := n.wire.Pin("other", 2)
+	if err := other.Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	err := b.Tx([]byte{0xCC}, nil, onewire.WeakPullup)
+	if e, ok := err.(onewire.ShortedBusError); !ok || !e.IsShorted() {
+		t.Fatal(err)
+	}
+	if !errors.Is(err, conn.ErrBusBusy) {
+		t.Fatal(err)
+	}
+	if err := other.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx([]byte{0xCC}, nil, onewire.WeakPullup); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBus_Context(t *testing.T) {
+	b, n := newBus(t, 0x1234)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.TxContext(ctx, []byte{0xCC}, nil, onewire.StrongPullup); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if n.q.strong {
+		t.Fatal("the line must be released")
+	}
+}
+
+func TestBus_Misc(t *testing.T) {
+	b, n := newBus(t, 0x1234)
+	if s := b.String(); s != "onewiregpio(Q(1))" {
+		t.Fatal(s)
+	}
+	if b.Q() != n.q {
+		t.Fatal("unexpected pin")
+	}
+	if err := b.Tx(nil, nil, onewire.StrongPullup); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n.q.strong {
+		t.Fatal("the line must be released")
+	}
+	if b.Close() == nil {
+		t.Fatal("second Close() must fail")
+	}
+	if b.Tx(nil, nil, onewire.WeakPullup) == nil {
+		t.Fatal("closed")
+	}
+	if _, err := b.SearchTriplet(0); err == nil {
+		t.Fatal("closed")
+	}
+}
+
+//
+
+// newBus returns a bus with simulated devices with the serial numbers.
+func newBus(t *testing.T, serials ...uint64) (*Bus, *network) {
+	n := &network{}
+	n.q = &masterPin{WirePin: n.wire.Pin("Q", 1), n: n}
+	for i, s := range serials {
+		n.devs = append(n.devs, &device{addr: makeAddr(s), pin: n.wire.Pin("dev", 10+i)})
+	}
+	b, err := New(n.q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.delay = n.advance
+	return b, n
+}
+
+// makeAddr returns the address of a DS18B20 with the serial number s.
+func makeAddr(s uint64) onewire.Address {
+	a := uint64(0x28) | (s&0xFFFFFFFFFFFF)<<8
+	var buf [7]byte
+	for i := range buf {
+		buf[i] = byte(a >> uint(8*i))
+	}
+	return onewire.Address(a | uint64(onewire.CalcCRC(buf[:]))<<56)
+}
+
+func sortAddr(a []onewire.Address) {
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+}
+
+// network simulates the devices on a 1-wire bus in virtual time.
+//
+// The devices react to the pulses of the master, measured with the virtual
+// time advanced by Bus.delay.
+type network struct {
+	wire gpiotest.Wire
+	q    *masterPin
+	devs []*device
+	now  time.Duration
+	fall time.Duration
+}
+
+func (n *network) advance(d time.Duration) {
+	n.now += d
+	for _, dev := range n.devs {
+		if dev.holding && n.now >= dev.until {
+			dev.holding = false
+			_ = dev.pin.In(gpio.PullUp, gpio.NoEdge)
+		}
+	}
+}
+
+// masterPin notifies the network of the pulses of the master.
+type masterPin struct {
+	*gpiotest.WirePin
+	n      *network
+	low    bool
+	strong bool
+}
+
+func (p *masterPin) In(pull gpio.Pull, edge gpio.Edge) error {
+	if err := p.WirePin.In(pull, edge); err != nil {
+		return err
+	}
+	p.strong = false
+	if p.low {
+		p.low = false
+		for _, d := range p.n.devs {
+			d.rise(p.n.now, p.n.now-p.n.fall)
+		}
+	}
+	return nil
+}
+
+func (p *masterPin) Out(l gpio.Level) error {
+	if err := p.WirePin.Out(l); err != nil {
+		return err
+	}
+	p.strong = l == gpio.High
+	if l == gpio.Low && !p.low {
+		p.low = true
+		p.n.fall = p.n.now
+		for _, d := range p.n.devs {
+			d.onFall(p.n.now)
+		}
+	}
+	return nil
+}
+
+const (
+	idle = iota
+	command
+	matching
+	searching
+	receiving
+	sending
+)
+
+// device simulates a DS18B20 like device.
+//
+// After selection, it records the bytes written and answers scratch after
+// the command 0xBE.
+type device struct {
+	addr    onewire.Address
+	pin     *gpiotest.WirePin
+	alarm   bool
+	scratch []byte
+	rx      []byte
+
+	state int
+	bits  int
+	cur   byte
+	// phase is the slot of the search triplet: the bit, its complement then
+	// the direction.
+	phase   int
+	holding bool
+	until   time.Duration
+}
+
+// hold pulls the line Low until end.
+func (d *device) hold(end time.Duration) {
+	d.holding = true
+	d.until = end
+	_ = d.pin.Out(gpio.Low)
+}
+
+func (d *device) addrBit(i int) bool {
+	return d.addr&(1<<uint(i)) != 0
+}
+
+// onFall starts a time slot.
+func (d *device) onFall(now time.Duration) {
+	switch d.state {
+	case searching:
+		switch d.phase {
+		case 0:
+			if !d.addrBit(d.bits) {
+				d.hold(now + 30*time.Microsecond)
+			}
+		case 1:
+			if d.addrBit(d.bits) {
+				d.hold(now + 30*time.Microsecond)
+			}
+		}
+	case sending:
+		i := d.bits
+		d.bits++
+		if i < 8*len(d.scratch) && d.scratch[i/8]&(1<<uint(i%8)) == 0 {
+			d.hold(now + 30*time.Microsecond)
+		}
+	}
+}
+
+// rise ends a pulse of the master that lasted low.
+func (d *device) rise(now, low time.Duration) {
+	if low >= 400*time.Microsecond {
+		d.state = command
+		d.bits = 0
+		d.cur = 0
+		// Presence pulse.
+		d.hold(now + 120*time.Microsecond)
+		return
+	}
+	bit := low < 15*time.Microsecond
+	switch d.state {
+	case command, receiving:
+		if bit {
+			d.cur |= 1 << uint(d.bits)
+		}
+		if d.bits++; d.bits < 8 {
+			return
+		}
+		c := d.cur
+		d.bits = 0
+		d.cur = 0
+		if d.state == receiving {
+			d.rx = append(d.rx, c)
+			if c == 0xBE {
+				d.state = sending
+			}
+			return
+		}
+		switch c {
+		case 0xF0:
+			d.state = searching
+			d.phase = 0
+		case 0xEC:
+			d.state = idle
+			if d.alarm {
+				d.state = searching
+				d.phase = 0
+			}
+		case 0x55:
+			d.state = matching
+		case 0xCC:
+			d.state = receiving
+		default:
+			d.state = idle
+		}
+	case matching:
+		if bit != d.addrBit(d.bits) {
+			d.state = idle
+			return
+		}
+		if d.bits++; d.bits == 64 {
+			d.bits = 0
+			d.state = receiving
+		}
+	case searching:
+		if d.phase != 2 {
+			d.phase++
+			return
+		}
+		if bit != d.addrBit(d.bits) {
+			d.state = idle
+			return
+		}
+		d.phase = 0
+		if d.bits++; d.bits == 64 {
+			d.state = idle
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package onewiretest
+
+import (
+	"context"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/onewire"
+)
+
+// Faulty implements onewire.Bus and injects faults in the transactions on
+// Bus.
+//
+// Search is forwarded to Bus as-is. See conntest.Faults for the supported
+// faults.
+type Faulty struct {
+	Bus onewire.Bus
+	conntest.Faults
+}
+
+func (f *Faulty) String() string {
+	return "faulty(" + f.Bus.String() + ")"
+}
+
+// Tx implements onewire.Bus.
+func (f *Faulty) Tx(w, r []byte, pull onewire.Pullup) error {
+	return f.TxContext(context.Background(), w, r, pull)
+}
+
+// TxContext implements onewire.BusContext.
+//
+// ctx is forwarded to Bus if it implements onewire.BusContext.
+func (f *Faulty) TxContext(ctx context.Context, w, r []byte, pull onewire.Pullup) error {
+	return f.Do(ctx, func() error {
+		if b, ok := f.Bus.(onewire.BusContext); ok {
+			return b.TxContext(ctx, w, r, pull)
+		}
+		return conn.DoContext(ctx, w, r, func(w, r []byte) error {
+			return f.Bus.Tx(w, r, pull)
+		})
+	}, r)
+}
+
+// Search implements onewire.Bus.
+func (f *Faulty) Search(alarmOnly bool) ([]onewire.Address, error) {
+	return f.Bus.Search(alarmOnly)
+}
+
+// Q implements onewire.Pins.
+func (f *Faulty) Q() gpio.PinIO {
+	if p, ok := f.Bus.(onewire.Pins); ok {
+		return p.Q()
+	}
+	return gpio.INVALID
+}
+
+var _ onewire.Bus = &Faulty{}
+var _ onewire.BusContext = &Faulty{}
+var _ onewire.Pins = &Faulty{}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package onewiretest
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/onewire"
+)
+
+func TestFaulty(t *testing.T) {
+	p := &Playback{
+		Ops:       []IO{{W: []byte{0xbe}, R: []byte{2}}},
+		Devices:   []onewire.Address{0x28},
+		DontPanic: true,
+	}
+	f := &Faulty{Bus: p, Faults: conntest.Faults{LockupAt: 1}}
+	if s := f.String(); s != "faulty(playback)" {
+		t.Fatal(s)
+	}
+	if f.Q() != nil {
+		t.Fatal("expected the Playback pin")
+	}
+	if (&Faulty{Bus: &Record{}}).Q() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	r := make([]byte, 1)
+	if err := f.Tx([]byte{0xbe}, r, onewire.WeakPullup); err != conn.ErrBusBusy {
+		t.Fatal(err)
+	}
+	f.Recover()
+	if err := f.Tx([]byte{0xbe}, r, onewire.WeakPullup); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 2 {
+		t.Fatal(r)
+	}
+	if err := (&Faulty{Bus: &Record{}}).Tx([]byte{1}, nil, onewire.WeakPullup); err != nil {
+		t.Fatal(err)
+	}
+}
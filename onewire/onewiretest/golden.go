@@ -0,0 +1,84 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package onewiretest
+
+import (
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"testing"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/onewire"
+)
+
+// Golden returns a Playback of the 1-wire transactions saved in the golden
+// file at path, with the pull-up requested after each.
+//
+// When conntest.UpdateGolden is true, it returns a Record over b instead. See
+// conntest.GoldenFile for the details and conntest.SaveGolden for the file
+// formats.
+func Golden(t testing.TB, path string, b onewire.Bus) onewire.Bus {
+	t.Helper()
+	return conntest.GoldenFile(t, path, func() (onewire.Bus, *[]IO) {
+		r := &Record{Bus: b}
+		return r, &r.Ops
+	}, func(ops []IO) (onewire.Bus, io.Closer) {
+		p := &Playback{Ops: ops, DontPanic: true}
+		return p, p
+	})
+}
+
+// MarshalText implements encoding.TextMarshaler for the golden text format.
+//
+// The fields are "w", "r", "pull" and "err". "pull" is "strong" for
+// onewire.StrongPullup and omitted otherwise.
+func (io IO) MarshalText() ([]byte, error) {
+	pull := ""
+	if io.Pull == onewire.StrongPullup {
+		pull = "strong"
+	}
+	return conntest.FormatGoldenLine("w", hex.EncodeToString(io.W), "r", hex.EncodeToString(io.R), "pull", pull, "err", conntest.GoldenErrorName(io.Err)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the golden text
+// format.
+func (io *IO) UnmarshalText(b []byte) error {
+	f, err := conntest.ParseGoldenLine(b)
+	if err != nil {
+		return err
+	}
+	var n IO
+	for k, v := range f {
+		switch k {
+		case "w":
+			n.W, err = conntest.ParseGoldenHex(v)
+		case "r":
+			n.R, err = conntest.ParseGoldenHex(v)
+		case "pull":
+			switch v {
+			case "strong":
+				n.Pull = onewire.StrongPullup
+			case "weak":
+				n.Pull = onewire.WeakPullup
+			default:
+				err = fmt.Errorf("invalid pull %q", v)
+			}
+		case "err":
+			n.Err = conntest.GoldenError(v)
+		default:
+			err = fmt.Errorf("unknown field %q", k)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	*io = n
+	return nil
+}
+
+var _ encoding.TextMarshaler = IO{}
+var _ encoding.TextUnmarshaler = &IO{}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package onewiretest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/onewire"
+)
+
+func TestIO_MarshalText(t *testing.T) {
+	io := IO{W: []byte{0x44}, Pull: onewire.StrongPullup, Err: conn.ErrCRC}
+	b, err := io.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); s != "w=44 pull=strong err=crc" {
+		t.Fatal(s)
+	}
+	var got IO
+	if err := got.UnmarshalText(b); err != nil {
+		t.Fatal(err)
+	}
+	if string(got.W) != "\x44" || got.R != nil || got.Pull != onewire.StrongPullup || got.Err != conn.ErrCRC {
+		t.Fatalf("%#v", got)
+	}
+	if err := got.UnmarshalText([]byte("w=be r=0102 pull=weak")); err != nil {
+		t.Fatal(err)
+	}
+	if got.Pull != onewire.WeakPullup || len(got.R) != 2 {
+		t.Fatalf("%#v", got)
+	}
+	for _, line := range []string{"pull=x", "w=0", "r=0", "x=1", "w"} {
+		if err := got.UnmarshalText([]byte(line)); err == nil {
+			t.Fatalf("%q: expected error", line)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.txt")
+	defer func(u bool) { conntest.UpdateGolden = u }(conntest.UpdateGolden)
+	conntest.UpdateGolden = true
+	t.Run("update", func(t *testing.T) {
+		b := Golden(t, path, &Playback{Ops: []IO{{W: []byte{0x44}, Pull: onewire.StrongPullup}}})
+		if err := b.Tx([]byte{0x44}, nil, onewire.StrongPullup); err != nil {
+			t.Fatal(err)
+		}
+	})
+	conntest.UpdateGolden = false
+	t.Run("playback", func(t *testing.T) {
+		b := Golden(t, path, nil)
+		if err := b.Tx([]byte{0x44}, nil, onewire.StrongPullup); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
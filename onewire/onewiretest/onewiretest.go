@@ -7,8 +7,10 @@ package onewiretest
 
 import (
 	"bytes"
+	"context"
 	"sync"
 
+	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/conntest"
 	"periph.io/x/conn/v3/gpio"
 	"periph.io/x/conn/v3/onewire"
@@ -19,11 +21,15 @@ type IO struct {
 	W    []byte
 	R    []byte
 	Pull onewire.Pullup
+	// Err is returned by Playback once W and R are processed. Use one of the
+	// well known conn errors like conn.ErrCRC to simulate a failure.
+	Err error
 }
 
 // Record implements onewire.Bus that records everything written to it.
 //
 // This can then be used to feed to Playback to do "replay" based unit tests.
+// The Ops, including the pull-up, can be kept in a file with Golden().
 type Record struct {
 	sync.Mutex
 	Bus onewire.Bus // Bus can be nil if only writes are being recorded.
@@ -36,6 +42,16 @@ func (r *Record) String() string {
 
 // Tx implements onewire.Bus.
 func (r *Record) Tx(w, read []byte, pull onewire.Pullup) error {
+	return r.TxContext(context.Background(), w, read, pull)
+}
+
+// TxContext implements onewire.BusContext.
+//
+// ctx is forwarded to Bus if it implements onewire.BusContext.
+func (r *Record) TxContext(ctx context.Context, w, read []byte, pull onewire.Pullup) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	io := IO{Pull: pull}
 	if len(w) != 0 {
 		io.W = make([]byte, len(w))
@@ -48,7 +64,15 @@ func (r *Record) Tx(w, read []byte, pull onewire.Pullup) error {
 			return conntest.Errorf("onewiretest: read unsupported when no bus is connected")
 		}
 	} else {
-		if err := r.Bus.Tx(w, read, pull); err != nil {
+		var err error
+		if b, ok := r.Bus.(onewire.BusContext); ok {
+			err = b.TxContext(ctx, w, read, pull)
+		} else {
+			err = conn.DoContext(ctx, w, read, func(w, read []byte) error {
+				return r.Bus.Tx(w, read, pull)
+			})
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -83,6 +107,8 @@ func (r *Record) Search(alarmOnly bool) ([]onewire.Address, error) {
 // While "replay" type of unit tests are of limited value, they still present
 // an easy way to do basic code coverage.
 //
+// Set IO.Err to simulate a failing transaction.
+//
 // Set DontPanic to true to return an error instead of panicking, which is the
 // default.
 type Playback struct {
@@ -135,7 +161,17 @@ func (p *Playback) Tx(w, r []byte, pull onewire.Pullup) error {
 	// Concoct response.
 	copy(r, p.Ops[p.Count].R)
 	p.Count++
-	return nil
+	return p.Ops[p.Count-1].Err
+}
+
+// TxContext implements onewire.BusContext.
+//
+// If ctx is done, ctx.Err() is returned and the current op is not consumed.
+func (p *Playback) TxContext(ctx context.Context, w, r []byte, pull onewire.Pullup) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.Tx(w, r, pull)
 }
 
 // Q implements onewire.Pins.
@@ -202,6 +238,8 @@ func errorf(dontPanic bool, format string, a ...interface{}) error {
 }
 
 var _ onewire.Bus = &Record{}
+var _ onewire.BusContext = &Record{}
 var _ onewire.Pins = &Record{}
 var _ onewire.Bus = &Playback{}
+var _ onewire.BusContext = &Playback{}
 var _ onewire.BusSearcher = &Playback{}
@@ -5,9 +5,12 @@
 package onewiretest
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"testing"
 
+	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/conntest"
 	"periph.io/x/conn/v3/gpio"
 	"periph.io/x/conn/v3/gpio/gpiotest"
@@ -239,3 +242,40 @@ match:
 		t.Fatal(err)
 	}
 }
+
+func TestRecord_Playback_TxContext(t *testing.T) {
+	p := &Playback{Ops: []IO{{W: []byte{10}, R: []byte{12}, Pull: onewire.StrongPullup}}, DontPanic: true}
+	r := Record{Bus: p}
+	v := [1]byte{}
+	if err := r.TxContext(context.Background(), []byte{10}, v[:], onewire.StrongPullup); err != nil {
+		t.Fatal(err)
+	}
+	if v[0] != 12 || len(r.Ops) != 1 {
+		t.Fatal(v, r.Ops)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.TxContext(ctx, []byte{10}, v[:], onewire.StrongPullup); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := p.TxContext(ctx, []byte{10}, v[:], onewire.StrongPullup); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayback_Err(t *testing.T) {
+	p := Playback{Ops: []IO{{W: []byte{1}, R: []byte{2}, Err: conn.ErrAddrNACK}}, DontPanic: true}
+	r := make([]byte, 1)
+	if err := p.Tx([]byte{1}, r, onewire.WeakPullup); !errors.Is(err, conn.ErrAddrNACK) {
+		t.Fatal(err)
+	}
+	if r[0] != 2 {
+		t.Fatal(r)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
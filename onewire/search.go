@@ -111,7 +111,7 @@ func Search(bus BusSearcher, alarmOnly bool) ([]Address, error) {
 					msg += " "
 				}
 			}
-			return devices, busError(msg + "]")
+			return devices, crcError(msg + "]")
 		}
 		devices = append(devices, Address(device))
 		lastDevice = device
@@ -10,6 +10,8 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+
+	"periph.io/x/conn/v3"
 )
 
 // TestSearch tests the onewire.Search function using the Playback bus preloaded
@@ -69,6 +71,19 @@ match:
 	}
 }
 
+func TestSearch_CRC_err(t *testing.T) {
+	// The CRC byte is wrong.
+	p := playback{Devices: []Address{0x0000000000000001}}
+	p.Ops = []IO{{Write: []byte{0xf0}, Pull: WeakPullup}, {Write: []byte{0xf0}, Pull: WeakPullup}}
+	if err := p.Tx([]byte{0xf0}, nil, WeakPullup); err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := p.Search(false)
+	if len(addrs) != 0 || !errors.Is(err, conn.ErrCRC) {
+		t.Fatal(addrs, err)
+	}
+}
+
 func TestSearch_Tx_err(t *testing.T) {
 	p := playback{}
 	if addrs, err := p.Search(true); len(addrs) != 0 || err == nil {
@@ -112,6 +112,9 @@ type Packet struct {
 //
 // Implementers can optionally implement io.Writer and io.Reader for
 // unidirectional operation.
+//
+// Implementers are expected but not required to implement conn.ConnContext.
+// Use conn.WithContext() to get cancellation support on any Conn.
 type Conn interface {
 	conn.Conn
 	// TxPackets does multiple operations over the SPI connection.
@@ -0,0 +1,59 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spigpio_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/driver/driverreg"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spigpio"
+	"periph.io/x/conn/v3/spi/spireg"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	// TODO: Use host.Init(). It is not used in this example to prevent circular
+	// go package import.
+	if _, err := driverreg.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Make the port available by name, like the hardware ones.
+	opener := func() (spi.PortCloser, error) {
+		clk := gpioreg.ByName("GPIO21")
+		mosi := gpioreg.ByName("GPIO20")
+		miso := gpioreg.ByName("GPIO19")
+		cs := gpioreg.ByName("GPIO16")
+		if clk == nil || mosi == nil || miso == nil || cs == nil {
+			return nil, fmt.Errorf("failed to find the pins")
+		}
+		return spigpio.New(clk, mosi, miso, cs)
+	}
+	if err := spireg.Register("SPIGPIO", nil, -1, opener); err != nil {
+		log.Fatal(err)
+	}
+
+	p, err := spireg.Open("SPIGPIO")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer p.Close()
+
+	c, err := p.Connect(100*physic.KiloHertz, spi.Mode3, 8)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Read the chip ID of a BME280.
+	r := make([]byte, 2)
+	if err := c.Tx([]byte{0xD0, 0x00}, r); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("0x%02x\n", r[1])
+}
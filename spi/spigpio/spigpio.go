@@ -0,0 +1,450 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package spigpio implements a SPI port in software over GPIO pins.
+//
+// It is useful to connect devices to pins that are not routed to a hardware
+// SPI controller. The frequency passed to Connect() caps the clock: each bit
+// also costs the writes of CLK and MOSI and the read of MISO.
+package spigpio
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/internal/bitbang"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// New returns a SPI port over the pins.
+//
+// miso can be nil for a port that only writes. cs can be nil if the port is
+// always connected with spi.NoCS. For spi.HalfDuplex, mosi must be a
+// gpio.PinIO and is used in both directions; miso is then unused.
+func New(clk, mosi gpio.PinOut, miso gpio.PinIn, cs gpio.PinOut) (*Port, error) {
+	if clk == nil || mosi == nil {
+		return nil, errors.New("spigpio: clk and mosi are required")
+	}
+	return &Port{clk: clk, mosi: mosi, miso: miso, cs: cs}, nil
+}
+
+// Port is a SPI port bit-banged over GPIO pins.
+//
+// It supports the four clock modes, spi.HalfDuplex, spi.NoCS, spi.LSBFirst
+// and any number of bits per word up to 64.
+//
+// Words longer than 8 bits use the smallest number of bytes that can hold
+// them, in big endian, like 0x01, 0xFF for the 9 bits word 0x1FF. spi.LSBFirst
+// changes the order of the bits on the wire, not the layout in memory.
+type Port struct {
+	// Immutable.
+	clk  gpio.PinOut
+	mosi gpio.PinOut
+	miso gpio.PinIn
+	cs   gpio.PinOut
+
+	mu        sync.Mutex
+	limit     physic.Frequency
+	connected bool
+	closed    bool
+}
+
+func (p *Port) String() string {
+	return "spigpio(" + p.clk.String() + ")"
+}
+
+// Connect implements spi.Port.
+//
+// A frequency of 0 both for f and the limit set with LimitSpeed() means to
+// toggle the clock as fast as the pins allow.
+func (p *Port) Connect(f physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	if f < 0 {
+		return nil, errors.New("spigpio: invalid speed " + f.String())
+	}
+	if mode&^(spi.Mode3|spi.HalfDuplex|spi.NoCS|spi.LSBFirst) != 0 {
+		return nil, errors.New("spigpio: invalid mode " + mode.String())
+	}
+	if bits < 1 || bits > 64 {
+		return nil, errors.New("spigpio: invalid bits " + strconv.Itoa(bits))
+	}
+	if mode&spi.HalfDuplex != 0 {
+		if _, ok := p.mosi.(gpio.PinIO); !ok {
+			return nil, errors.New("spigpio: HalfDuplex requires mosi to be a gpio.PinIO")
+		}
+	}
+	if mode&spi.NoCS == 0 && p.cs == nil {
+		return nil, errors.New("spigpio: NoCS is required without cs")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, errClosed
+	}
+	if p.connected {
+		return nil, errors.New("spigpio: Connect() can only be called once")
+	}
+	if p.limit != 0 && (f == 0 || f > p.limit) {
+		f = p.limit
+	}
+	c := &spiConn{p: p, f: f, mode: mode, bits: bits}
+	if f != 0 {
+		c.half = f.Period() / 2
+	}
+	if err := c.idle(); err != nil {
+		return nil, err
+	}
+	p.connected = true
+	return c, nil
+}
+
+// LimitSpeed implements spi.PortCloser.
+//
+// It must be called before Connect().
+func (p *Port) LimitSpeed(f physic.Frequency) error {
+	if f <= 0 {
+		return errors.New("spigpio: invalid speed " + f.String())
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limit = f
+	return nil
+}
+
+// Close implements spi.PortCloser.
+func (p *Port) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return errClosed
+	}
+	p.closed = true
+	return nil
+}
+
+// CLK implements spi.Pins.
+func (p *Port) CLK() gpio.PinOut {
+	return p.clk
+}
+
+// MOSI implements spi.Pins.
+func (p *Port) MOSI() gpio.PinOut {
+	return p.mosi
+}
+
+// MISO implements spi.Pins.
+//
+// It returns gpio.INVALID if there's none.
+func (p *Port) MISO() gpio.PinIn {
+	if p.miso == nil {
+		return gpio.INVALID
+	}
+	return p.miso
+}
+
+// CS implements spi.Pins.
+//
+// It returns gpio.INVALID if there's none.
+func (p *Port) CS() gpio.PinOut {
+	if p.cs == nil {
+		return gpio.INVALID
+	}
+	return p.cs
+}
+
+//
+
+var errClosed = errors.New("spigpio: port closed")
+
+// spiConn implements spi.Conn.
+type spiConn struct {
+	// Immutable.
+	p    *Port
+	f    physic.Frequency
+	half time.Duration
+	mode spi.Mode
+	bits int
+
+	// Mutable; guarded by p.mu.
+	// selected is true when CS was kept asserted by the last packet.
+	selected bool
+}
+
+func (c *spiConn) String() string {
+	return c.p.String()
+}
+
+// Tx implements spi.Conn.
+//
+// In full duplex, w and r must have the same length, unless one of them is
+// empty. With spi.HalfDuplex, w is written first then r is read.
+func (c *spiConn) Tx(w, r []byte) error {
+	return c.TxContext(context.Background(), w, r)
+}
+
+// TxContext implements conn.ConnContext.
+//
+// ctx is checked between each word. When it is done, CS is deasserted and
+// ctx.Err() is returned.
+func (c *spiConn) TxContext(ctx context.Context, w, r []byte) error {
+	return c.txPackets(ctx, []spi.Packet{{W: w, R: r}})
+}
+
+// Duplex implements conn.Conn.
+func (c *spiConn) Duplex() conn.Duplex {
+	if c.mode&spi.HalfDuplex != 0 {
+		return conn.Half
+	}
+	return conn.Full
+}
+
+// TxPackets implements spi.Conn.
+func (c *spiConn) TxPackets(p []spi.Packet) error {
+	return c.txPackets(context.Background(), p)
+}
+
+// CLK implements spi.Pins.
+func (c *spiConn) CLK() gpio.PinOut {
+	return c.p.CLK()
+}
+
+// MOSI implements spi.Pins.
+func (c *spiConn) MOSI() gpio.PinOut {
+	return c.p.MOSI()
+}
+
+// MISO implements spi.Pins.
+func (c *spiConn) MISO() gpio.PinIn {
+	return c.p.MISO()
+}
+
+// CS implements spi.Pins.
+func (c *spiConn) CS() gpio.PinOut {
+	return c.p.CS()
+}
+
+func (c *spiConn) txPackets(ctx context.Context, packets []spi.Packet) error {
+	for i := range packets {
+		if err := c.check(&packets[i]); err != nil {
+			return err
+		}
+	}
+	c.p.mu.Lock()
+	defer c.p.mu.Unlock()
+	if c.p.closed {
+		return errClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for i := range packets {
+		pkt := &packets[i]
+		if err := c.selectCS(); err != nil {
+			return err
+		}
+		bits := c.bits
+		if pkt.BitsPerWord != 0 {
+			bits = int(pkt.BitsPerWord)
+		}
+		err := c.transfer(ctx, pkt.W, pkt.R, bits)
+		if err != nil || !pkt.KeepCS {
+			if err2 := c.deselectCS(); err == nil {
+				err = err2
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check verifies that the packet can be sent.
+func (c *spiConn) check(pkt *spi.Packet) error {
+	bits := c.bits
+	if pkt.BitsPerWord != 0 {
+		bits = int(pkt.BitsPerWord)
+	}
+	if bits > 64 {
+		return errors.New("spigpio: invalid bits " + strconv.Itoa(bits))
+	}
+	n := (bits + 7) / 8
+	if len(pkt.W)%n != 0 || len(pkt.R)%n != 0 {
+		return errors.New("spigpio: buffer length must be a multiple of " + strconv.Itoa(n) + " bytes for " + strconv.Itoa(bits) + " bits words")
+	}
+	if c.mode&spi.HalfDuplex == 0 {
+		if len(pkt.W) != 0 && len(pkt.R) != 0 && len(pkt.W) != len(pkt.R) {
+			return errors.New("spigpio: w and r must have the same length in full duplex")
+		}
+		if len(pkt.R) != 0 && c.p.miso == nil {
+			return errors.New("spigpio: can't read without miso")
+		}
+	}
+	return nil
+}
+
+// transfer clocks the words of w and r with CS asserted.
+func (c *spiConn) transfer(ctx context.Context, w, r []byte, bits int) error {
+	n := (bits + 7) / 8
+	if c.mode&spi.HalfDuplex != 0 {
+		for i := 0; i < len(w); i += n {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if _, err := c.word(getWord(w[i:i+n]), bits, true, false); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < len(r); i += n {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			v, err := c.word(0, bits, false, true)
+			if err != nil {
+				return err
+			}
+			putWord(r[i:i+n], v)
+		}
+		return nil
+	}
+	l := len(w)
+	if len(r) > l {
+		l = len(r)
+	}
+	for i := 0; i < l; i += n {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var v uint64
+		if len(w) != 0 {
+			v = getWord(w[i : i+n])
+		}
+		v, err := c.word(v, bits, true, len(r) != 0)
+		if err != nil {
+			return err
+		}
+		if len(r) != 0 {
+			putWord(r[i:i+n], v)
+		}
+	}
+	return nil
+}
+
+// word clocks one word. It writes v if write is true and returns the word
+// read if read is true.
+func (c *spiConn) word(v uint64, bits int, write, read bool) (uint64, error) {
+	idle := gpio.Level(c.mode&spi.Mode2 != 0)
+	cpha := c.mode&spi.Mode1 != 0
+	in := c.p.miso
+	if c.mode&spi.HalfDuplex != 0 {
+		in = c.p.mosi.(gpio.PinIO)
+		if read {
+			if err := in.In(gpio.PullNoChange, gpio.NoEdge); err != nil {
+				return 0, err
+			}
+		}
+	}
+	var out uint64
+	for i := 0; i < bits; i++ {
+		shift := bits - 1 - i
+		if c.mode&spi.LSBFirst != 0 {
+			shift = i
+		}
+		if cpha {
+			// Data is shifted on the leading edge and sampled on the trailing one.
+			if err := c.p.clk.Out(!idle); err != nil {
+				return 0, err
+			}
+		}
+		if write {
+			if err := c.p.mosi.Out(gpio.Level(v&(1<<uint(shift)) != 0)); err != nil {
+				return 0, err
+			}
+		}
+		bitbang.Delay(c.half)
+		// The second edge of the bit.
+		second := !idle
+		if cpha {
+			second = idle
+		}
+		if err := c.p.clk.Out(second); err != nil {
+			return 0, err
+		}
+		if read && in.Read() == gpio.High {
+			out |= 1 << uint(shift)
+		}
+		bitbang.Delay(c.half)
+		if !cpha {
+			if err := c.p.clk.Out(idle); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return out, nil
+}
+
+// idle sets the pins to their idle state.
+func (c *spiConn) idle() error {
+	if err := c.p.clk.Out(gpio.Level(c.mode&spi.Mode2 != 0)); err != nil {
+		return err
+	}
+	if err := c.p.mosi.Out(gpio.Low); err != nil {
+		return err
+	}
+	if c.mode&spi.NoCS == 0 {
+		return c.p.cs.Out(gpio.High)
+	}
+	return nil
+}
+
+// selectCS asserts CS, unless it was kept asserted.
+func (c *spiConn) selectCS() error {
+	if c.mode&spi.NoCS != 0 || c.selected {
+		return nil
+	}
+	if err := c.p.cs.Out(gpio.Low); err != nil {
+		return err
+	}
+	c.selected = true
+	bitbang.Delay(c.half)
+	return nil
+}
+
+// deselectCS deasserts CS.
+func (c *spiConn) deselectCS() error {
+	if c.mode&spi.NoCS != 0 {
+		return nil
+	}
+	c.selected = false
+	bitbang.Delay(c.half)
+	return c.p.cs.Out(gpio.High)
+}
+
+// getWord decodes a big endian word.
+func getWord(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// putWord encodes a big endian word.
+func putWord(b []byte, v uint64) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
+
+var _ spi.PortCloser = &Port{}
+var _ spi.Pins = &Port{}
+var _ spi.Conn = &spiConn{}
+var _ conn.ConnContext = &spiConn{}
+var _ spi.Pins = &spiConn{}
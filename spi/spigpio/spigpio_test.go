@@ -0,0 +1,409 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spigpio
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+func TestPort_Modes(t *testing.T) {
+	for _, m := range []spi.Mode{spi.Mode0, spi.Mode1, spi.Mode2, spi.Mode3} {
+		t.Run(m.String(), func(t *testing.T) {
+			p, s := newPort(t, m, true)
+			s.tx = "1100101011110000"
+			c := connect(t, p, m, 8)
+			r := make([]byte, 2)
+			if err := c.Tx([]byte{0xA5, 0x3C}, r); err != nil {
+				t.Fatal(err)
+			}
+			if s.rx != "1010010100111100" {
+				t.Fatal(s.rx)
+			}
+			if !bytes.Equal(r, []byte{0xCA, 0xF0}) {
+				t.Fatalf("%#v", r)
+			}
+			if s.selects != 1 || s.selected {
+				t.Fatalf("%d selects, selected %t", s.selects, s.selected)
+			}
+			if s.clk.Read() != s.idle() {
+				t.Fatal("clock must be idle")
+			}
+			if s.stray != 0 {
+				t.Fatalf("%d clock edges while not selected", s.stray)
+			}
+		})
+	}
+}
+
+func TestPort_LSBFirst(t *testing.T) {
+	p, s := newPort(t, spi.Mode0|spi.LSBFirst, true)
+	s.tx = "11000000"
+	c := connect(t, p, spi.Mode0|spi.LSBFirst, 8)
+	r := make([]byte, 1)
+	if err := c.Tx([]byte{0x01}, r); err != nil {
+		t.Fatal(err)
+	}
+	if s.rx != "10000000" {
+		t.Fatal(s.rx)
+	}
+	if r[0] != 0x03 {
+		t.Fatalf("0x%02x", r[0])
+	}
+}
+
+func TestPort_Bits(t *testing.T) {
+	p, s := newPort(t, spi.Mode0, true)
+	s.tx = "100000011"
+	c := connect(t, p, spi.Mode0, 9)
+	r := make([]byte, 2)
+	if err := c.Tx([]byte{0x01, 0x02}, r); err != nil {
+		t.Fatal(err)
+	}
+	if s.rx != "100000010" {
+		t.Fatal(s.rx)
+	}
+	if !bytes.Equal(r, []byte{0x01, 0x03}) {
+		t.Fatalf("%#v", r)
+	}
+	if c.Tx([]byte{1, 2, 3}, nil) == nil {
+		t.Fatal("9 bits words take 2 bytes")
+	}
+	// Write only.
+	s.rx = ""
+	if err := c.Tx([]byte{0x00, 0xFF, 0x01, 0x00}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.rx != "011111111100000000" {
+		t.Fatal(s.rx)
+	}
+}
+
+func TestPort_TxPackets(t *testing.T) {
+	p, s := newPort(t, spi.Mode0, true)
+	s.tx = "101100001111"
+	c := connect(t, p, spi.Mode0, 8)
+	r := make([]byte, 1)
+	pkts := []spi.Packet{
+		{W: []byte{0x01}, KeepCS: true},
+		{R: r, BitsPerWord: 4},
+	}
+	if err := c.TxPackets(pkts); err != nil {
+		t.Fatal(err)
+	}
+	// Zeros are written while reading.
+	if s.rx != "000000010000" {
+		t.Fatal(s.rx)
+	}
+	if r[0] != 0x0F {
+		t.Fatalf("0x%02x", r[0])
+	}
+	if s.selects != 1 || s.selected {
+		t.Fatalf("%d selects, selected %t", s.selects, s.selected)
+	}
+	// CS stays asserted until the next call.
+	if err := c.TxPackets([]spi.Packet{{W: []byte{0x80}, KeepCS: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if !s.selected {
+		t.Fatal("CS must be kept asserted")
+	}
+	if err := c.Tx([]byte{0x80}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.selects != 2 || s.selected {
+		t.Fatalf("%d selects, selected %t", s.selects, s.selected)
+	}
+	if c.TxPackets([]spi.Packet{{W: []byte{1}, BitsPerWord: 65}}) == nil {
+		t.Fatal("invalid bits")
+	}
+}
+
+func TestPort_NoCS(t *testing.T) {
+	p, s := newPort(t, spi.Mode0|spi.NoCS, false)
+	if _, err := p.Connect(0, spi.Mode0, 8); err == nil {
+		t.Fatal("NoCS is required without cs")
+	}
+	if p.CS() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	c := connect(t, p, spi.Mode0|spi.NoCS, 8)
+	if err := c.Tx([]byte{0x42}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.rx != "01000010" {
+		t.Fatal(s.rx)
+	}
+}
+
+func TestPort_HalfDuplex(t *testing.T) {
+	p, s := newPort(t, spi.Mode0|spi.HalfDuplex, true)
+	// The device responds after the byte written.
+	s.tx = "0000000010110000"
+	c := connect(t, p, spi.Mode0|spi.HalfDuplex, 8)
+	if d := c.Duplex(); d != conn.Half {
+		t.Fatal(d)
+	}
+	r := make([]byte, 1)
+	if err := c.Tx([]byte{0x81}, r); err != nil {
+		t.Fatal(err)
+	}
+	if s.rx != "10000001" {
+		t.Fatal(s.rx)
+	}
+	if r[0] != 0xB0 {
+		t.Fatalf("0x%02x", r[0])
+	}
+
+	p, err := New(s.clk, struct{ gpio.PinOut }{s.mosi}, nil, s.cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Connect(0, spi.Mode0|spi.HalfDuplex, 8); err == nil {
+		t.Fatal("mosi must be a PinIO")
+	}
+}
+
+func TestPort_Context(t *testing.T) {
+	p, s := newPort(t, spi.Mode0, true)
+	c := connect(t, p, spi.Mode0, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.(conn.ConnContext).TxContext(ctx, []byte{1}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if s.selected {
+		t.Fatal("CS must be deasserted")
+	}
+	if s.rx != "" {
+		t.Fatal(s.rx)
+	}
+}
+
+func TestPort_Errors(t *testing.T) {
+	if _, err := New(nil, &gpiotest.Pin{}, nil, nil); err == nil {
+		t.Fatal("clk is required")
+	}
+	_, s := newPort(t, spi.Mode0, true)
+	p, err := New(s.clk, s.mosi, nil, s.cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.MISO() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+	if _, err := p.Connect(-1, spi.Mode0, 8); err == nil {
+		t.Fatal("invalid speed")
+	}
+	if _, err := p.Connect(0, spi.Mode0|0x20, 8); err == nil {
+		t.Fatal("invalid mode")
+	}
+	if _, err := p.Connect(0, spi.Mode0, 0); err == nil {
+		t.Fatal("invalid bits")
+	}
+	if _, err := p.Connect(0, spi.Mode0, 65); err == nil {
+		t.Fatal("invalid bits")
+	}
+	if p.LimitSpeed(0) == nil {
+		t.Fatal("invalid speed")
+	}
+	c := connect(t, p, spi.Mode0, 8)
+	if _, err := p.Connect(0, spi.Mode0, 8); err == nil {
+		t.Fatal("second Connect() must fail")
+	}
+	if c.Tx([]byte{1, 2}, make([]byte, 1)) == nil {
+		t.Fatal("w and r must have the same length")
+	}
+	if c.Tx(nil, make([]byte, 1)) == nil {
+		t.Fatal("can't read without miso")
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Close() == nil {
+		t.Fatal("second Close() must fail")
+	}
+	if c.Tx([]byte{1}, nil) == nil {
+		t.Fatal("closed")
+	}
+}
+
+func TestPort_LimitSpeed(t *testing.T) {
+	p, _ := newPort(t, spi.Mode0, true)
+	if err := p.LimitSpeed(physic.MegaHertz); err != nil {
+		t.Fatal(err)
+	}
+	c := connect(t, p, spi.Mode0, 8).(*spiConn)
+	if c.f != physic.MegaHertz || c.half != 500*time.Nanosecond {
+		t.Fatal(c.f, c.half)
+	}
+	if s := c.String(); s != "spigpio(CLK(1))" {
+		t.Fatal(s)
+	}
+	if c.CLK() == nil || c.MOSI() == nil || c.MISO() == nil || c.CS() == nil {
+		t.Fatal("expected pins")
+	}
+}
+
+func TestPort_Conformance(t *testing.T) {
+	p, _ := newPort(t, spi.Mode0, true)
+	spitest.TestPort(t, p)
+}
+
+//
+
+// newPort returns a port connected to a simulated device.
+func newPort(t *testing.T, mode spi.Mode, cs bool) (*Port, *slave) {
+	s := &slave{mode: mode, selected: mode&spi.NoCS != 0}
+	s.clk = &hookPin{Pin: gpiotest.Pin{N: "CLK", Num: 1}, onOut: s.onCLK}
+	s.clkLevel = s.idle()
+	s.clk.L = s.clkLevel
+	s.mosi = &hookPin{Pin: gpiotest.Pin{N: "MOSI", Num: 2}, read: s.level}
+	s.miso = &hookPin{Pin: gpiotest.Pin{N: "MISO", Num: 3}, read: s.level, input: true}
+	s.cs = &hookPin{Pin: gpiotest.Pin{N: "CS", Num: 4, L: gpio.High}, onOut: s.onCS}
+	if s.selected && mode&spi.Mode1 == 0 {
+		s.shift()
+	}
+	var csPin gpio.PinOut
+	if cs {
+		csPin = s.cs
+	}
+	p, err := New(s.clk, s.mosi, s.miso, csPin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, s
+}
+
+func connect(t *testing.T, p *Port, mode spi.Mode, bits int) spi.Conn {
+	c, err := p.Connect(physic.GigaHertz, mode, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// slave simulates a SPI device, driven by the changes of the clock and CS.
+type slave struct {
+	mode spi.Mode
+	// tx is the bits sent by the device, as '0' and '1'.
+	tx string
+	// rx is the bits received by the device, as '0' and '1'.
+	rx string
+
+	clk, mosi, miso, cs *hookPin
+
+	clkLevel gpio.Level
+	selected bool
+	selects  int
+	stray    int
+	i        int
+	out      gpio.Level
+}
+
+// idle returns the idle level of the clock.
+func (s *slave) idle() gpio.Level {
+	return s.mode&spi.Mode2 != 0
+}
+
+// level returns the level driven by the device.
+func (s *slave) level() gpio.Level {
+	return s.out
+}
+
+func (s *slave) onCS(l gpio.Level) {
+	if s.selected == (l == gpio.Low) {
+		return
+	}
+	s.selected = l == gpio.Low
+	if s.selected {
+		s.selects++
+		// With CPHA=0, the first bit is presented on selection.
+		if s.mode&spi.Mode1 == 0 {
+			s.shift()
+		}
+	}
+}
+
+func (s *slave) onCLK(l gpio.Level) {
+	if l == s.clkLevel {
+		return
+	}
+	s.clkLevel = l
+	if !s.selected {
+		s.stray++
+		return
+	}
+	leading := l != s.idle()
+	if leading == (s.mode&spi.Mode1 == 0) {
+		// Sample.
+		if !s.mosi.isInput() {
+			if s.mosi.Pin.Read() == gpio.High {
+				s.rx += "1"
+			} else {
+				s.rx += "0"
+			}
+		}
+	} else {
+		s.shift()
+	}
+}
+
+// shift presents the next bit.
+func (s *slave) shift() {
+	s.out = s.i < len(s.tx) && s.tx[s.i] == '1'
+	s.i++
+}
+
+// hookPin is a gpiotest.Pin that calls onOut on each Out() and whose level
+// is returned by read while it is an input.
+type hookPin struct {
+	gpiotest.Pin
+	onOut func(l gpio.Level)
+	read  func() gpio.Level
+	input bool
+}
+
+func (p *hookPin) In(pull gpio.Pull, edge gpio.Edge) error {
+	p.Lock()
+	p.input = true
+	p.Unlock()
+	return nil
+}
+
+func (p *hookPin) Read() gpio.Level {
+	if p.isInput() && p.read != nil {
+		return p.read()
+	}
+	return p.Pin.Read()
+}
+
+func (p *hookPin) Out(l gpio.Level) error {
+	p.Lock()
+	p.input = false
+	p.Unlock()
+	if err := p.Pin.Out(l); err != nil {
+		return err
+	}
+	if p.onOut != nil {
+		p.onOut(l)
+	}
+	return nil
+}
+
+func (p *hookPin) isInput() bool {
+	p.Lock()
+	defer p.Unlock()
+	return p.input
+}
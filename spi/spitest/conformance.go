@@ -0,0 +1,42 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spitest
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/spi"
+)
+
+// TestPort verifies that p follows the contracts documented in package spi.
+//
+// It connects to p in Mode0 with 8 bits words, so p may not be usable
+// afterward. In addition to the checks of conntest.TestConn on the
+// connection:
+//   - String() is not empty;
+//   - Connect() succeeds with no maximum speed;
+//   - the connection is full duplex.
+//
+// Whether Connect() can be called again is left to the implementation, as
+// spi.Port doesn't specify it.
+//
+// It reports failures with t.Errorf().
+func TestPort(t testing.TB, p spi.Port) {
+	t.Helper()
+	if p.String() == "" {
+		t.Errorf("String() is empty")
+	}
+	c, err := p.Connect(0, spi.Mode0, 8)
+	if err != nil {
+		t.Errorf("Connect(): %v", err)
+		return
+	}
+	if d := c.Duplex(); d != conn.Full {
+		t.Errorf("Duplex() returned %s; expected Full", d)
+	}
+	conntest.TestConn(t, c)
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spitest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+)
+
+func TestTestPort(t *testing.T) {
+	TestPort(t, &Playback{Playback: conntest.Playback{Ops: []conntest.IO{{W: []byte{0}, R: []byte{0}}}, D: conn.Full, DontPanic: true}})
+
+	f := &fakeTB{}
+	TestPort(f, &Record{})
+	if !strings.Contains(f.String(), "Duplex() returned DuplexUnknown; expected Full") {
+		t.Fatal(f.String())
+	}
+}
+
+//
+
+// fakeTB records the errors reported by the conformance tests.
+type fakeTB struct {
+	testing.TB
+	errs []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errs = append(f.errs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) String() string {
+	return strings.Join(f.errs, "\n")
+}
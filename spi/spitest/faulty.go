@@ -0,0 +1,93 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spitest
+
+import (
+	"context"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Faulty implements spi.Conn and injects faults in the transactions on Conn.
+//
+// A TxPackets() call is a single transaction. See conntest.Faults for the
+// supported faults.
+type Faulty struct {
+	Conn spi.Conn
+	conntest.Faults
+}
+
+func (f *Faulty) String() string {
+	return "faulty(" + f.Conn.String() + ")"
+}
+
+// Tx implements spi.Conn.
+func (f *Faulty) Tx(w, r []byte) error {
+	return f.TxContext(context.Background(), w, r)
+}
+
+// TxContext implements conn.ConnContext.
+//
+// ctx is forwarded to Conn via conn.WithContext().
+func (f *Faulty) TxContext(ctx context.Context, w, r []byte) error {
+	return f.Do(ctx, func() error {
+		return conn.WithContext(f.Conn).TxContext(ctx, w, r)
+	}, r)
+}
+
+// TxPackets implements spi.Conn.
+func (f *Faulty) TxPackets(p []spi.Packet) error {
+	r := make([][]byte, len(p))
+	for i := range p {
+		r[i] = p[i].R
+	}
+	return f.Do(context.Background(), func() error {
+		return f.Conn.TxPackets(p)
+	}, r...)
+}
+
+// Duplex implements spi.Conn.
+func (f *Faulty) Duplex() conn.Duplex {
+	return f.Conn.Duplex()
+}
+
+// CLK implements spi.Pins.
+func (f *Faulty) CLK() gpio.PinOut {
+	if p, ok := f.Conn.(spi.Pins); ok {
+		return p.CLK()
+	}
+	return gpio.INVALID
+}
+
+// MOSI implements spi.Pins.
+func (f *Faulty) MOSI() gpio.PinOut {
+	if p, ok := f.Conn.(spi.Pins); ok {
+		return p.MOSI()
+	}
+	return gpio.INVALID
+}
+
+// MISO implements spi.Pins.
+func (f *Faulty) MISO() gpio.PinIn {
+	if p, ok := f.Conn.(spi.Pins); ok {
+		return p.MISO()
+	}
+	return gpio.INVALID
+}
+
+// CS implements spi.Pins.
+func (f *Faulty) CS() gpio.PinOut {
+	if p, ok := f.Conn.(spi.Pins); ok {
+		return p.CS()
+	}
+	return gpio.INVALID
+}
+
+var _ spi.Conn = &Faulty{}
+var _ conn.ConnContext = &Faulty{}
+var _ spi.Pins = &Faulty{}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spitest
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+func TestFaulty(t *testing.T) {
+	p := &Playback{Playback: conntest.Playback{Ops: []conntest.IO{{W: []byte{1}, R: []byte{2}}}, D: conn.Full, DontPanic: true}}
+	c, err := p.Connect(physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Faulty{Conn: c, Faults: conntest.Faults{BitFlipRate: 1}}
+	if s := f.String(); s != "faulty(playback)" {
+		t.Fatal(s)
+	}
+	if d := f.Duplex(); d != conn.Full {
+		t.Fatal(d)
+	}
+	if f.CLK() != nil || f.MOSI() != nil || f.MISO() != nil || f.CS() != nil {
+		t.Fatal("expected the Playback pins")
+	}
+	r := make([]byte, 1)
+	if err := f.Tx([]byte{1}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != ^byte(2) {
+		t.Fatal(r)
+	}
+	if err := f.TxPackets([]spi.Packet{{W: []byte{1}, R: r}}); !conntest.IsErr(err) {
+		t.Fatal(err)
+	}
+	d := &Faulty{Conn: &recordRawConn{NewRecordRaw(nil)}}
+	if d.CLK() != gpio.INVALID || d.MOSI() != gpio.INVALID || d.MISO() != gpio.INVALID || d.CS() != gpio.INVALID {
+		t.Fatal("expected INVALID")
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spitest
+
+import (
+	"io"
+	"testing"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Golden returns a port whose connection plays back the transactions saved in
+// the golden file at path. The file uses the format of conntest.IO.
+//
+// When conntest.UpdateGolden is true, it returns a Record over p instead. See
+// conntest.GoldenFile for the details.
+func Golden(t testing.TB, path string, p spi.PortCloser) spi.PortCloser {
+	t.Helper()
+	return conntest.GoldenFile(t, path, func() (spi.PortCloser, *[]conntest.IO) {
+		r := &Record{Port: p}
+		return r, &r.Ops
+	}, func(ops []conntest.IO) (spi.PortCloser, io.Closer) {
+		pb := &Playback{Playback: conntest.Playback{Ops: ops, DontPanic: true}}
+		return pb, pb
+	})
+}
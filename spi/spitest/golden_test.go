@@ -0,0 +1,45 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spitest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+func TestGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	defer func(u bool) { conntest.UpdateGolden = u }(conntest.UpdateGolden)
+	conntest.UpdateGolden = true
+	t.Run("update", func(t *testing.T) {
+		p := Golden(t, path, &Playback{Playback: conntest.Playback{Ops: []conntest.IO{{W: []byte{1}, R: []byte{2}}}}})
+		c, err := p.Connect(physic.MegaHertz, spi.Mode0, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Tx([]byte{1}, make([]byte, 1)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	conntest.UpdateGolden = false
+	t.Run("playback", func(t *testing.T) {
+		p := Golden(t, path, nil)
+		c, err := p.Connect(physic.MegaHertz, spi.Mode0, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := make([]byte, 1)
+		if err := c.Tx([]byte{1}, r); err != nil {
+			t.Fatal(err)
+		}
+		if r[0] != 2 {
+			t.Fatal(r)
+		}
+	})
+}
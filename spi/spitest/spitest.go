@@ -6,6 +6,7 @@
 package spitest
 
 import (
+	"context"
 	"io"
 	"log"
 	"sync"
@@ -65,6 +66,10 @@ func (r *recordRawConn) Tx(w, read []byte) error {
 	return r.r.Tx(w, read)
 }
 
+func (r *recordRawConn) TxContext(ctx context.Context, w, read []byte) error {
+	return r.r.TxContext(ctx, w, read)
+}
+
 func (r *recordRawConn) Duplex() conn.Duplex {
 	return r.r.Duplex()
 }
@@ -78,6 +83,7 @@ func (r *recordRawConn) TxPackets(p []spi.Packet) error {
 // Record implements spi.PortCloser that records everything written to it.
 //
 // This can then be used to feed to Playback to do "replay" based unit tests.
+// Golden() stores the transactions of the connection in the conntest format.
 type Record struct {
 	sync.Mutex
 	Port        spi.PortCloser // Port can be nil if only writes are being recorded.
@@ -155,7 +161,10 @@ func (r *Record) CS() gpio.PinOut {
 	return gpio.INVALID
 }
 
-func (r *Record) txInternal(c spi.Conn, w, read []byte) error {
+func (r *Record) txInternal(ctx context.Context, c spi.Conn, w, read []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	io := conntest.IO{}
 	if len(w) != 0 {
 		io.W = make([]byte, len(w))
@@ -168,7 +177,7 @@ func (r *Record) txInternal(c spi.Conn, w, read []byte) error {
 			return conntest.Errorf("spitest: read unsupported when no port is connected")
 		}
 	} else {
-		if err := c.Tx(w, read); err != nil {
+		if err := conn.WithContext(c).TxContext(ctx, w, read); err != nil {
 			return err
 		}
 	}
@@ -199,7 +208,11 @@ func (r *recordConn) Duplex() conn.Duplex {
 }
 
 func (r *recordConn) Tx(w, read []byte) error {
-	return r.r.txInternal(r.c, w, read)
+	return r.r.txInternal(context.Background(), r.c, w, read)
+}
+
+func (r *recordConn) TxContext(ctx context.Context, w, read []byte) error {
+	return r.r.txInternal(ctx, r.c, w, read)
 }
 
 // TxPackets is not yet implemented.
@@ -301,6 +314,10 @@ func (p *playbackConn) Tx(w, r []byte) error {
 	return p.p.Tx(w, r)
 }
 
+func (p *playbackConn) TxContext(ctx context.Context, w, r []byte) error {
+	return p.p.TxContext(ctx, w, r)
+}
+
 func (p *playbackConn) TxPackets(packets []spi.Packet) error {
 	return conntest.Errorf("spitest: TxPackets is not implemented")
 }
@@ -324,6 +341,8 @@ func (p *playbackConn) CS() gpio.PinOut {
 //
 
 // Log logs all operations done on an spi.PortCloser.
+//
+// Use connutil.TraceSPIPort for structured logging outside of tests.
 type Log struct {
 	spi.PortCloser
 }
@@ -363,6 +382,13 @@ func (l *LogConn) Tx(w, r []byte) error {
 	return err
 }
 
+// TxContext implements conn.ConnContext.
+func (l *LogConn) TxContext(ctx context.Context, w, r []byte) error {
+	err := conn.WithContext(l.Conn).TxContext(ctx, w, r)
+	log.Printf("%s.TxContext(%#v, %#v) = %v", l.Conn, w, r, err)
+	return err
+}
+
 // TxPackets is not yet implemented.
 func (l *LogConn) TxPackets(p []spi.Packet) error {
 	return conntest.Errorf("spitest: TxPackets is not implemented")
@@ -376,3 +402,7 @@ var _ spi.PortCloser = &Playback{}
 var _ spi.PortCloser = &Log{}
 var _ spi.Pins = &Record{}
 var _ spi.Pins = &Playback{}
+var _ conn.ConnContext = &recordRawConn{}
+var _ conn.ConnContext = &recordConn{}
+var _ conn.ConnContext = &playbackConn{}
+var _ conn.ConnContext = &LogConn{}
@@ -6,6 +6,7 @@ package spitest
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"io"
@@ -334,6 +335,48 @@ func TestLog_Playback(t *testing.T) {
 	}
 }
 
+func TestRecord_Playback_TxContext(t *testing.T) {
+	r := Record{
+		Port: &Playback{
+			Playback: conntest.Playback{
+				Ops:       []conntest.IO{{W: []byte{10}, R: []byte{12}}, {W: []byte{11}}},
+				D:         conn.Full,
+				DontPanic: true,
+			},
+		},
+	}
+	c, err := r.Connect(0, spi.Mode0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc, ok := c.(conn.ConnContext)
+	if !ok {
+		t.Fatal("expected conn.ConnContext")
+	}
+	v := [1]byte{}
+	if err := cc.TxContext(context.Background(), []byte{10}, v[:]); err != nil {
+		t.Fatal(err)
+	}
+	if v[0] != 12 {
+		t.Fatalf("expected 12, got %v", v)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cc.TxContext(ctx, []byte{11}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	l := LogConn{Conn: c}
+	if err := l.TxContext(ctx, []byte{11}, nil); err != context.Canceled {
+		t.Fatal(err)
+	}
+	if err := l.TxContext(context.Background(), []byte{11}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 //
 
 func TestMain(m *testing.M) {
@@ -0,0 +1,59 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package uartgpio_test
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"periph.io/x/conn/v3/driver/driverreg"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/uart"
+	"periph.io/x/conn/v3/uart/uartgpio"
+	"periph.io/x/conn/v3/uart/uartreg"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	// TODO: Use host.Init(). It is not used in this example to prevent circular
+	// go package import.
+	if _, err := driverreg.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Make the port available by name, like the hardware ones.
+	opener := func() (uart.PortCloser, error) {
+		rx := gpioreg.ByName("GPIO23")
+		tx := gpioreg.ByName("GPIO24")
+		if rx == nil || tx == nil {
+			return nil, fmt.Errorf("failed to find the pins")
+		}
+		return uartgpio.New(rx, tx)
+	}
+	if err := uartreg.Register("UARTGPIO", nil, -1, opener); err != nil {
+		log.Fatal(err)
+	}
+
+	p, err := uartreg.Open("UARTGPIO")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer p.Close()
+
+	c, err := p.Connect(9600*physic.Hertz, uart.One, uart.NoParity, uart.NoFlow, 8)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := c.Tx([]byte("login: "), nil); err != nil {
+		log.Fatal(err)
+	}
+	// The connection is also an io.Reader, to use as a console.
+	if _, err := io.Copy(os.Stdout, c.(io.Reader)); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package uartgpio
+
+import (
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioutil"
+	"periph.io/x/conn/v3/uart"
+)
+
+// pollTimeout is how often the receiver checks if the port was closed while
+// the line is idle.
+const pollTimeout = 100 * time.Millisecond
+
+// startRX starts the receiver.
+func (c *serialConn) startRX() error {
+	ee := gpioutil.EdgeEvents(c.p.rx)
+	if err := ee.In(gpio.PullUp, gpio.BothEdges); err != nil {
+		return err
+	}
+	c.done = make(chan struct{})
+	go c.receive(ee)
+	return nil
+}
+
+// receive decodes the frames until the port is closed.
+func (c *serialConn) receive(ee gpio.PinEdgeEvents) {
+	defer close(c.done)
+	d := newDecoder(c.bit, c.bits, c.parity != uart.NoParity)
+	for !c.closed() {
+		timeout := pollTimeout
+		if d.inFrame {
+			// Wait at least a bit, so queued edges are not skipped.
+			timeout = time.Until(d.end())
+			if timeout < c.bit {
+				timeout = c.bit
+			}
+		}
+		ev, ok := ee.WaitForEdgeEvent(timeout)
+		if !ok {
+			// The line didn't change until the end of the frame.
+			if v, ok := d.flush(); ok {
+				c.push(v)
+			}
+			continue
+		}
+		if v, ok := d.edge(ev.Time, ev.Level); ok {
+			c.push(v)
+		}
+	}
+}
+
+// push checks and buffers a frame.
+func (c *serialConn) push(v uint16) {
+	// The start bit is followed by the data bits, the parity bit if any and
+	// the stop bit.
+	b := byte(v >> 1 & (1<<uint(c.bits) - 1))
+	p := c.bits + 1
+	stop := p
+	if c.parity != uart.NoParity {
+		stop++
+	}
+	c.mu.Lock()
+	switch {
+	case v&(1<<uint(stop)) == 0:
+		c.setErr(errFraming)
+	case c.parity != uart.NoParity && gpio.Level(v&(1<<uint(p)) != 0) != parityBit(c.parity, b, c.bits):
+		c.setErr(errParity)
+	case len(c.buf) >= rxBufferSize:
+		c.setErr(errOverflow)
+	default:
+		c.buf = append(c.buf, b)
+	}
+	c.mu.Unlock()
+	select {
+	case c.avail <- struct{}{}:
+	default:
+	}
+}
+
+// setErr records the first error since the last read. c.mu must be held.
+func (c *serialConn) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+		c.errAt = len(c.buf)
+	}
+}
+
+// decoder reconstructs frames from the timestamped edges of the line.
+//
+// Each bit is sampled in its middle, relative to the falling edge of the
+// start bit.
+type decoder struct {
+	bit time.Duration
+	// n is the number of samples per frame: the start bit, the data bits, the
+	// parity bit if any and the first stop bit.
+	n int
+
+	inFrame bool
+	start   time.Time
+	// level is the level of the line since the last edge.
+	level gpio.Level
+	// i is the next sample.
+	i int
+	// v is the samples, the start bit first.
+	v uint16
+}
+
+func newDecoder(bit time.Duration, bits int, parity bool) *decoder {
+	n := bits + 2
+	if parity {
+		n++
+	}
+	return &decoder{bit: bit, n: n, level: gpio.High}
+}
+
+// end returns when the last sample of the current frame is taken.
+func (d *decoder) end() time.Time {
+	return d.start.Add(d.sampleAt(d.n - 1))
+}
+
+// sampleAt returns when the sample i is taken, relative to the start.
+func (d *decoder) sampleAt(i int) time.Duration {
+	return d.bit*time.Duration(i) + d.bit/2
+}
+
+// edge processes a change of the line to l at t. It returns a frame if one
+// completed.
+func (d *decoder) edge(t time.Time, l gpio.Level) (uint16, bool) {
+	var v uint16
+	ok := false
+	if d.inFrame {
+		d.fill(t)
+		if d.inFrame && d.i == d.n {
+			v, ok = d.v, true
+			d.inFrame = false
+		}
+	}
+	if !d.inFrame && l == gpio.Low {
+		d.inFrame = true
+		d.start = t
+		d.i = 0
+		d.v = 0
+	}
+	d.level = l
+	return v, ok
+}
+
+// flush completes the current frame with the current level.
+func (d *decoder) flush() (uint16, bool) {
+	if !d.inFrame {
+		return 0, false
+	}
+	for d.inFrame && d.i < d.n {
+		d.sample()
+	}
+	if !d.inFrame {
+		return 0, false
+	}
+	d.inFrame = false
+	return d.v, true
+}
+
+// fill takes the samples before t.
+func (d *decoder) fill(t time.Time) {
+	for d.inFrame && d.i < d.n && d.start.Add(d.sampleAt(d.i)).Before(t) {
+		d.sample()
+	}
+}
+
+// sample takes the next sample. A start bit shorter than half a bit is a
+// glitch and is ignored.
+func (d *decoder) sample() {
+	if d.i == 0 && d.level == gpio.High {
+		d.inFrame = false
+		return
+	}
+	if d.level {
+		d.v |= 1 << uint(d.i)
+	}
+	d.i++
+}
@@ -0,0 +1,486 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package uartgpio implements an UART in software over GPIO pins.
+//
+// It is useful to get an additional serial port, like a console, on boards
+// with a single hardware UART. It is meant for speeds up to a few tens of
+// kbauds.
+//
+// The frames are transmitted as a gpiostream.BitStream if the TX pin
+// implements gpiostream.PinOut, which is the most precise. Otherwise the
+// bits are generated with timed Out() calls.
+//
+// The frames are received by timing the edges of the RX pin. It is precise
+// when the pin implements gpio.PinEdgeEvents, as the edges are then
+// timestamped by the driver. Otherwise they are timestamped by
+// gpioutil.EdgeEvents() and the reception is limited by the scheduling
+// latency of the OS.
+package uartgpio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiostream"
+	"periph.io/x/conn/v3/internal/bitbang"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/uart"
+)
+
+// New returns an UART port over the pins.
+//
+// Either rx or tx can be nil for a port that only transmits or only
+// receives.
+func New(rx gpio.PinIn, tx gpio.PinOut) (*Port, error) {
+	if rx == nil && tx == nil {
+		return nil, errors.New("uartgpio: rx or tx is required")
+	}
+	return &Port{rx: rx, tx: tx}, nil
+}
+
+// Port is an UART port bit-banged over GPIO pins.
+//
+// It supports 5 to 8 bits per character, all the uart.Parity and uart.Stop
+// values and no flow control.
+type Port struct {
+	// Immutable.
+	rx gpio.PinIn
+	tx gpio.PinOut
+
+	mu     sync.Mutex
+	limit  physic.Frequency
+	c      *serialConn
+	closed bool
+}
+
+func (p *Port) String() string {
+	s := "uartgpio("
+	if p.rx != nil {
+		s += "RX=" + p.rx.String()
+		if p.tx != nil {
+			s += ", "
+		}
+	}
+	if p.tx != nil {
+		s += "TX=" + p.tx.String()
+	}
+	return s + ")"
+}
+
+// Connect implements uart.Port.
+//
+// flow must be uart.NoFlow. The receiver runs in the background until the
+// port is closed; the characters received are buffered until read.
+func (p *Port) Connect(f physic.Frequency, stopBit uart.Stop, parity uart.Parity, flow uart.Flow, bits int) (conn.Conn, error) {
+	if f < 0 {
+		return nil, errors.New("uartgpio: invalid speed " + f.String())
+	}
+	switch stopBit {
+	case uart.One, uart.OneHalf, uart.Two:
+	default:
+		return nil, errors.New("uartgpio: invalid stop bit " + strconv.Itoa(int(stopBit)))
+	}
+	switch parity {
+	case uart.NoParity, uart.Odd, uart.Even, uart.Mark, uart.Space:
+	default:
+		return nil, errors.New("uartgpio: invalid parity " + strconv.Quote(string(rune(parity))))
+	}
+	if flow != uart.NoFlow {
+		return nil, errors.New("uartgpio: unsupported flow control " + flow.String())
+	}
+	if bits < 5 || bits > 8 {
+		return nil, errors.New("uartgpio: invalid bits " + strconv.Itoa(bits))
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, errClosed
+	}
+	if p.c != nil {
+		return nil, errors.New("uartgpio: Connect() can only be called once")
+	}
+	if p.limit != 0 && (f == 0 || f > p.limit) {
+		f = p.limit
+	}
+	if f == 0 {
+		return nil, errors.New("uartgpio: a speed is required")
+	}
+	c := &serialConn{
+		p:      p,
+		f:      f,
+		bit:    f.Period(),
+		stop:   stopBit,
+		parity: parity,
+		bits:   bits,
+		avail:  make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+	}
+	if p.tx != nil {
+		// Idle.
+		if err := p.tx.Out(gpio.High); err != nil {
+			return nil, err
+		}
+	}
+	if p.rx != nil {
+		if err := c.startRX(); err != nil {
+			return nil, err
+		}
+	}
+	p.c = c
+	return c, nil
+}
+
+// LimitSpeed implements uart.PortCloser.
+//
+// It must be called before Connect().
+func (p *Port) LimitSpeed(f physic.Frequency) error {
+	if f <= 0 {
+		return errors.New("uartgpio: invalid speed " + f.String())
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limit = f
+	return nil
+}
+
+// Close implements uart.PortCloser.
+//
+// It stops the receiver.
+func (p *Port) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return errClosed
+	}
+	p.closed = true
+	c := p.c
+	p.mu.Unlock()
+	if c != nil {
+		close(c.quit)
+		if c.done != nil {
+			<-c.done
+		}
+	}
+	return nil
+}
+
+// RX implements uart.Pins.
+//
+// It returns gpio.INVALID if there's none.
+func (p *Port) RX() gpio.PinIn {
+	if p.rx == nil {
+		return gpio.INVALID
+	}
+	return p.rx
+}
+
+// TX implements uart.Pins.
+//
+// It returns gpio.INVALID if there's none.
+func (p *Port) TX() gpio.PinOut {
+	if p.tx == nil {
+		return gpio.INVALID
+	}
+	return p.tx
+}
+
+// RTS implements uart.Pins.
+//
+// It always returns gpio.INVALID.
+func (p *Port) RTS() gpio.PinOut {
+	return gpio.INVALID
+}
+
+// CTS implements uart.Pins.
+//
+// It always returns gpio.INVALID.
+func (p *Port) CTS() gpio.PinIn {
+	return gpio.INVALID
+}
+
+//
+
+// rxBufferSize is the number of characters buffered before the overflow.
+const rxBufferSize = 4096
+
+var (
+	errClosed   = errors.New("uartgpio: port closed")
+	errNoRX     = errors.New("uartgpio: can't read without rx")
+	errNoTX     = errors.New("uartgpio: can't write without tx")
+	errFraming  = errors.New("uartgpio: framing error")
+	errParity   = errors.New("uartgpio: parity error")
+	errOverflow = errors.New("uartgpio: receive buffer overflow")
+)
+
+// serialConn implements conn.Conn.
+type serialConn struct {
+	// Immutable.
+	p      *Port
+	f      physic.Frequency
+	bit    time.Duration
+	stop   uart.Stop
+	parity uart.Parity
+	bits   int
+	// avail is signaled when characters are received.
+	avail chan struct{}
+	// quit is closed by Port.Close().
+	quit chan struct{}
+	// done is closed when the receiver stopped; nil without rx.
+	done chan struct{}
+
+	// txMu serializes the writes.
+	txMu sync.Mutex
+
+	mu sync.Mutex
+	// buf is the characters received and not read yet.
+	buf []byte
+	// err is the first reception error since the last read.
+	err error
+	// errAt is the number of characters in buf received before err.
+	errAt int
+}
+
+func (c *serialConn) String() string {
+	return c.p.String()
+}
+
+// Tx implements conn.Conn.
+//
+// It writes w, then waits until len(r) characters are received. The
+// characters received before the call are returned first.
+func (c *serialConn) Tx(w, r []byte) error {
+	return c.TxContext(context.Background(), w, r)
+}
+
+// TxContext implements conn.ConnContext.
+//
+// ctx is checked between each character written and while waiting for the
+// characters to read.
+func (c *serialConn) TxContext(ctx context.Context, w, r []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(w) != 0 {
+		if err := c.write(ctx, w); err != nil {
+			return err
+		}
+	}
+	for len(r) != 0 {
+		n, err := c.read(ctx, r)
+		if err != nil {
+			return err
+		}
+		r = r[n:]
+	}
+	return nil
+}
+
+// Duplex implements conn.Conn.
+func (c *serialConn) Duplex() conn.Duplex {
+	return conn.Full
+}
+
+// Read implements io.Reader.
+//
+// It waits for at least one character and returns the ones already
+// received. A framing error, a parity error or an overflow since the last
+// read is returned once, after the characters received before it.
+func (c *serialConn) Read(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	return c.read(context.Background(), b)
+}
+
+// Write implements io.Writer.
+func (c *serialConn) Write(b []byte) (int, error) {
+	if err := c.write(context.Background(), b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// RX implements uart.Pins.
+func (c *serialConn) RX() gpio.PinIn {
+	return c.p.RX()
+}
+
+// TX implements uart.Pins.
+func (c *serialConn) TX() gpio.PinOut {
+	return c.p.TX()
+}
+
+// RTS implements uart.Pins.
+func (c *serialConn) RTS() gpio.PinOut {
+	return c.p.RTS()
+}
+
+// CTS implements uart.Pins.
+func (c *serialConn) CTS() gpio.PinIn {
+	return c.p.CTS()
+}
+
+// closed returns true once the port is closed.
+func (c *serialConn) closed() bool {
+	select {
+	case <-c.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// read waits for at least one character or an error.
+func (c *serialConn) read(ctx context.Context, b []byte) (int, error) {
+	if c.p.rx == nil {
+		return 0, errNoRX
+	}
+	for {
+		c.mu.Lock()
+		l := len(c.buf)
+		if c.err != nil {
+			l = c.errAt
+		}
+		n := copy(b, c.buf[:l])
+		c.buf = c.buf[n:]
+		if c.err != nil {
+			if c.errAt -= n; n == 0 {
+				err := c.err
+				c.err = nil
+				c.mu.Unlock()
+				return 0, err
+			}
+		}
+		c.mu.Unlock()
+		if n != 0 {
+			return n, nil
+		}
+		if c.closed() {
+			return 0, errClosed
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-c.quit:
+		case <-c.avail:
+		}
+	}
+}
+
+// write transmits the characters.
+func (c *serialConn) write(ctx context.Context, w []byte) error {
+	tx := c.p.tx
+	if tx == nil {
+		return errNoTX
+	}
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+	if c.closed() {
+		return errClosed
+	}
+	// 1.5 stop bits requires two samples per bit.
+	k := 1
+	if c.stop == uart.OneHalf {
+		k = 2
+	}
+	if s, ok := tx.(gpiostream.PinOut); ok {
+		var levels []gpio.Level
+		for _, b := range w {
+			levels = c.appendFrame(levels, b, k)
+		}
+		return s.StreamOut(toBitStream(levels, c.f*physic.Frequency(k)))
+	}
+	period := c.bit / time.Duration(k)
+	var levels []gpio.Level
+	for _, b := range w {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		levels = c.appendFrame(levels[:0], b, k)
+		next := time.Now()
+		for _, l := range levels {
+			if err := tx.Out(l); err != nil {
+				return err
+			}
+			next = next.Add(period)
+			bitbang.Until(next)
+		}
+	}
+	return nil
+}
+
+// appendFrame appends the levels of the frame for the character b, k samples
+// per bit.
+func (c *serialConn) appendFrame(levels []gpio.Level, b byte, k int) []gpio.Level {
+	add := func(l gpio.Level, n int) {
+		for i := 0; i < n; i++ {
+			levels = append(levels, l)
+		}
+	}
+	// Start bit.
+	add(gpio.Low, k)
+	for i := 0; i < c.bits; i++ {
+		add(gpio.Level(b&(1<<uint(i)) != 0), k)
+	}
+	if c.parity != uart.NoParity {
+		add(parityBit(c.parity, b, c.bits), k)
+	}
+	switch c.stop {
+	case uart.One:
+		add(gpio.High, k)
+	case uart.OneHalf:
+		add(gpio.High, 3)
+	case uart.Two:
+		add(gpio.High, 2*k)
+	}
+	return levels
+}
+
+// parityBit returns the parity bit of the low bits of b.
+func parityBit(p uart.Parity, b byte, bits int) gpio.Level {
+	ones := 0
+	for i := 0; i < bits; i++ {
+		if b&(1<<uint(i)) != 0 {
+			ones++
+		}
+	}
+	switch p {
+	case uart.Odd:
+		return ones&1 == 0
+	case uart.Even:
+		return ones&1 == 1
+	case uart.Mark:
+		return gpio.High
+	default:
+		return gpio.Low
+	}
+}
+
+// toBitStream packs the levels LSB first, padded with the idle level.
+func toBitStream(levels []gpio.Level, f physic.Frequency) *gpiostream.BitStream {
+	s := &gpiostream.BitStream{Bits: make([]byte, (len(levels)+7)/8), Freq: f, LSBF: true}
+	for i := range s.Bits {
+		s.Bits[i] = 0xFF
+	}
+	for i, l := range levels {
+		if l == gpio.Low {
+			s.Bits[i/8] &^= 1 << uint(i%8)
+		}
+	}
+	return s
+}
+
+var _ uart.PortCloser = &Port{}
+var _ uart.Pins = &Port{}
+var _ conn.Conn = &serialConn{}
+var _ conn.ConnContext = &serialConn{}
+var _ io.ReadWriter = &serialConn{}
+var _ uart.Pins = &serialConn{}
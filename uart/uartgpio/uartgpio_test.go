@@ -0,0 +1,433 @@
+// Copyright 2026 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package uartgpio
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiostream"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/uart"
+)
+
+func TestConn_Stream(t *testing.T) {
+	tx := &streamPin{Pin: gpiotest.Pin{N: "TX"}}
+	c := connect(t, nil, tx, uart.One, uart.NoParity, 8)
+	if err := c.Tx([]byte("A"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.streams) != 1 {
+		t.Fatal(tx.streams)
+	}
+	s := tx.streams[0]
+	if s.Freq != 9600*physic.Hertz || !s.LSBF {
+		t.Fatalf("%#v", s)
+	}
+	// 0x41 is sent LSB first between the start and stop bits, then the line is
+	// idle.
+	if got := unpack(s); got != "0100000101111111" {
+		t.Fatal(got)
+	}
+	if tx.Read() != gpio.High {
+		t.Fatal("line must be idle")
+	}
+}
+
+func TestConn_Stream_OneHalf(t *testing.T) {
+	tx := &streamPin{Pin: gpiotest.Pin{N: "TX"}}
+	c := connect(t, nil, tx, uart.OneHalf, uart.Even, 7)
+	if err := c.Tx([]byte("A"), nil); err != nil {
+		t.Fatal(err)
+	}
+	s := tx.streams[0]
+	if s.Freq != 2*9600*physic.Hertz {
+		t.Fatal(s.Freq)
+	}
+	// Each bit is doubled, and 1.5 stop bits is 3 samples.
+	want := "00" + "11000000000011" + "00" + "111"
+	if got := unpack(s); got[:len(want)] != want {
+		t.Fatal(got)
+	}
+}
+
+func TestConn_Out(t *testing.T) {
+	tx := &outPin{Pin: gpiotest.Pin{N: "TX"}}
+	c := connect(t, nil, tx, uart.Two, uart.Odd, 8)
+	tx.levels = ""
+	if err := c.Tx([]byte("AB"), nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "0" + "10000010" + "1" + "11" + "0" + "01000010" + "1" + "11"
+	if tx.levels != want {
+		t.Fatal(tx.levels)
+	}
+}
+
+func TestConn_Parity(t *testing.T) {
+	data := []struct {
+		p    uart.Parity
+		b    byte
+		want gpio.Level
+	}{
+		{uart.Odd, 0x03, gpio.High},
+		{uart.Odd, 0x07, gpio.Low},
+		{uart.Even, 0x03, gpio.Low},
+		{uart.Even, 0x07, gpio.High},
+		{uart.Mark, 0x00, gpio.High},
+		{uart.Space, 0xFF, gpio.Low},
+	}
+	for i, line := range data {
+		if got := parityBit(line.p, line.b, 8); got != line.want {
+			t.Fatalf("#%d: %s", i, got)
+		}
+	}
+	// Only the data bits are counted.
+	if got := parityBit(uart.Even, 0x81, 7); got != gpio.High {
+		t.Fatal(got)
+	}
+}
+
+func TestConn_RX(t *testing.T) {
+	rx := newEventPin()
+	c := connect(t, rx, nil, uart.One, uart.NoParity, 8)
+	// "hi" back to back.
+	rx.send("0" + "00010110" + "1" + "0" + "10010110" + "1")
+	r := make([]byte, 2)
+	if err := c.Tx(nil, r); err != nil {
+		t.Fatal(err)
+	}
+	if string(r) != "hi" {
+		t.Fatalf("%q", r)
+	}
+	// A glitch shorter than half a bit is ignored.
+	rx.pulse(10 * time.Microsecond)
+	rx.send("0" + "10000010" + "1")
+	if n, err := c.(*serialConn).Read(r); n != 1 || err != nil || r[0] != 'A' {
+		t.Fatal(n, err, r)
+	}
+}
+
+func TestConn_RX_Errors(t *testing.T) {
+	rx := newEventPin()
+	c := connect(t, rx, nil, uart.One, uart.Even, 8).(*serialConn)
+	// The parity bit of 'A' is 0.
+	rx.send("0" + "10000010" + "1" + "1")
+	rx.send("0" + "10000010" + "0" + "0")
+	rx.send("0" + "10000010" + "0" + "1")
+	waitRX(t, c, 1)
+	// The framing error of the second frame is hidden by the parity error.
+	r := make([]byte, 4)
+	if n, err := c.Read(r); n != 0 || err != errParity {
+		t.Fatal(n, err)
+	}
+	if n, err := c.Read(r); n != 1 || err != nil || r[0] != 'A' {
+		t.Fatal(n, err, r)
+	}
+
+	// The characters received before the overflow are read first.
+	c.mu.Lock()
+	c.buf = make([]byte, rxBufferSize)
+	c.mu.Unlock()
+	rx.send("0" + "10000010" + "0" + "1")
+	for start := time.Now(); ; {
+		c.mu.Lock()
+		err := c.err
+		c.mu.Unlock()
+		if err != nil {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("expected overflow")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	r = make([]byte, rxBufferSize)
+	if n, err := c.Read(r); n != rxBufferSize || err != nil {
+		t.Fatal(n, err)
+	}
+	if _, err := c.Read(r); err != errOverflow {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_RX_Framing(t *testing.T) {
+	rx := newEventPin()
+	c := connect(t, rx, nil, uart.One, uart.NoParity, 8).(*serialConn)
+	rx.send("0" + "10000010" + "0")
+	r := make([]byte, 1)
+	if _, err := c.Read(r); err != errFraming {
+		t.Fatal(err)
+	}
+}
+
+func TestConn_Context(t *testing.T) {
+	rx := newEventPin()
+	tx := &outPin{Pin: gpiotest.Pin{N: "TX"}}
+	p, err := New(rx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := p.Connect(9600*physic.Hertz, uart.One, uart.NoParity, uart.NoFlow, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := c.(conn.ConnContext).TxContext(ctx, nil, make([]byte, 1)); err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+	// Close() unblocks the readers.
+	done := make(chan error)
+	go func() {
+		done <- c.Tx(nil, make([]byte, 1))
+	}()
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != errClosed {
+		t.Fatal(err)
+	}
+	if err := c.Tx([]byte{1}, nil); err != errClosed {
+		t.Fatal(err)
+	}
+}
+
+func TestPort(t *testing.T) {
+	if _, err := New(nil, nil); err == nil {
+		t.Fatal("a pin is required")
+	}
+	tx := &outPin{Pin: gpiotest.Pin{N: "TX"}}
+	p, err := New(nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := p.String(); s != "uartgpio(TX=TX(0))" {
+		t.Fatal(s)
+	}
+	if p.RX() != gpio.INVALID || p.TX() != tx || p.RTS() != gpio.INVALID || p.CTS() != gpio.INVALID {
+		t.Fatal("unexpected pins")
+	}
+	data := []struct {
+		f      physic.Frequency
+		stop   uart.Stop
+		parity uart.Parity
+		flow   uart.Flow
+		bits   int
+	}{
+		{-1, uart.One, uart.NoParity, uart.NoFlow, 8},
+		{0, uart.One, uart.NoParity, uart.NoFlow, 8},
+		{9600, 3, uart.NoParity, uart.NoFlow, 8},
+		{9600, uart.One, 'X', uart.NoFlow, 8},
+		{9600, uart.One, uart.NoParity, uart.RTSCTS, 8},
+		{9600, uart.One, uart.NoParity, uart.NoFlow, 4},
+		{9600, uart.One, uart.NoParity, uart.NoFlow, 9},
+	}
+	for i, line := range data {
+		if _, err := p.Connect(line.f, line.stop, line.parity, line.flow, line.bits); err == nil {
+			t.Fatalf("#%d: expected error", i)
+		}
+	}
+	if p.LimitSpeed(0) == nil {
+		t.Fatal("invalid speed")
+	}
+	if err := p.LimitSpeed(4800 * physic.Hertz); err != nil {
+		t.Fatal(err)
+	}
+	c, err := p.Connect(0, uart.One, uart.NoParity, uart.NoFlow, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := c.(*serialConn).f; f != 4800*physic.Hertz {
+		t.Fatal(f)
+	}
+	if _, err := p.Connect(9600, uart.One, uart.NoParity, uart.NoFlow, 8); err == nil {
+		t.Fatal("second Connect() must fail")
+	}
+	if c.String() != p.String() || c.Duplex() != conn.Full {
+		t.Fatal("unexpected conn")
+	}
+	if err := c.Tx(nil, make([]byte, 1)); err != errNoRX {
+		t.Fatal(err)
+	}
+	if n, err := c.(*serialConn).Write([]byte{0x55}); n != 1 || err != nil {
+		t.Fatal(n, err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Close() == nil {
+		t.Fatal("second Close() must fail")
+	}
+	if _, err := p.Connect(9600, uart.One, uart.NoParity, uart.NoFlow, 8); err == nil {
+		t.Fatal("closed")
+	}
+
+	p, err = New(newEventPin(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := p.String(); s != "uartgpio(RX=RX(0))" {
+		t.Fatal(s)
+	}
+	c, err = p.Connect(9600, uart.One, uart.NoParity, uart.NoFlow, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Tx([]byte{1}, nil); err != errNoTX {
+		t.Fatal(err)
+	}
+	pins := c.(uart.Pins)
+	if pins.RX() == gpio.INVALID || pins.TX() != gpio.INVALID || pins.RTS() != gpio.INVALID || pins.CTS() != gpio.INVALID {
+		t.Fatal("unexpected pins")
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//
+
+const bit = time.Second / 9600
+
+func connect(t *testing.T, rx gpio.PinIn, tx gpio.PinOut, stop uart.Stop, parity uart.Parity, bits int) conn.Conn {
+	p, err := New(rx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := p.Connect(9600*physic.Hertz, stop, parity, uart.NoFlow, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := p.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return c
+}
+
+// unpack returns the samples of a LSB first stream as '0' and '1'.
+func unpack(s *gpiostream.BitStream) string {
+	out := ""
+	for i := 0; i < 8*len(s.Bits); i++ {
+		if s.Bits[i/8]&(1<<uint(i%8)) != 0 {
+			out += "1"
+		} else {
+			out += "0"
+		}
+	}
+	return out
+}
+
+// streamPin records the streams.
+type streamPin struct {
+	gpiotest.Pin
+	streams []*gpiostream.BitStream
+}
+
+func (p *streamPin) StreamOut(s gpiostream.Stream) error {
+	p.streams = append(p.streams, s.(*gpiostream.BitStream))
+	return nil
+}
+
+// outPin records the levels written as '0' and '1'.
+type outPin struct {
+	gpiotest.Pin
+	mu     sync.Mutex
+	levels string
+}
+
+func (p *outPin) Out(l gpio.Level) error {
+	p.mu.Lock()
+	if l {
+		p.levels += "1"
+	} else {
+		p.levels += "0"
+	}
+	p.mu.Unlock()
+	return p.Pin.Out(l)
+}
+
+// eventPin implements gpio.PinEdgeEvents with edges sent by the test.
+type eventPin struct {
+	gpiotest.Pin
+	events chan gpio.EdgeEvent
+	// t is when the next frame starts.
+	t time.Time
+}
+
+func newEventPin() *eventPin {
+	return &eventPin{Pin: gpiotest.Pin{N: "RX", L: gpio.High}, events: make(chan gpio.EdgeEvent, 128), t: time.Now().Add(-time.Second)}
+}
+
+func (p *eventPin) In(pull gpio.Pull, edge gpio.Edge) error {
+	return nil
+}
+
+func (p *eventPin) WaitForEdgeEvent(timeout time.Duration) (gpio.EdgeEvent, bool) {
+	select {
+	case ev := <-p.events:
+		return ev, true
+	case <-time.After(timeout):
+		return gpio.EdgeEvent{}, false
+	}
+}
+
+// send sends the edges of the levels, one per bit, followed by an idle
+// line.
+//
+// The frames are in the past, so the receiver decodes them as fast as
+// possible.
+func (p *eventPin) send(levels string) {
+	last := gpio.High
+	for i := 0; i <= len(levels); i++ {
+		l := gpio.High
+		if i < len(levels) {
+			l = levels[i] == '1'
+		}
+		if l != last {
+			p.edge(p.t.Add(time.Duration(i)*bit), l)
+			last = l
+		}
+	}
+	p.t = p.t.Add(time.Duration(len(levels)+2) * bit)
+}
+
+// pulse sends a Low pulse lasting d.
+func (p *eventPin) pulse(d time.Duration) {
+	p.edge(p.t, gpio.Low)
+	p.edge(p.t.Add(d), gpio.High)
+	p.t = p.t.Add(2 * bit)
+}
+
+func (p *eventPin) edge(t time.Time, l gpio.Level) {
+	e := gpio.FallingEdge
+	if l {
+		e = gpio.RisingEdge
+	}
+	p.events <- gpio.EdgeEvent{Time: t, Edge: e, Level: l}
+}
+
+// waitRX waits for n characters to be buffered.
+func waitRX(t *testing.T, c *serialConn, n int) {
+	for start := time.Now(); ; {
+		c.mu.Lock()
+		l := len(c.buf)
+		c.mu.Unlock()
+		if l == n {
+			return
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("got %d characters", l)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}